@@ -0,0 +1,208 @@
+// file: apierror/apierror.go
+package apierror
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+
+	"github.com/fiber-mongo/starter-kit/logging" // Sesuaikan dengan path modulmu
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/recover"
+)
+
+// Kode error yang stabil dan bisa dipakai client untuk menangani error secara terprogram.
+// Pesan error boleh berubah, kode tidak.
+const (
+	CodeInvalidBody          = "INVALID_BODY"
+	CodeInvalidObjectID      = "INVALID_OBJECT_ID"
+	CodeValidationFailed     = "VALIDATION_FAILED"
+	CodeUnauthorized         = "UNAUTHORIZED"
+	CodeInvalidAPIKey        = "INVALID_API_KEY"
+	CodeForbidden            = "FORBIDDEN"
+	CodeCollectionNotEnabled = "COLLECTION_NOT_ENABLED"
+	CodeNotFound             = "NOT_FOUND"
+	CodeProjectNotFound      = "PROJECT_NOT_FOUND"
+	CodeCollectionNotFound   = "COLLECTION_NOT_FOUND"
+	CodeDocumentNotFound     = "DOCUMENT_NOT_FOUND"
+	CodeRevisionNotFound     = "REVISION_NOT_FOUND"
+	CodeWebhookNotFound      = "WEBHOOK_NOT_FOUND"
+	CodeDeliveryNotFound     = "DELIVERY_NOT_FOUND"
+	CodeHookNotFound         = "HOOK_NOT_FOUND"
+	CodeJobNotFound          = "JOB_NOT_FOUND"
+	CodeFileNotFound         = "FILE_NOT_FOUND"
+	CodeMethodNotAllowed     = "METHOD_NOT_ALLOWED"
+	CodeCollectionExists     = "COLLECTION_EXISTS"
+	CodeConflict             = "CONFLICT"
+	CodePreconditionFailed   = "PRECONDITION_FAILED"
+	CodeHookRejected         = "HOOK_REJECTED"
+	CodeUpgradeRequired      = "UPGRADE_REQUIRED"
+	CodeResumeTokenExpired   = "RESUME_TOKEN_EXPIRED"
+	CodeRateLimited          = "RATE_LIMITED"
+	CodeQuotaExceeded        = "QUOTA_EXCEEDED"
+	CodeStorageQuotaExceeded = "STORAGE_QUOTA_EXCEEDED"
+	CodeConnectionFailed     = "CONNECTION_FAILED"
+	CodeServiceUnavailable   = "SERVICE_UNAVAILABLE"
+	CodeInternal             = "INTERNAL_ERROR"
+)
+
+// Error adalah error API dengan status HTTP, kode stabil, pesan untuk client, dan (opsional) error per field.
+// Penyebab aslinya (misalnya error driver) hanya dicatat di log, tidak pernah dikirim ke client.
+type Error struct {
+	Status  int               `json:"-"`
+	Message string            `json:"error"`
+	Code    string            `json:"code"`
+	Fields  map[string]string `json:"fields,omitempty"`
+	cause   error
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.cause
+}
+
+// WithField mengembalikan salinan error dengan tambahan pesan untuk satu field input
+func (e *Error) WithField(field, message string) *Error {
+	clone := *e
+	clone.Fields = make(map[string]string, len(e.Fields)+1)
+	for k, v := range e.Fields {
+		clone.Fields[k] = v
+	}
+	clone.Fields[field] = message
+	return &clone
+}
+
+// Wrap mengembalikan salinan error dengan penyebab asli (dicatat di log untuk error 5xx)
+func (e *Error) Wrap(cause error) *Error {
+	clone := *e
+	clone.cause = cause
+	return &clone
+}
+
+// New membuat error API baru
+func New(status int, code, message string) *Error {
+	return &Error{Status: status, Code: code, Message: message}
+}
+
+// Newf seperti New dengan pesan berformat
+func Newf(status int, code, format string, args ...interface{}) *Error {
+	return New(status, code, fmt.Sprintf(format, args...))
+}
+
+// InvalidBody untuk body request yang tidak bisa dibaca
+func InvalidBody(message string) *Error {
+	return New(fiber.StatusBadRequest, CodeInvalidBody, message)
+}
+
+// InvalidObjectID untuk parameter ID yang bukan ObjectID valid, misalnya InvalidObjectID("Project")
+func InvalidObjectID(what string) *Error {
+	return Newf(fiber.StatusBadRequest, CodeInvalidObjectID, "Invalid %s ID format", what)
+}
+
+// Validation untuk input yang tidak valid. Jika field diisi, pesan juga dicantumkan di "fields".
+func Validation(field, message string) *Error {
+	err := New(fiber.StatusBadRequest, CodeValidationFailed, message)
+	if field != "" {
+		err = err.WithField(field, message)
+	}
+	return err
+}
+
+// NotFound untuk resource yang tidak ada, dengan kode spesifik resource-nya
+func NotFound(code, message string) *Error {
+	return New(fiber.StatusNotFound, code, message)
+}
+
+// ProjectNotFound dipakai semua handler yang mencari proyek berdasarkan ID
+func ProjectNotFound() *Error {
+	return NotFound(CodeProjectNotFound, "Project not found")
+}
+
+// Internal untuk error server; cause dicatat di log, client hanya menerima pesan umum
+func Internal(cause error, message string) *Error {
+	return New(fiber.StatusInternalServerError, CodeInternal, message).Wrap(cause)
+}
+
+// Status mengembalikan status HTTP yang akan dikirim untuk error ini (untuk middleware yang mencatat status)
+func Status(err error) int {
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		return apiErr.Status
+	}
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) {
+		return fiberErr.Code
+	}
+	return fiber.StatusInternalServerError
+}
+
+// From mengubah error apa pun menjadi *Error: *fiber.Error (misalnya 404 rute tidak ditemukan) dipetakan
+// ke kode sesuai statusnya, error lain dianggap error server.
+func From(err error) *Error {
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		return apiErr
+	}
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) {
+		return New(fiberErr.Code, codeForStatus(fiberErr.Code), fiberErr.Message).Wrap(err)
+	}
+	return Internal(err, "Internal server error")
+}
+
+// Fungsi helper untuk memilih kode dari status HTTP (untuk error yang tidak dibuat lewat package ini)
+func codeForStatus(status int) string {
+	switch status {
+	case fiber.StatusBadRequest, fiber.StatusRequestEntityTooLarge, fiber.StatusUnprocessableEntity:
+		return CodeValidationFailed
+	case fiber.StatusUnauthorized:
+		return CodeUnauthorized
+	case fiber.StatusForbidden:
+		return CodeForbidden
+	case fiber.StatusNotFound:
+		return CodeNotFound
+	case fiber.StatusMethodNotAllowed:
+		return CodeMethodNotAllowed
+	case fiber.StatusConflict:
+		return CodeConflict
+	case fiber.StatusPreconditionFailed:
+		return CodePreconditionFailed
+	case fiber.StatusTooManyRequests:
+		return CodeRateLimited
+	case fiber.StatusServiceUnavailable:
+		return CodeServiceUnavailable
+	}
+	return CodeInternal
+}
+
+// Body respons error: {"error": "...", "code": "...", "fields": {...}, "requestId": "..."}
+type body struct {
+	*Error
+	RequestID string `json:"requestId,omitempty"`
+}
+
+// Handler adalah ErrorHandler untuk fiber.Config. Semua error yang dikembalikan handler/middleware
+// (termasuk panic yang ditangkap middleware recover) dikirim dengan format yang sama.
+func Handler(c *fiber.Ctx, err error) error {
+	apiErr := From(err)
+	if apiErr.Status >= fiber.StatusInternalServerError {
+		slog.ErrorContext(c.UserContext(), apiErr.Message, "code", apiErr.Code, "error", errors.Unwrap(apiErr), "path", c.Path())
+	}
+	return c.Status(apiErr.Status).JSON(body{Error: apiErr, RequestID: logging.RequestID(c)})
+}
+
+// Recover mengubah panic di handler menjadi error 500 biasa (diproses Handler dengan format yang sama)
+// dan mencatat stack trace-nya di log
+func Recover() fiber.Handler {
+	return recover.New(recover.Config{
+		EnableStackTrace: true,
+		StackTraceHandler: func(c *fiber.Ctx, e interface{}) {
+			slog.ErrorContext(c.UserContext(), "panic recovered", "panic", fmt.Sprint(e), "path", c.Path(), "stack", string(debug.Stack()))
+		},
+	})
+}
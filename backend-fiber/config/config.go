@@ -1,19 +1,313 @@
 package config
 
 import (
-	"log"
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
 
+	"github.com/BurntSushi/toml"
 	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
 )
 
-// Fungsi untuk mengambil nilai dari file .env berdasarkan key
-func Env(key string) string {
-	// Muat file .env
-	err := godotenv.Load(".env")
+// File konfigurasi yang dibaca jika ada (yang pertama ditemukan); lokasi lain bisa dipilih lewat CONFIG_FILE
+var defaultFiles = []string{"config.yaml", "config.toml"}
+
+// Konfigurasi aplikasi. Urutan prioritas: environment variable (termasuk .env) > file konfigurasi > default.
+type Config struct {
+	Port            string        `yaml:"port" toml:"port"`
+	MongoURI        string        `yaml:"mongoUri" toml:"mongoUri"`
+	DBName          string        `yaml:"dbName" toml:"dbName"`
+	LogLevel        string        `yaml:"logLevel" toml:"logLevel"`
+	TracesExporter  string        `yaml:"tracesExporter" toml:"tracesExporter"`
+	CORSOrigins     []string      `yaml:"corsOrigins" toml:"corsOrigins"`
+	UploadDir       string        `yaml:"uploadDir" toml:"uploadDir"`
+	BodyLimit       ByteSize      `yaml:"bodyLimit" toml:"bodyLimit"`
+	ReadTimeout     time.Duration `yaml:"readTimeout" toml:"readTimeout"`
+	WriteTimeout    time.Duration `yaml:"writeTimeout" toml:"writeTimeout"`
+	IdleTimeout     time.Duration `yaml:"idleTimeout" toml:"idleTimeout"`
+	DBTimeout       time.Duration `yaml:"dbTimeout" toml:"dbTimeout"`
+	ShutdownTimeout time.Duration `yaml:"shutdownTimeout" toml:"shutdownTimeout"` // Batas waktu menunggu request, job dan webhook saat server dimatikan
+	EncryptionKey   string        `yaml:"encryptionKey" toml:"encryptionKey"`     // Kunci AES-256 (base64, 32 byte) untuk password database, connection string dan sertifikat proyek
+}
+
+// Ukuran dalam byte; di file dan env bisa ditulis sebagai angka atau dengan satuan (KB, MB, GB)
+type ByteSize int
+
+func (b *ByteSize) UnmarshalYAML(value *yaml.Node) error {
+	size, err := parseByteSize(value.Value)
+	if err != nil {
+		return err
+	}
+	*b = size
+	return nil
+}
+
+func (b *ByteSize) UnmarshalTOML(value interface{}) error {
+	var size ByteSize
+	var err error
+	switch v := value.(type) {
+	case int64:
+		size, err = parseByteSize(strconv.FormatInt(v, 10))
+	case string:
+		size, err = parseByteSize(v)
+	default:
+		err = fmt.Errorf("invalid size %v", value)
+	}
+	if err != nil {
+		return err
+	}
+	*b = size
+	return nil
+}
+
+var current atomic.Pointer[Config]
+
+// Default mengembalikan konfigurasi bawaan (tanpa MONGO_URI, yang wajib diisi)
+func Default() *Config {
+	return &Config{
+		Port:           "8080",
+		DBName:         "fiber_starter",
+		LogLevel:       "info",
+		TracesExporter: "none",
+		CORSOrigins:    []string{"http://localhost:3000"}, // Frontend development server
+		UploadDir:      "./public/uploads",
+		BodyLimit:      4 * 1024 * 1024,
+		ReadTimeout:    30 * time.Second,
+		// 0 berarti tanpa batas; stream realtime dan export audit bisa berjalan lama
+		WriteTimeout:    0,
+		IdleTimeout:     120 * time.Second,
+		DBTimeout:       10 * time.Second,
+		ShutdownTimeout: 30 * time.Second,
+	}
+}
+
+// Load membaca konfigurasi sekali saat startup lalu menyimpannya untuk Get.
+// File .env dan config.yaml/config.toml bersifat opsional; CONFIG_FILE yang diisi tapi tidak ada dianggap error.
+func Load() (*Config, error) {
+	if err := godotenv.Load(".env"); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read .env: %w", err)
+	}
+
+	cfg := Default()
+	path, required := os.LookupEnv("CONFIG_FILE")
+	if !required || path == "" {
+		path, required = defaultFile(), false
+	}
+	if err := cfg.readFile(path, required); err != nil {
+		return nil, err
+	}
+	if err := cfg.applyEnv(os.LookupEnv); err != nil {
+		return nil, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	Set(cfg)
+	return cfg, nil
+}
+
+// Set mengganti konfigurasi aktif (dipakai Load dan test)
+func Set(cfg *Config) {
+	current.Store(cfg)
+}
+
+// Get mengembalikan konfigurasi aktif; sebelum Load dipanggil nilainya adalah Default()
+func Get() *Config {
+	if cfg := current.Load(); cfg != nil {
+		return cfg
+	}
+	return Default()
+}
+
+// Fungsi helper untuk memilih file konfigurasi default yang ada (config.yaml jika tidak ada satu pun)
+func defaultFile() string {
+	for _, name := range defaultFiles {
+		if _, err := os.Stat(name); err == nil {
+			return name
+		}
+	}
+	return defaultFiles[0]
+}
+
+// Fungsi helper untuk membaca file YAML (.yaml/.yml) atau TOML (.toml) di atas nilai default.
+// Key yang tidak dikenal dianggap error supaya salah ketik tidak diam-diam diabaikan.
+func (c *Config) readFile(path string, required bool) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) && !required {
+		return nil
+	}
 	if err != nil {
-		log.Fatalf("Error loading .env file")
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+			return fmt.Errorf("invalid config file %s: %w", path, err)
+		}
+	case ".toml":
+		meta, err := toml.NewDecoder(bytes.NewReader(data)).Decode(c)
+		if err != nil {
+			return fmt.Errorf("invalid config file %s: %w", path, err)
+		}
+		if undecoded := meta.Undecoded(); len(undecoded) > 0 {
+			return fmt.Errorf("invalid config file %s: unknown field %q", path, undecoded[0].String())
+		}
+	default:
+		return fmt.Errorf("unsupported config file %q (use .yaml, .yml or .toml)", path)
+	}
+	return nil
+}
+
+// Environment variable yang dikenali beserta field yang diisinya
+var envFields = []struct {
+	key   string
+	apply func(c *Config, value string) error
+}{
+	{"PORT", func(c *Config, v string) error { c.Port = v; return nil }},
+	{"MONGO_URI", func(c *Config, v string) error { c.MongoURI = v; return nil }},
+	{"DB_NAME", func(c *Config, v string) error { c.DBName = v; return nil }},
+	{"LOG_LEVEL", func(c *Config, v string) error { c.LogLevel = v; return nil }},
+	{"OTEL_TRACES_EXPORTER", func(c *Config, v string) error { c.TracesExporter = v; return nil }},
+	{"CORS_ALLOW_ORIGINS", func(c *Config, v string) error { c.CORSOrigins = splitList(v); return nil }},
+	{"UPLOAD_DIR", func(c *Config, v string) error { c.UploadDir = v; return nil }},
+	{"BODY_LIMIT", func(c *Config, v string) (err error) { c.BodyLimit, err = parseByteSize(v); return }},
+	{"READ_TIMEOUT", func(c *Config, v string) (err error) { c.ReadTimeout, err = time.ParseDuration(v); return }},
+	{"WRITE_TIMEOUT", func(c *Config, v string) (err error) { c.WriteTimeout, err = time.ParseDuration(v); return }},
+	{"IDLE_TIMEOUT", func(c *Config, v string) (err error) { c.IdleTimeout, err = time.ParseDuration(v); return }},
+	{"DB_TIMEOUT", func(c *Config, v string) (err error) { c.DBTimeout, err = time.ParseDuration(v); return }},
+	{"ENCRYPTION_KEY", func(c *Config, v string) error { c.EncryptionKey = v; return nil }},
+	{"SHUTDOWN_TIMEOUT", func(c *Config, v string) (err error) { c.ShutdownTimeout, err = time.ParseDuration(v); return }},
+}
+
+// Fungsi helper untuk menimpa nilai dengan environment variable yang tidak kosong
+func (c *Config) applyEnv(lookup func(string) (string, bool)) error {
+	for _, field := range envFields {
+		value, ok := lookup(field.key)
+		value = strings.TrimSpace(value)
+		if !ok || value == "" {
+			continue
+		}
+		if err := field.apply(c, value); err != nil {
+			return fmt.Errorf("invalid %s: %w", field.key, err)
+		}
+	}
+	return nil
+}
+
+// Validate memeriksa semua nilai dan mengembalikan seluruh kesalahan sekaligus
+func (c *Config) Validate() error {
+	var errs []error
+
+	if port, err := strconv.Atoi(c.Port); err != nil || port < 1 || port > 65535 {
+		errs = append(errs, fmt.Errorf("port must be a number between 1 and 65535, got %q", c.Port))
+	}
+	if c.MongoURI == "" {
+		errs = append(errs, errors.New("MONGO_URI is required"))
+	} else if !strings.HasPrefix(c.MongoURI, "mongodb://") && !strings.HasPrefix(c.MongoURI, "mongodb+srv://") {
+		errs = append(errs, errors.New("MONGO_URI must start with mongodb:// or mongodb+srv://"))
+	}
+	if strings.TrimSpace(c.DBName) == "" {
+		errs = append(errs, errors.New("database name must not be empty"))
+	}
+	switch strings.ToLower(c.LogLevel) {
+	case "", "debug", "info", "warn", "warning", "error":
+	default:
+		errs = append(errs, fmt.Errorf("unknown log level %q (use debug, info, warn or error)", c.LogLevel))
+	}
+	switch strings.ToLower(c.TracesExporter) {
+	case "", "none", "otlp", "stdout":
+	default:
+		errs = append(errs, fmt.Errorf("unknown trace exporter %q (use otlp, stdout or none)", c.TracesExporter))
+	}
+	if len(c.CORSOrigins) == 0 {
+		errs = append(errs, errors.New("at least one CORS origin is required"))
+	}
+	for _, origin := range c.CORSOrigins {
+		if origin == "*" {
+			continue
+		}
+		if u, err := url.Parse(origin); err != nil || u.Scheme == "" || u.Host == "" || (u.Path != "" && u.Path != "/") {
+			errs = append(errs, fmt.Errorf("invalid CORS origin %q (use scheme://host[:port] or *)", origin))
+		}
+	}
+	if strings.TrimSpace(c.UploadDir) == "" {
+		errs = append(errs, errors.New("upload directory must not be empty"))
+	}
+	if c.BodyLimit <= 0 {
+		errs = append(errs, errors.New("body limit must be greater than 0"))
+	}
+	if c.ReadTimeout < 0 || c.WriteTimeout < 0 || c.IdleTimeout < 0 {
+		errs = append(errs, errors.New("server timeouts must not be negative"))
+	}
+	if c.DBTimeout <= 0 {
+		errs = append(errs, errors.New("database timeout must be greater than 0"))
+	}
+	if c.ShutdownTimeout <= 0 {
+		errs = append(errs, errors.New("shutdown timeout must be greater than 0"))
+	}
+	if c.EncryptionKey != "" {
+		if _, err := c.EncryptionKeyBytes(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// EncryptionKeyBytes mengembalikan kunci enkripsi (kosong jika tidak dikonfigurasi)
+func (c *Config) EncryptionKeyBytes() ([]byte, error) {
+	if c.EncryptionKey == "" {
+		return nil, nil
+	}
+	key, err := base64.StdEncoding.DecodeString(c.EncryptionKey)
+	if err != nil || len(key) != 32 {
+		return nil, errors.New("ENCRYPTION_KEY must be 32 bytes encoded as base64 (openssl rand -base64 32)")
+	}
+	return key, nil
+}
+
+// Fungsi helper untuk memecah daftar yang dipisahkan koma
+func splitList(value string) []string {
+	items := []string{}
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// Fungsi helper untuk membaca ukuran seperti "4194304", "512KB" atau "4MB"
+func parseByteSize(value string) (ByteSize, error) {
+	s := strings.ToUpper(strings.TrimSpace(value))
+	multiplier := 1
+	for _, unit := range []struct {
+		suffix string
+		size   int
+	}{{"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10}, {"B", 1}} {
+		if strings.HasSuffix(s, unit.suffix) {
+			s, multiplier = strings.TrimSpace(strings.TrimSuffix(s, unit.suffix)), unit.size
+			break
+		}
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid size %q", value)
 	}
-	// Kembalikan nilai dari environment variable
-	return os.Getenv(key)
-}
\ No newline at end of file
+	return ByteSize(n * multiplier), nil
+}
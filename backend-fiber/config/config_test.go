@@ -0,0 +1,135 @@
+// file: config/config_test.go
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Fungsi helper untuk menjalankan Load di direktori sementara dengan environment yang terkendali
+func loadIn(t *testing.T, files map[string]string, env map[string]string) (*Config, error) {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+
+	// t.Setenv memulihkan nilai lama setelah test; Unsetenv supaya .env tidak dianggap sudah diisi
+	for _, field := range envFields {
+		t.Setenv(field.key, "")
+		os.Unsetenv(field.key)
+	}
+	t.Setenv("CONFIG_FILE", "")
+	for k, v := range env {
+		t.Setenv(k, v)
+	}
+	t.Cleanup(func() { Set(nil) })
+	return Load()
+}
+
+func TestLoadDefaultsWithoutFiles(t *testing.T) {
+	cfg, err := loadIn(t, nil, map[string]string{"MONGO_URI": "mongodb://localhost:27017"})
+	if err != nil {
+		t.Fatalf("Load without .env or config file: %v", err)
+	}
+	want := Default()
+	if cfg.Port != want.Port || cfg.DBName != want.DBName || cfg.BodyLimit != want.BodyLimit || cfg.UploadDir != want.UploadDir {
+		t.Fatalf("cfg = %+v, want defaults", cfg)
+	}
+	if Get() != cfg {
+		t.Fatal("Get does not return the loaded config")
+	}
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	cfg, err := loadIn(t, map[string]string{
+		"config.yaml": "port: \"9000\"\nmongoUri: mongodb://file:27017\ndbName: fromfile\nbodyLimit: 8MB\nreadTimeout: 5s\ncorsOrigins: [\"https://a.example\"]\n",
+		".env":        "DB_NAME=fromdotenv\n",
+	}, map[string]string{"PORT": "9100", "CORS_ALLOW_ORIGINS": "https://b.example, https://c.example"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.Port != "9100" {
+		t.Errorf("Port = %q, environment should override the file", cfg.Port)
+	}
+	if cfg.DBName != "fromdotenv" {
+		t.Errorf("DBName = %q, .env should override the file", cfg.DBName)
+	}
+	if cfg.MongoURI != "mongodb://file:27017" || cfg.BodyLimit != 8<<20 || cfg.ReadTimeout != 5*time.Second {
+		t.Errorf("file values not applied: %+v", cfg)
+	}
+	if strings.Join(cfg.CORSOrigins, " ") != "https://b.example https://c.example" {
+		t.Errorf("CORSOrigins = %v", cfg.CORSOrigins)
+	}
+}
+
+func TestLoadTOML(t *testing.T) {
+	toml := "port = \"9000\"\nmongoUri = \"mongodb://file:27017\"\nbodyLimit = \"8MB\"\nreadTimeout = \"5s\"\ncorsOrigins = [\"https://a.example\"]\n"
+
+	cfg, err := loadIn(t, map[string]string{"config.toml": toml}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Port != "9000" || cfg.MongoURI != "mongodb://file:27017" || cfg.BodyLimit != 8<<20 || cfg.ReadTimeout != 5*time.Second {
+		t.Errorf("config.toml values not applied: %+v", cfg)
+	}
+	if strings.Join(cfg.CORSOrigins, " ") != "https://a.example" {
+		t.Errorf("CORSOrigins = %v", cfg.CORSOrigins)
+	}
+
+	// config.yaml didahulukan jika keduanya ada
+	cfg, err = loadIn(t, map[string]string{"config.toml": toml, "config.yaml": "mongoUri: mongodb://yaml:27017\n"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.MongoURI != "mongodb://yaml:27017" {
+		t.Errorf("MongoURI = %q, config.yaml should be preferred", cfg.MongoURI)
+	}
+
+	cfg, err = loadIn(t, map[string]string{"app.toml": "mongoUri = \"mongodb://x\"\nbodyLimit = 1024\n"}, map[string]string{"CONFIG_FILE": "app.toml"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.BodyLimit != 1024 {
+		t.Errorf("BodyLimit = %d, want 1024", cfg.BodyLimit)
+	}
+}
+
+func TestLoadValidation(t *testing.T) {
+	_, err := loadIn(t, nil, map[string]string{"PORT": "http", "CORS_ALLOW_ORIGINS": "localhost:3000"})
+	if err == nil {
+		t.Fatal("expected validation error")
+	}
+	for _, want := range []string{"port", "MONGO_URI", "CORS origin"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %s", err, want)
+		}
+	}
+
+	if _, err := loadIn(t, nil, map[string]string{"MONGO_URI": "mongodb://x", "BODY_LIMIT": "lots"}); err == nil || !strings.Contains(err.Error(), "BODY_LIMIT") {
+		t.Errorf("err = %v, want invalid BODY_LIMIT", err)
+	}
+	if _, err := loadIn(t, nil, map[string]string{"MONGO_URI": "mongodb://x", "CONFIG_FILE": "missing.yaml"}); err == nil {
+		t.Error("explicit CONFIG_FILE that does not exist should fail")
+	}
+	if _, err := loadIn(t, map[string]string{"config.yaml": "prot: 1\n"}, map[string]string{"MONGO_URI": "mongodb://x"}); err == nil {
+		t.Error("unknown key in config file should fail")
+	}
+	if _, err := loadIn(t, map[string]string{"config.toml": "prot = 1\n"}, map[string]string{"MONGO_URI": "mongodb://x"}); err == nil {
+		t.Error("unknown key in TOML config file should fail")
+	}
+}
@@ -0,0 +1,227 @@
+// file: controllers/audit_controller.go
+package controllers
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fiber-mongo/starter-kit/apierror" // Sesuaikan dengan nama modul Anda
+	"github.com/fiber-mongo/starter-kit/database" // Sesuaikan dengan nama modul Anda
+	"github.com/fiber-mongo/starter-kit/models"   // Sesuaikan dengan nama modul Anda
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	defaultAuditLimit = 50
+	maxAuditLimit     = 500
+	maxAuditExport    = 100000
+	auditExportWindow = 2 * time.Minute
+)
+
+// Fungsi helper untuk membaca waktu dari query (RFC 3339 atau tanggal YYYY-MM-DD)
+func parseQueryTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", value)
+}
+
+// Fungsi helper untuk menyusun filter audit log dari query: projectId, actor, action, from, to
+func auditFilter(c *fiber.Ctx) (bson.M, error) {
+	filter := bson.M{}
+	if projectID := c.Query("projectId"); projectID != "" {
+		objID, err := primitive.ObjectIDFromHex(projectID)
+		if err != nil {
+			return nil, errors.New("Invalid Project ID format")
+		}
+		filter["projectId"] = objID
+	}
+	if actor := c.Query("actor"); actor != "" {
+		filter["actor"] = actor
+	}
+	if action := c.Query("action"); action != "" {
+		filter["action"] = action
+	}
+
+	timestamp := bson.M{}
+	if from := c.Query("from"); from != "" {
+		t, err := parseQueryTime(from)
+		if err != nil {
+			return nil, errors.New("Invalid 'from' date, use RFC 3339 or YYYY-MM-DD")
+		}
+		timestamp["$gte"] = t
+	}
+	if to := c.Query("to"); to != "" {
+		t, err := parseQueryTime(to)
+		if err != nil {
+			return nil, errors.New("Invalid 'to' date, use RFC 3339 or YYYY-MM-DD")
+		}
+		// Tanggal tanpa jam berarti sampai akhir hari tersebut
+		if len(to) == len("2006-01-02") {
+			t = t.Add(24 * time.Hour)
+		}
+		timestamp["$lt"] = t
+	}
+	if len(timestamp) > 0 {
+		filter["timestamp"] = timestamp
+	}
+	return filter, nil
+}
+
+// Handler untuk GET /audit-logs?projectId=&actor=&action=&from=&to=&limit=&skip=
+// (Entri terbaru dulu; jumlah total dikirim di header X-Total-Count)
+// Filter actor mencocokkan header X-User-ID yang dicatat tanpa verifikasi (lihat models.AuditLog).
+func GetAuditLogs(c *fiber.Ctx) error {
+	auditCollection := database.GetCollection("audit_logs")
+	ctx, cancel := context.WithTimeout(c.UserContext(), 15*time.Second)
+	defer cancel()
+
+	filter, err := auditFilter(c)
+	if err != nil {
+		return apierror.Validation("", err.Error())
+	}
+	limit := c.QueryInt("limit", defaultAuditLimit)
+	if limit <= 0 || limit > maxAuditLimit {
+		return apierror.Validation("limit", fmt.Sprintf("limit must be between 1 and %d", maxAuditLimit))
+	}
+	skip := c.QueryInt("skip", 0)
+	if skip < 0 {
+		return apierror.Validation("skip", "skip must not be negative")
+	}
+
+	total, err := auditCollection.CountDocuments(ctx, filter)
+	if err != nil {
+		return apierror.Internal(err, "Failed to count audit logs")
+	}
+	cursor, err := auditCollection.Find(ctx, filter,
+		options.Find().SetSort(bson.M{"timestamp": -1}).SetLimit(int64(limit)).SetSkip(int64(skip)))
+	if err != nil {
+		return apierror.Internal(err, "Failed to fetch audit logs")
+	}
+	entries := []models.AuditLog{}
+	if err := cursor.All(ctx, &entries); err != nil {
+		return apierror.Internal(err, "Failed to decode audit logs")
+	}
+
+	c.Set("X-Total-Count", strconv.FormatInt(total, 10))
+	return c.Status(fiber.StatusOK).JSON(entries)
+}
+
+var auditCSVHeader = []string{
+	"timestamp", "actor", "actorVerified", "action", "projectId", "targetType", "targetId",
+	"method", "path", "ip", "userAgent", "requestId", "outcome", "statusCode", "error", "durationMs",
+}
+
+func auditCSVRecord(entry models.AuditLog) []string {
+	projectID := ""
+	if entry.ProjectID != nil {
+		projectID = entry.ProjectID.Hex()
+	}
+	record := []string{
+		entry.Timestamp.UTC().Format(time.RFC3339), entry.Actor, strconv.FormatBool(entry.ActorVerified), entry.Action, projectID, entry.Target.Type, entry.Target.ID,
+		entry.Request.Method, entry.Request.Path, entry.Request.IP, entry.Request.UserAgent, entry.Request.RequestID,
+		entry.Outcome, strconv.Itoa(entry.StatusCode), entry.Error, strconv.FormatInt(entry.DurationMs, 10),
+	}
+	for i, cell := range record {
+		record[i] = csvSafe(cell)
+	}
+	return record
+}
+
+// Fungsi helper untuk mencegah CSV/formula injection: sel yang diawali =, +, - atau @ (atau tab/CR)
+// akan dijalankan sebagai formula oleh spreadsheet, jadi diberi awalan ' supaya dibaca sebagai teks.
+// Actor, path dan user agent berasal dari client sehingga bisa berisi formula.
+func csvSafe(cell string) string {
+	if cell != "" && strings.ContainsRune("=+-@\t\r", rune(cell[0])) {
+		return "'" + cell
+	}
+	return cell
+}
+
+// Handler untuk GET /audit-logs/export?format=csv|ndjson (filter sama dengan GetAuditLogs, urut dari yang terlama).
+// Export dibatasi maxAuditExport entri; filter yang menghasilkan lebih dari itu ditolak dengan 400.
+func ExportAuditLogs(c *fiber.Ctx) error {
+	filter, err := auditFilter(c)
+	if err != nil {
+		return apierror.Validation("", err.Error())
+	}
+	format := c.Query("format", "csv")
+	if format != "csv" && format != "ndjson" {
+		return apierror.Validation("format", "format must be 'csv' or 'ndjson'")
+	}
+
+	// Query dijalankan sebelum header dikirim, supaya filter yang terlalu luas atau kegagalan database
+	// dilaporkan sebagai error biasa, bukan file export kosong/terpotong dengan status 200
+	ctx, cancel := context.WithTimeout(context.Background(), auditExportWindow)
+	auditLogs := database.GetCollection("audit_logs")
+	total, err := auditLogs.CountDocuments(ctx, filter, options.Count().SetLimit(maxAuditExport+1))
+	if err != nil {
+		cancel()
+		return apierror.Internal(err, "Failed to count audit logs")
+	}
+	if total > maxAuditExport {
+		cancel()
+		return apierror.Validation("", fmt.Sprintf("Export is limited to %d entries; narrow the time range with 'from' and 'to'", maxAuditExport))
+	}
+	cursor, err := auditLogs.Find(ctx, filter, options.Find().SetSort(bson.M{"timestamp": 1}))
+	if err != nil {
+		cancel()
+		return apierror.Internal(err, "Failed to fetch audit logs")
+	}
+
+	filename := "audit-logs-" + time.Now().UTC().Format("20060102-150405") + "." + format
+	if format == "csv" {
+		c.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+	} else {
+		c.Set(fiber.HeaderContentType, "application/x-ndjson")
+	}
+	c.Set(fiber.HeaderContentDisposition, `attachment; filename="`+filename+`"`)
+
+	// Data dialirkan langsung dari cursor supaya export besar tidak ditampung di memori
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer cancel()
+		defer cursor.Close(ctx)
+
+		csvWriter := csv.NewWriter(w)
+		encoder := json.NewEncoder(w)
+		if format == "csv" {
+			csvWriter.Write(auditCSVHeader)
+		}
+		for rows := 1; cursor.Next(ctx); rows++ {
+			var entry models.AuditLog
+			if err := cursor.Decode(&entry); err != nil {
+				slog.ErrorContext(ctx, "audit: export decode failed", "error", err)
+				return
+			}
+			if format == "csv" {
+				csvWriter.Write(auditCSVRecord(entry))
+				csvWriter.Flush()
+			} else {
+				encoder.Encode(entry)
+			}
+			if rows%500 == 0 {
+				if err := w.Flush(); err != nil {
+					return // Client memutus koneksi
+				}
+			}
+		}
+		csvWriter.Flush()
+		// Header sudah terkirim, jadi kegagalan di tengah jalan hanya bisa dicatat di log
+		if err := cursor.Err(); err != nil {
+			slog.ErrorContext(ctx, "audit: export cursor failed", "error", err)
+		}
+	})
+	return nil
+}
@@ -0,0 +1,40 @@
+// file: controllers/audit_controller_test.go
+package controllers
+
+import (
+	"testing"
+
+	"github.com/fiber-mongo/starter-kit/models" // Sesuaikan dengan nama modul Anda
+)
+
+func TestAuditCSVRecordEscapesFormulas(t *testing.T) {
+	entry := models.AuditLog{
+		Actor:   "=HYPERLINK(\"http://evil.example\")",
+		Action:  "project.delete",
+		Request: models.AuditRequest{Method: "DELETE", Path: "/api/v1/projects/x", UserAgent: "@SUM(1+1)", IP: "-1+1"},
+		Error:   "+cmd",
+	}
+	record := auditCSVRecord(entry)
+	if len(record) != len(auditCSVHeader) {
+		t.Fatalf("record has %d cells, header has %d", len(record), len(auditCSVHeader))
+	}
+
+	cells := map[string]string{}
+	for i, name := range auditCSVHeader {
+		cells[name] = record[i]
+	}
+	want := map[string]string{
+		"actor":         "'=HYPERLINK(\"http://evil.example\")",
+		"actorVerified": "false",
+		"action":        "project.delete",
+		"path":          "/api/v1/projects/x",
+		"userAgent":     "'@SUM(1+1)",
+		"ip":            "'-1+1",
+		"error":         "'+cmd",
+	}
+	for name, value := range want {
+		if cells[name] != value {
+			t.Errorf("%s = %q, want %q", name, cells[name], value)
+		}
+	}
+}
@@ -0,0 +1,235 @@
+// file: controllers/collection_info.go
+package controllers
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/fiber-mongo/starter-kit/models" // Sesuaikan dengan nama modul Anda
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Opsi koleksi seperti yang dikembalikan oleh perintah listCollections
+type collectionOptions struct {
+	Validator        bson.Raw `bson:"validator"`
+	ValidationLevel  string   `bson:"validationLevel"`
+	ValidationAction string   `bson:"validationAction"`
+	Capped           bool     `bson:"capped"`
+	Size             int64    `bson:"size"`
+	Max              int64    `bson:"max"`
+	Timeseries       bson.M   `bson:"timeseries"`
+}
+
+// Statistik penyimpanan dari tahap $collStats
+type collectionStorageStats struct {
+	StorageStats struct {
+		Count          int64   `bson:"count"`
+		Size           int64   `bson:"size"`
+		StorageSize    int64   `bson:"storageSize"`
+		AvgObjSize     float64 `bson:"avgObjSize"`
+		NIndexes       int64   `bson:"nindexes"`
+		TotalIndexSize int64   `bson:"totalIndexSize"`
+	} `bson:"storageStats"`
+}
+
+// Fungsi helper untuk mengambil $jsonSchema dari sebuah validator
+func jsonSchemaFromValidator(validator bson.Raw) bson.M {
+	schemaDoc, ok := validator.Lookup("$jsonSchema").DocumentOK()
+	if !ok {
+		return nil
+	}
+	var schema bson.M
+	if err := bson.Unmarshal(schemaDoc, &schema); err != nil {
+		return nil
+	}
+	return schema
+}
+
+// Tipe bsonType yang tersedia di schema builder frontend
+var schemaBuilderTypes = map[string]bool{
+	"string": true, "double": true, "int": true, "long": true, "decimal": true,
+	"bool": true, "objectId": true, "date": true, "array": true, "object": true,
+}
+
+// Fungsi helper untuk mengubah $jsonSchema menjadi format field/type/required yang dipakai schema builder di frontend.
+// Urutan field mengikuti urutan "properties" di validator. Nilai kedua false jika validator memakai hal yang tidak
+// bisa disimpan ulang oleh schema builder (properti bersarang, enum, batas nilai, tipe nullable, dan sebagainya);
+// field tetap dikembalikan sebagai gambaran, tapi schema harus diedit sebagai JSON.
+func schemaFieldsFromValidator(validator bson.Raw) ([]models.SchemaField, bool) {
+	fields := []models.SchemaField{}
+	if len(validator) == 0 {
+		return fields, true
+	}
+	elements, _ := validator.Elements()
+	if len(elements) != 1 {
+		return fields, false
+	}
+	schemaDoc, ok := validator.Lookup("$jsonSchema").DocumentOK()
+	if !ok {
+		return fields, false
+	}
+
+	editable := true
+	schemaElements, _ := schemaDoc.Elements()
+	for _, elem := range schemaElements {
+		switch elem.Key() {
+		case "properties", "required":
+		case "bsonType":
+			if t, _ := elem.Value().StringValueOK(); t != "object" {
+				editable = false
+			}
+		default:
+			editable = false
+		}
+	}
+
+	required := map[string]bool{}
+	if requiredArr, ok := schemaDoc.Lookup("required").ArrayOK(); ok {
+		values, _ := requiredArr.Values()
+		for _, v := range values {
+			if name, ok := v.StringValueOK(); ok {
+				required[name] = true
+			}
+		}
+	}
+
+	properties, _ := schemaDoc.Lookup("properties").DocumentOK()
+	propertyElements, _ := properties.Elements()
+	for _, elem := range propertyElements {
+		field := models.SchemaField{Name: elem.Key(), Required: required[elem.Key()]}
+		def, _ := elem.Value().DocumentOK()
+		var simple bool
+		field.Type, simple = simpleFieldType(def)
+		editable = editable && simple
+		delete(required, elem.Key())
+		fields = append(fields, field)
+	}
+	// Field wajib yang tidak punya definisi di "properties" akan hilang jika disimpan lewat schema builder
+	if len(required) > 0 {
+		editable = false
+	}
+	return fields, editable
+}
+
+// Fungsi helper untuk membaca tipe sebuah definisi properti $jsonSchema. Nilai kedua true hanya jika definisinya
+// berisi satu bsonType yang tersedia di schema builder, tanpa keyword lain.
+func simpleFieldType(def bson.Raw) (string, bool) {
+	elements, _ := def.Elements()
+	if bsonType, ok := def.Lookup("bsonType").StringValueOK(); ok {
+		return bsonType, len(elements) == 1 && schemaBuilderTypes[bsonType]
+	}
+	// Tipe majemuk (misalnya ["string", "null"]) dan keyword "type" milik JSON Schema standar
+	// hanya ditampilkan apa adanya
+	if bsonTypes, ok := def.Lookup("bsonType").ArrayOK(); ok {
+		values, _ := bsonTypes.Values()
+		names := make([]string, 0, len(values))
+		for _, v := range values {
+			if t, ok := v.StringValueOK(); ok {
+				names = append(names, t)
+			}
+		}
+		return strings.Join(names, "|"), false
+	}
+	t, _ := def.Lookup("type").StringValueOK()
+	return t, false
+}
+
+// Fungsi helper untuk membaca opsi satu koleksi. Mengembalikan mongo.ErrNoDocuments jika koleksi tidak ada.
+func readCollectionOptions(ctx context.Context, db *mongo.Database, collectionName string) (collectionOptions, error) {
+	var opts collectionOptions
+	specs, err := db.ListCollectionSpecifications(ctx, bson.M{"name": collectionName})
+	if err != nil {
+		return opts, err
+	}
+	if len(specs) == 0 {
+		return opts, mongo.ErrNoDocuments
+	}
+	if len(specs[0].Options) > 0 {
+		if err := bson.Unmarshal(specs[0].Options, &opts); err != nil {
+			return opts, err
+		}
+	}
+	return opts, nil
+}
+
+// Fungsi helper untuk memvalidasi validationLevel dan validationAction dari request
+func validateValidationSettings(level, action string) error {
+	switch level {
+	case "", "off", "strict", "moderate":
+	default:
+		return errors.New("validationLevel must be one of 'off', 'strict' or 'moderate'")
+	}
+	switch action {
+	case "", "error", "warn":
+	default:
+		return errors.New("validationAction must be one of 'error' or 'warn'")
+	}
+	return nil
+}
+
+// Fungsi helper untuk membaca metadata dan statistik semua koleksi di database user
+func readCollectionInfos(ctx context.Context, db *mongo.Database, activeCollections []string) ([]models.CollectionInfo, error) {
+	specs, err := db.ListCollectionSpecifications(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+
+	active := make(map[string]bool, len(activeCollections))
+	for _, name := range activeCollections {
+		active[name] = true
+	}
+
+	infos := make([]models.CollectionInfo, 0, len(specs))
+	for _, spec := range specs {
+		// Koleksi revisi dikelola oleh platform dan tidak ditampilkan sebagai koleksi user
+		if strings.HasSuffix(spec.Name, models.RevisionCollectionSuffix) {
+			continue
+		}
+		info := models.CollectionInfo{
+			Name:   spec.Name,
+			Type:   spec.Type,
+			Active: active[spec.Name],
+		}
+
+		var opts collectionOptions
+		if len(spec.Options) > 0 {
+			if err := bson.Unmarshal(spec.Options, &opts); err == nil {
+				info.Schema = jsonSchemaFromValidator(opts.Validator)
+				info.ValidationLevel = opts.ValidationLevel
+				info.ValidationAction = opts.ValidationAction
+				info.Capped = opts.Capped
+				info.CappedSize = opts.Size
+				info.CappedMax = opts.Max
+				info.Timeseries = opts.Timeseries
+			}
+		}
+
+		// View tidak punya storage sendiri, jadi $collStats tidak bisa dijalankan
+		if spec.Type != "view" {
+			cursor, err := db.Collection(spec.Name).Aggregate(ctx, mongo.Pipeline{
+				{{Key: "$collStats", Value: bson.M{"storageStats": bson.M{}}}},
+			})
+			if err == nil {
+				var stats []collectionStorageStats
+				if err := cursor.All(ctx, &stats); err == nil && len(stats) > 0 {
+					s := stats[0].StorageStats
+					info.DocumentCount = s.Count
+					info.DataSize = s.Size
+					info.StorageSize = s.StorageSize
+					info.AvgDocumentSize = s.AvgObjSize
+					info.IndexCount = s.NIndexes
+					info.TotalIndexSize = s.TotalIndexSize
+				}
+			}
+		}
+
+		infos = append(infos, info)
+	}
+
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+	return infos, nil
+}
@@ -0,0 +1,130 @@
+// file: controllers/collection_info_test.go
+package controllers
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/fiber-mongo/starter-kit/models" // Sesuaikan dengan nama modul Anda
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestSchemaFieldsFromValidator(t *testing.T) {
+	tests := []struct {
+		name      string
+		validator bson.D
+		fields    []models.SchemaField
+		editable  bool
+	}{
+		{
+			name:     "no validator",
+			fields:   []models.SchemaField{},
+			editable: true,
+		},
+		{
+			name: "flat fields",
+			validator: bson.D{{Key: "$jsonSchema", Value: bson.D{
+				{Key: "bsonType", Value: "object"},
+				{Key: "required", Value: bson.A{"title"}},
+				{Key: "properties", Value: bson.D{
+					{Key: "title", Value: bson.D{{Key: "bsonType", Value: "string"}}},
+					{Key: "total", Value: bson.D{{Key: "bsonType", Value: "decimal"}}},
+					{Key: "meta", Value: bson.D{{Key: "bsonType", Value: "object"}}},
+				}},
+			}}},
+			fields: []models.SchemaField{
+				{Name: "title", Type: "string", Required: true},
+				{Name: "total", Type: "decimal"},
+				{Name: "meta", Type: "object"},
+			},
+			editable: true,
+		},
+		{
+			name: "nullable type",
+			validator: bson.D{{Key: "$jsonSchema", Value: bson.D{
+				{Key: "properties", Value: bson.D{
+					{Key: "title", Value: bson.D{{Key: "bsonType", Value: bson.A{"string", "null"}}}},
+				}},
+			}}},
+			fields:   []models.SchemaField{{Name: "title", Type: "string|null"}},
+			editable: false,
+		},
+		{
+			name: "untyped field",
+			validator: bson.D{{Key: "$jsonSchema", Value: bson.D{
+				{Key: "properties", Value: bson.D{
+					{Key: "anything", Value: bson.D{{Key: "description", Value: "free form"}}},
+				}},
+			}}},
+			fields:   []models.SchemaField{{Name: "anything"}},
+			editable: false,
+		},
+		{
+			name: "constraints",
+			validator: bson.D{{Key: "$jsonSchema", Value: bson.D{
+				{Key: "properties", Value: bson.D{
+					{Key: "age", Value: bson.D{{Key: "bsonType", Value: "int"}, {Key: "minimum", Value: 0}}},
+				}},
+			}}},
+			fields:   []models.SchemaField{{Name: "age", Type: "int"}},
+			editable: false,
+		},
+		{
+			name: "nested properties",
+			validator: bson.D{{Key: "$jsonSchema", Value: bson.D{
+				{Key: "properties", Value: bson.D{
+					{Key: "address", Value: bson.D{
+						{Key: "bsonType", Value: "object"},
+						{Key: "properties", Value: bson.D{{Key: "city", Value: bson.D{{Key: "bsonType", Value: "string"}}}}},
+					}},
+				}},
+			}}},
+			fields:   []models.SchemaField{{Name: "address", Type: "object"}},
+			editable: false,
+		},
+		{
+			name: "top-level keyword",
+			validator: bson.D{{Key: "$jsonSchema", Value: bson.D{
+				{Key: "additionalProperties", Value: false},
+				{Key: "properties", Value: bson.D{{Key: "title", Value: bson.D{{Key: "bsonType", Value: "string"}}}}},
+			}}},
+			fields:   []models.SchemaField{{Name: "title", Type: "string"}},
+			editable: false,
+		},
+		{
+			name: "required without property",
+			validator: bson.D{{Key: "$jsonSchema", Value: bson.D{
+				{Key: "required", Value: bson.A{"title"}},
+			}}},
+			fields:   []models.SchemaField{},
+			editable: false,
+		},
+		{
+			name:      "query operator validator",
+			validator: bson.D{{Key: "age", Value: bson.D{{Key: "$gte", Value: 0}}}},
+			fields:    []models.SchemaField{},
+			editable:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var validator bson.Raw
+			if tt.validator != nil {
+				raw, err := bson.Marshal(tt.validator)
+				if err != nil {
+					t.Fatal(err)
+				}
+				validator = raw
+			}
+			fields, editable := schemaFieldsFromValidator(validator)
+			if !reflect.DeepEqual(fields, tt.fields) {
+				t.Errorf("fields = %+v, want %+v", fields, tt.fields)
+			}
+			if editable != tt.editable {
+				t.Errorf("editable = %v, want %v", editable, tt.editable)
+			}
+		})
+	}
+}
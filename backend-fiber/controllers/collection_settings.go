@@ -0,0 +1,101 @@
+// file: controllers/collection_settings.go
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/fiber-mongo/starter-kit/apierror"   // Sesuaikan dengan nama modul Anda
+	"github.com/fiber-mongo/starter-kit/database"   // Sesuaikan dengan nama modul Anda
+	"github.com/fiber-mongo/starter-kit/middleware" // Sesuaikan dengan nama modul Anda
+	"github.com/fiber-mongo/starter-kit/models"     // Sesuaikan dengan nama modul Anda
+	"github.com/fiber-mongo/starter-kit/services"   // Sesuaikan dengan nama modul Anda
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Fungsi helper untuk mendapatkan identitas pembuat request Data API
+func requestActor(c *fiber.Ctx) models.Actor {
+	return models.Actor{
+		UserID:   c.Get("X-User-ID"),
+		APIKeyID: middleware.APIKeyID(c),
+	}
+}
+
+// Handler untuk GET /projects/{id}/collections/{collName}/settings
+func GetCollectionSettings(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	projObjID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return apierror.InvalidObjectID("Project")
+	}
+	collectionName := c.Params("collName")
+
+	settings, err := services.Documents().Settings(ctx, projObjID, []string{collectionName})
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(settings[collectionName])
+}
+
+// Handler untuk PUT /projects/{id}/collections/{collName}/settings (Aktifkan/matikan timestamps, soft delete dan revisi)
+func UpdateCollectionSettings(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	projObjID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return apierror.InvalidObjectID("Project")
+	}
+	collectionName := c.Params("collName")
+	project, err := services.Projects().Get(ctx, projObjID)
+	if err != nil {
+		return err
+	}
+
+	var input models.CollectionSettingsInput
+	if err := c.BodyParser(&input); err != nil {
+		return apierror.InvalidBody("Invalid request body")
+	}
+
+	set := bson.M{"updatedAt": time.Now()}
+	if input.Timestamps != nil {
+		set["timestamps"] = *input.Timestamps
+	}
+	if input.SoftDelete != nil {
+		set["softDelete"] = *input.SoftDelete
+	}
+	if input.Revisions != nil {
+		set["revisions"] = *input.Revisions
+	}
+
+	// Index unik nomor revisi dibuat sebelum revisi diaktifkan
+	if input.Revisions != nil && *input.Revisions {
+		userDBClient, err := database.ProjectClient(ctx, project)
+		if err != nil {
+			return apierror.Internal(err, "Could not connect to user database")
+		}
+		revisions := userDBClient.Database(project.DBName).Collection(collectionName + models.RevisionCollectionSuffix)
+		if err := database.EnsureRevisionIndexes(ctx, revisions); err != nil {
+			return apierror.Internal(err, "Failed to create revision indexes")
+		}
+	}
+
+	var settings models.CollectionSettings
+	err = database.GetCollection("collection_settings").FindOneAndUpdate(ctx,
+		bson.M{"projectId": projObjID, "collectionName": collectionName},
+		bson.M{"$set": set},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(&settings)
+	if err != nil {
+		return apierror.Internal(err, "Failed to update collection settings")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(settings)
+}
@@ -0,0 +1,189 @@
+// file: controllers/controllers_test.go
+package controllers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/fiber-mongo/starter-kit/apierror"   // Sesuaikan dengan nama modul Anda
+	"github.com/fiber-mongo/starter-kit/database"   // Sesuaikan dengan nama modul Anda
+	"github.com/fiber-mongo/starter-kit/models"     // Sesuaikan dengan nama modul Anda
+	"github.com/fiber-mongo/starter-kit/repository" // Sesuaikan dengan nama modul Anda
+	"github.com/fiber-mongo/starter-kit/routes"     // Sesuaikan dengan nama modul Anda
+	"github.com/fiber-mongo/starter-kit/services"   // Sesuaikan dengan nama modul Anda
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Event perubahan dokumen yang dicatat selama test (pengganti realtime dan webhook)
+type recordedEvent struct {
+	Collection string
+	Operation  string
+	DocumentID interface{}
+}
+
+type eventRecorder struct {
+	mu     sync.Mutex
+	events []recordedEvent
+}
+
+func (r *eventRecorder) DocumentChanged(ctx context.Context, project models.Project, collection, operationType string, documentID interface{}, before, after bson.M) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, recordedEvent{Collection: collection, Operation: operationType, DocumentID: documentID})
+}
+
+func (r *eventRecorder) operations() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ops := []string{}
+	for _, e := range r.events {
+		ops = append(ops, e.Operation)
+	}
+	return ops
+}
+
+type testEnv struct {
+	app       *fiber.App
+	projects  *repository.MemoryProjects
+	documents *repository.MemoryDocuments
+	events    *eventRecorder
+}
+
+// Fungsi helper untuk menyiapkan app dengan repository memori. checkConnection nil berarti koneksi selalu berhasil.
+func newTestEnv(t *testing.T, checkConnection services.ConnectionChecker) *testEnv {
+	t.Helper()
+	env := &testEnv{
+		projects:  repository.NewMemoryProjects(),
+		documents: repository.NewMemoryDocuments(),
+		events:    &eventRecorder{},
+	}
+	if checkConnection == nil {
+		checkConnection = func(ctx context.Context, conn database.ProjectConnection) error { return nil }
+	}
+	services.Set(services.Services{
+		Projects:  services.NewProjectService(env.projects, checkConnection),
+		Documents: services.NewDocumentService(env.projects, env.documents, env.events),
+	})
+	t.Cleanup(func() { services.Set(services.NewMongo()) })
+
+	// Rute dari routes.SetupRoutesWith; middleware yang menulis ke database platform (Audit, Usage, RateLimit) diganti stub
+	next := func(c *fiber.Ctx) error { return c.Next() }
+	env.app = fiber.New(fiber.Config{ErrorHandler: apierror.Handler})
+	routes.SetupRoutesWith(env.app, routes.Middleware{
+		Audit:     func(action string) fiber.Handler { return next },
+		Usage:     next,
+		RateLimit: next,
+	})
+	return env
+}
+
+// Fungsi helper untuk membuat proyek langsung di repository (tanpa lewat POST /projects)
+func (e *testEnv) createProject(t *testing.T, activeCollections ...string) models.Project {
+	t.Helper()
+	project := models.Project{Name: "Test", DBName: "testdb", ApiKey: "key-" + t.Name(), ActiveCollections: activeCollections}
+	if err := e.projects.Create(context.Background(), &project); err != nil {
+		t.Fatalf("create project: %v", err)
+	}
+	return project
+}
+
+type response struct {
+	status int
+	header http.Header
+	body   []byte
+}
+
+// Fungsi helper untuk mengirim request. body bertipe io.Reader dikirim apa adanya, selain itu di-encode sebagai JSON.
+func (e *testEnv) do(t *testing.T, method, path string, body interface{}, headers map[string]string) response {
+	t.Helper()
+	var reader io.Reader
+	contentType := ""
+	switch b := body.(type) {
+	case nil:
+	case io.Reader:
+		reader = b
+	default:
+		data, err := json.Marshal(b)
+		if err != nil {
+			t.Fatalf("encode body: %v", err)
+		}
+		reader = bytes.NewReader(data)
+		contentType = fiber.MIMEApplicationJSON
+	}
+
+	req := httptest.NewRequest(method, path, reader)
+	if contentType != "" {
+		req.Header.Set(fiber.HeaderContentType, contentType)
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := e.app.Test(req, -1)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, path, err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("read body: %v", err)
+	}
+	return response{status: resp.StatusCode, header: resp.Header, body: data}
+}
+
+// Fungsi helper untuk request multipart/form-data (POST dokumen hanya menerima form)
+func (e *testEnv) doForm(t *testing.T, method, path string, fields map[string]string, headers map[string]string) response {
+	t.Helper()
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+	for k, v := range fields {
+		if err := writer.WriteField(k, v); err != nil {
+			t.Fatalf("write field: %v", err)
+		}
+	}
+	writer.Close()
+
+	all := map[string]string{fiber.HeaderContentType: writer.FormDataContentType()}
+	for k, v := range headers {
+		all[k] = v
+	}
+	return e.do(t, method, path, &buf, all)
+}
+
+func (r response) decode(t *testing.T, v interface{}) {
+	t.Helper()
+	if err := json.Unmarshal(r.body, v); err != nil {
+		t.Fatalf("decode %q: %v", r.body, err)
+	}
+}
+
+// Fungsi helper untuk memeriksa status dan kode error API
+func (r response) expectError(t *testing.T, status int, code string) {
+	t.Helper()
+	if r.status != status {
+		t.Fatalf("status = %d, want %d (body %s)", r.status, status, r.body)
+	}
+	var body struct {
+		Code string `json:"code"`
+	}
+	r.decode(t, &body)
+	if body.Code != code {
+		t.Fatalf("code = %q, want %q (body %s)", body.Code, code, r.body)
+	}
+}
+
+func (r response) expectStatus(t *testing.T, status int) {
+	t.Helper()
+	if r.status != status {
+		t.Fatalf("status = %d, want %d (body %s)", r.status, status, r.body)
+	}
+}
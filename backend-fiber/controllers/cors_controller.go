@@ -0,0 +1,135 @@
+// file: controllers/cors_controller.go
+package controllers
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/fiber-mongo/starter-kit/apierror"   // Sesuaikan dengan nama modul Anda
+	"github.com/fiber-mongo/starter-kit/corspolicy" // Sesuaikan dengan nama modul Anda
+	"github.com/fiber-mongo/starter-kit/database"   // Sesuaikan dengan nama modul Anda
+	"github.com/fiber-mongo/starter-kit/models"     // Sesuaikan dengan nama modul Anda
+	"github.com/fiber-mongo/starter-kit/services"   // Sesuaikan dengan nama modul Anda
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Handler untuk GET /projects/{id}/cors (Aturan CORS Data API proyek)
+func GetProjectCORS(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	projObjID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return apierror.InvalidObjectID("Project")
+	}
+	if _, err := services.Projects().Get(ctx, projObjID); err != nil {
+		return err
+	}
+
+	settings, err := corspolicy.Load(ctx, projObjID)
+	if err != nil {
+		return apierror.Internal(err, "Failed to read CORS settings")
+	}
+	return c.Status(fiber.StatusOK).JSON(settings)
+}
+
+// Handler untuk PUT /projects/{id}/cors (Ubah origin, method dan header yang diizinkan untuk browser)
+func UpdateProjectCORS(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	projObjID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return apierror.InvalidObjectID("Project")
+	}
+	if _, err := services.Projects().Get(ctx, projObjID); err != nil {
+		return err
+	}
+
+	var input models.CORSSettingsInput
+	if err := c.BodyParser(&input); err != nil {
+		return apierror.InvalidBody("Invalid request body")
+	}
+
+	defaults := corspolicy.DefaultSettings(projObjID)
+	set := bson.M{"updatedAt": time.Now()}
+	setOnInsert := bson.M{
+		"allowOrigins": defaults.AllowOrigins,
+		"allowMethods": defaults.AllowMethods,
+		"allowHeaders": defaults.AllowHeaders,
+		"maxAge":       defaults.MaxAge,
+	}
+	if input.AllowOrigins != nil {
+		origins := []string{}
+		for _, origin := range *input.AllowOrigins {
+			origin = strings.TrimSuffix(strings.TrimSpace(origin), "/")
+			if err := corspolicy.ValidateOrigin(origin); err != nil {
+				return apierror.Validation("allowOrigins", err.Error())
+			}
+			origins = append(origins, origin)
+		}
+		set["allowOrigins"] = origins
+	}
+	if input.AllowMethods != nil {
+		methods, err := corspolicy.NormalizeMethods(*input.AllowMethods)
+		if err != nil {
+			return apierror.Validation("allowMethods", err.Error())
+		}
+		if len(methods) == 0 {
+			return apierror.Validation("allowMethods", "At least one method is required")
+		}
+		set["allowMethods"] = methods
+	}
+	if input.AllowHeaders != nil {
+		headers, err := corspolicy.NormalizeHeaders(*input.AllowHeaders)
+		if err != nil {
+			return apierror.Validation("allowHeaders", err.Error())
+		}
+		// Tanpa X-API-Key browser tidak bisa memanggil Data API sama sekali
+		if !containsFold(headers, "X-API-Key") {
+			headers = append(headers, "X-API-Key")
+		}
+		set["allowHeaders"] = headers
+	}
+	if input.MaxAge != nil {
+		if !corspolicy.ValidMaxAge(*input.MaxAge) {
+			return apierror.Validation("maxAge", "maxAge must be between 0 and 86400 seconds")
+		}
+		set["maxAge"] = *input.MaxAge
+	}
+	for field := range set {
+		delete(setOnInsert, field)
+	}
+	update := bson.M{"$set": set}
+	if len(setOnInsert) > 0 {
+		update["$setOnInsert"] = setOnInsert
+	}
+
+	var settings models.CORSSettings
+	err = database.GetCollection(corspolicy.CollectionName).FindOneAndUpdate(ctx,
+		bson.M{"projectId": projObjID},
+		update,
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(&settings)
+	if err != nil {
+		return apierror.Internal(err, "Failed to update CORS settings")
+	}
+	corspolicy.Invalidate(projObjID)
+
+	return c.Status(fiber.StatusOK).JSON(settings)
+}
+
+// Fungsi helper untuk mencari string tanpa membedakan huruf besar/kecil
+func containsFold(list []string, value string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -3,90 +3,80 @@ package controllers
 
 import (
 	"context"
-	"fmt"
-	"net/url"
+	"errors"
 	"os"
 	"path/filepath"
 	"strconv"
 	"time"
 
-	"github.com/fiber-mongo/starter-kit/database" // Sesuaikan nama modul
-	"github.com/fiber-mongo/starter-kit/models"   // Sesuaikan nama modul
+	"github.com/fiber-mongo/starter-kit/apierror" // Sesuaikan nama modul
+	"github.com/fiber-mongo/starter-kit/config"   // Sesuaikan nama modul
+	"github.com/fiber-mongo/starter-kit/hooks"    // Sesuaikan nama modul
+	"github.com/fiber-mongo/starter-kit/metrics"  // Sesuaikan nama modul
+	"github.com/fiber-mongo/starter-kit/services" // Sesuaikan nama modul
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-// Fungsi helper untuk menghubungkan ke database user secara dinamis
-func connectToUserDB(ctx context.Context, project models.Project) (*mongo.Client, error) {
-	uri := fmt.Sprintf("mongodb://%s:%s@%s:%s/%s?authSource=%s",
-		project.DBUser, url.QueryEscape(project.DBPassword), project.DBHost, project.DBPort, project.DBName, project.AuthSource)
-	if project.DBUser == "" || project.DBPassword == "" {
-		uri = fmt.Sprintf("mongodb://%s:%s/%s", project.DBHost, project.DBPort, project.DBName)
-	}
-
-	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
-	if err != nil {
-		return nil, err
-	}
-	err = client.Ping(ctx, nil)
-	if err != nil {
-		return nil, err
-	}
-	return client, nil
-}
-
 // Handler untuk GET /.../{collectionName} (Ambil semua dokumen)
+// Dokumen yang sudah di-soft delete disembunyikan kecuali ?includeDeleted=true
+// Respons menyertakan ETag (weak) sehingga client bisa memakai If-None-Match untuk caching (304)
 func GetAllDocuments(c *fiber.Ctx) error {
-	projectCollection := database.GetCollection("projects")
-	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	ctx, cancel := context.WithTimeout(c.UserContext(), 15*time.Second)
 	defer cancel()
 
 	projectIdStr := c.Params("projectId")
 	collectionName := c.Params("collectionName")
 	objID, err := primitive.ObjectIDFromHex(projectIdStr)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid Project ID format"})
+		return apierror.InvalidObjectID("Project")
 	}
 
-	var project models.Project
-	err = projectCollection.FindOne(ctx, bson.M{"_id": objID}).Decode(&project)
+	project, err := services.Projects().Get(ctx, objID)
 	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Project not found"})
+		return err
 	}
 
-	userDBClient, err := connectToUserDB(ctx, project)
+	scope, err := services.Documents().Open(ctx, project, collectionName, requestActor(c))
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Could not connect to user database"})
+		return err
+	}
+	filter := bson.M{}
+	if !c.QueryBool("includeDeleted") {
+		filter = scope.LiveFilter(filter)
 	}
-	defer userDBClient.Disconnect(ctx)
 
-	userCollection := userDBClient.Database(project.DBName).Collection(collectionName)
-	cursor, err := userCollection.Find(ctx, bson.M{})
+	cursor, err := scope.Collection.Find(ctx, filter)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch documents"})
+		return apierror.Internal(err, "Failed to fetch documents")
+	}
+
+	var docs []bson.Raw
+	if err = cursor.All(ctx, &docs); err != nil {
+		return apierror.Internal(err, "Failed to decode documents")
 	}
 
-	var results []bson.M
-	if err = cursor.All(ctx, &results); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to decode documents"})
+	etag := services.ListETag(docs)
+	c.Set(fiber.HeaderETag, etag)
+	if match := c.Get(fiber.HeaderIfNoneMatch); match != "" && services.ETagMatches(match, etag) {
+		return c.SendStatus(fiber.StatusNotModified)
 	}
 
-	if results == nil {
-		results = []bson.M{}
+	results, err := rawToMaps(docs)
+	if err != nil {
+		return apierror.Internal(err, "Failed to decode documents")
 	}
 
 	return c.Status(fiber.StatusOK).JSON(results)
 }
 
 // Handler untuk GET /.../{collectionName}/{docId} (Ambil satu dokumen)
+// Respons menyertakan ETag (hash isi dokumen) untuk dipakai di If-Match saat update/delete
 func GetOneDocument(c *fiber.Ctx) error {
-	projectCollection := database.GetCollection("projects")
-	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	ctx, cancel := context.WithTimeout(c.UserContext(), 15*time.Second)
 	defer cancel()
 
 	projectIdStr := c.Params("projectId")
@@ -95,31 +85,42 @@ func GetOneDocument(c *fiber.Ctx) error {
 
 	projObjID, err := primitive.ObjectIDFromHex(projectIdStr)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid Project ID format"})
+		return apierror.InvalidObjectID("Project")
 	}
 	docObjID, err := primitive.ObjectIDFromHex(docIdStr)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid Document ID format"})
+		return apierror.InvalidObjectID("Document")
+	}
+
+	project, err := services.Projects().Get(ctx, projObjID)
+	if err != nil {
+		return err
 	}
 
-	var project models.Project
-	err = projectCollection.FindOne(ctx, bson.M{"_id": projObjID}).Decode(&project)
+	scope, err := services.Documents().Open(ctx, project, collectionName, requestActor(c))
 	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Project not found"})
+		return err
+	}
+	filter := bson.M{"_id": docObjID}
+	if !c.QueryBool("includeDeleted") {
+		filter = scope.DocumentFilter(docObjID)
 	}
 
-	userDBClient, err := connectToUserDB(ctx, project)
+	var raw bson.Raw
+	err = scope.Collection.FindOne(ctx, filter).Decode(&raw)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Could not connect to user database"})
+		return apierror.NotFound(apierror.CodeDocumentNotFound, "Document not found")
 	}
-	defer userDBClient.Disconnect(ctx)
 
-	userCollection := userDBClient.Database(project.DBName).Collection(collectionName)
+	etag := services.DocumentETag(raw)
+	c.Set(fiber.HeaderETag, etag)
+	if match := c.Get(fiber.HeaderIfNoneMatch); match != "" && services.ETagMatches(match, etag) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
 
 	var result bson.M
-	err = userCollection.FindOne(ctx, bson.M{"_id": docObjID}).Decode(&result)
-	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Document not found"})
+	if err := bson.Unmarshal(raw, &result); err != nil {
+		return apierror.Internal(err, "Failed to decode document")
 	}
 
 	return c.Status(fiber.StatusOK).JSON(result)
@@ -127,32 +128,28 @@ func GetOneDocument(c *fiber.Ctx) error {
 
 // Handler untuk POST /.../{collectionName} (Buat dokumen baru dengan file)
 func CreateDocument(c *fiber.Ctx) error {
-	projectCollection := database.GetCollection("projects")
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(c.UserContext(), 30*time.Second)
 	defer cancel()
 
 	projectIdStr := c.Params("projectId")
 	collectionName := c.Params("collectionName")
 	projObjID, err := primitive.ObjectIDFromHex(projectIdStr)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid Project ID format"})
+		return apierror.InvalidObjectID("Project")
 	}
 
-	var project models.Project
-	err = projectCollection.FindOne(ctx, bson.M{"_id": projObjID}).Decode(&project)
+	project, err := services.Projects().Get(ctx, projObjID)
 	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Project not found"})
+		return err
 	}
 
-	userDBClient, err := connectToUserDB(ctx, project)
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Could not connect to user database"})
+	if storageQuotaExceeded(ctx, c, project) {
+		return apierror.New(fiber.StatusInsufficientStorage, apierror.CodeStorageQuotaExceeded, "Storage quota exceeded")
 	}
-	defer userDBClient.Disconnect(ctx)
 
 	form, err := c.MultipartForm()
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid form data. Ensure you are sending multipart/form-data."})
+		return apierror.InvalidBody("Invalid form data. Ensure you are sending multipart/form-data.")
 	}
 
 	newDoc := bson.M{}
@@ -176,32 +173,41 @@ func CreateDocument(c *fiber.Ctx) error {
 			extension := filepath.Ext(file.Filename)
 			uniqueFileName := uuid.New().String() + extension
 
-			storagePath := fmt.Sprintf("./public/uploads/%s/%s", projectIdStr, collectionName)
+			storagePath := filepath.Join(config.Get().UploadDir, projectIdStr, collectionName)
 			if err := os.MkdirAll(storagePath, os.ModePerm); err != nil {
-				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to create storage directory"})
+				return apierror.Internal(err, "Failed to create storage directory")
 			}
 
 			filePath := filepath.Join(storagePath, uniqueFileName)
 			if err := c.SaveFile(file, filePath); err != nil {
-				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to save file"})
+				return apierror.Internal(err, "Failed to save file")
 			}
+			metrics.RecordUpload(file.Size)
 			newDoc[key] = uniqueFileName
 		}
 	}
 
-	userCollection := userDBClient.Database(project.DBName).Collection(collectionName)
-	result, err := userCollection.InsertOne(ctx, newDoc)
+	scope, err := services.Documents().Open(ctx, project, collectionName, requestActor(c))
+	if err != nil {
+		return err
+	}
+	scope.StorageLimit = storageLimit(c)
+	insertedID, err := scope.Insert(ctx, newDoc)
+	var hookErr *hooks.Error
+	if errors.As(err, &hookErr) {
+		return apierror.New(fiber.StatusUnprocessableEntity, apierror.CodeHookRejected, hookErr.Error()).Wrap(hookErr)
+	}
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to create document", "details": err.Error()})
+		return documentWriteError(err, "Failed to create document")
 	}
 
-	return c.Status(fiber.StatusCreated).JSON(result)
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{"InsertedID": insertedID})
 }
 
 // Handler untuk PUT /.../{collectionName}/{docId} (Update dokumen dengan file)
+// Jika header If-Match dikirim, update ditolak dengan 412 bila dokumen sudah berubah
 func UpdateDocument(c *fiber.Ctx) error {
-	projectCollection := database.GetCollection("projects")
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(c.UserContext(), 30*time.Second)
 	defer cancel()
 
 	projectIdStr := c.Params("projectId")
@@ -210,34 +216,31 @@ func UpdateDocument(c *fiber.Ctx) error {
 
 	projObjID, err := primitive.ObjectIDFromHex(projectIdStr)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid Project ID format"})
+		return apierror.InvalidObjectID("Project")
 	}
 	docObjID, err := primitive.ObjectIDFromHex(docIdStr)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid Document ID format"})
+		return apierror.InvalidObjectID("Document")
 	}
 
-	var project models.Project
-	err = projectCollection.FindOne(ctx, bson.M{"_id": projObjID}).Decode(&project)
+	project, err := services.Projects().Get(ctx, projObjID)
 	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Project not found"})
+		return err
 	}
 
-	userDBClient, err := connectToUserDB(ctx, project)
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Could not connect to user database"})
+	if storageQuotaExceeded(ctx, c, project) {
+		return apierror.New(fiber.StatusInsufficientStorage, apierror.CodeStorageQuotaExceeded, "Storage quota exceeded")
 	}
-	defer userDBClient.Disconnect(ctx)
 
 	// Coba parse sebagai multipart form terlebih dahulu
 	form, err := c.MultipartForm()
-	
+
 	updateData := bson.M{}
 
 	// Jika request BUKAN multipart/form-data (kemungkinan JSON biasa)
 	if err != nil {
 		if err := c.BodyParser(&updateData); err != nil {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body. Must be multipart/form-data or application/json."})
+			return apierror.InvalidBody("Invalid request body. Must be multipart/form-data or application/json.")
 		}
 	} else {
 		// --- BAGIAN YANG DITAMBAHKAN UNTUK MENGGUNAKAN 'form' ---
@@ -261,36 +264,48 @@ func UpdateDocument(c *fiber.Ctx) error {
 				file := fileHeaders[0]
 				extension := filepath.Ext(file.Filename)
 				uniqueFileName := uuid.New().String() + extension
-				storagePath := fmt.Sprintf("./public/uploads/%s/%s", projectIdStr, collectionName)
+				storagePath := filepath.Join(config.Get().UploadDir, projectIdStr, collectionName)
 				if err := os.MkdirAll(storagePath, os.ModePerm); err != nil {
-					return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to create storage directory"})
+					return apierror.Internal(err, "Failed to create storage directory")
 				}
 				filePath := filepath.Join(storagePath, uniqueFileName)
 				if err := c.SaveFile(file, filePath); err != nil {
-					return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to save file"})
+					return apierror.Internal(err, "Failed to save file")
 				}
+				metrics.RecordUpload(file.Size)
 				updateData[key] = uniqueFileName
 			}
 		}
 		// --- AKHIR BAGIAN YANG DITAMBAHKAN ---
 	}
 
-	userCollection := userDBClient.Database(project.DBName).Collection(collectionName)
-	update := bson.M{"$set": updateData}
-	result, err := userCollection.UpdateOne(ctx, bson.M{"_id": docObjID}, update)
+	scope, err := services.Documents().Open(ctx, project, collectionName, requestActor(c))
+	if err != nil {
+		return err
+	}
+	scope.StorageLimit = storageLimit(c)
+	found, err := scope.Update(ctx, docObjID, updateData, c.Get(fiber.HeaderIfMatch))
+	var hookErr *hooks.Error
+	if errors.As(err, &hookErr) {
+		return apierror.New(fiber.StatusUnprocessableEntity, apierror.CodeHookRejected, hookErr.Error()).Wrap(hookErr)
+	}
+	if errors.Is(err, services.ErrPreconditionFailed) {
+		return apierror.New(fiber.StatusPreconditionFailed, apierror.CodePreconditionFailed, "Document has been modified (ETag mismatch)")
+	}
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to update document"})
+		return documentWriteError(err, "Failed to update document")
 	}
-	if result.MatchedCount == 0 {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Document not found to update"})
+	if !found {
+		return apierror.NotFound(apierror.CodeDocumentNotFound, "Document not found to update")
 	}
 
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Document updated successfully"})
 }
+
 // Handler untuk DELETE /.../{collectionName}/{docId} (Hapus dokumen)
+// Jika header If-Match dikirim, penghapusan ditolak dengan 412 bila dokumen sudah berubah
 func DeleteDocument(c *fiber.Ctx) error {
-	projectCollection := database.GetCollection("projects")
-	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	ctx, cancel := context.WithTimeout(c.UserContext(), 15*time.Second)
 	defer cancel()
 
 	projectIdStr := c.Params("projectId")
@@ -299,44 +314,122 @@ func DeleteDocument(c *fiber.Ctx) error {
 
 	projObjID, err := primitive.ObjectIDFromHex(projectIdStr)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid Project ID format"})
+		return apierror.InvalidObjectID("Project")
 	}
 	docObjID, err := primitive.ObjectIDFromHex(docIdStr)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid Document ID format"})
+		return apierror.InvalidObjectID("Document")
 	}
 
-	var project models.Project
-	err = projectCollection.FindOne(ctx, bson.M{"_id": projObjID}).Decode(&project)
+	project, err := services.Projects().Get(ctx, projObjID)
 	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Project not found"})
+		return err
 	}
 
-	userDBClient, err := connectToUserDB(ctx, project)
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Could not connect to user database"})
-	}
-	defer userDBClient.Disconnect(ctx)
-	
 	// TODO: Hapus juga file terkait dari storage jika ada
 
-	userCollection := userDBClient.Database(project.DBName).Collection(collectionName)
-	result, err := userCollection.DeleteOne(ctx, bson.M{"_id": docObjID})
+	scope, err := services.Documents().Open(ctx, project, collectionName, requestActor(c))
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to delete document"})
+		return err
+	}
+	found, err := scope.Delete(ctx, docObjID, c.Get(fiber.HeaderIfMatch))
+	var hookErr *hooks.Error
+	if errors.As(err, &hookErr) {
+		return apierror.New(fiber.StatusUnprocessableEntity, apierror.CodeHookRejected, hookErr.Error()).Wrap(hookErr)
+	}
+	if errors.Is(err, services.ErrPreconditionFailed) {
+		return apierror.New(fiber.StatusPreconditionFailed, apierror.CodePreconditionFailed, "Document has been modified (ETag mismatch)")
+	}
+	if err != nil {
+		return apierror.Internal(err, "Failed to delete document")
 	}
 
-	if result.DeletedCount == 0 {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Document not found to delete"})
+	if !found {
+		return apierror.NotFound(apierror.CodeDocumentNotFound, "Document not found to delete")
 	}
 
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Document deleted successfully"})
 }
 
+// Handler untuk POST /.../{collectionName}/{docId}/restore (Pulihkan dokumen yang sudah di-soft delete)
+func RestoreDocument(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), 15*time.Second)
+	defer cancel()
+
+	projectIdStr := c.Params("projectId")
+	collectionName := c.Params("collectionName")
+	docIdStr := c.Params("docId")
+
+	projObjID, err := primitive.ObjectIDFromHex(projectIdStr)
+	if err != nil {
+		return apierror.InvalidObjectID("Project")
+	}
+	docObjID, err := primitive.ObjectIDFromHex(docIdStr)
+	if err != nil {
+		return apierror.InvalidObjectID("Document")
+	}
+
+	project, err := services.Projects().Get(ctx, projObjID)
+	if err != nil {
+		return err
+	}
+
+	scope, err := services.Documents().Open(ctx, project, collectionName, requestActor(c))
+	if err != nil {
+		return err
+	}
+	found, err := scope.Restore(ctx, docObjID)
+	if err != nil {
+		return documentWriteError(err, "Failed to restore document")
+	}
+	if !found {
+		return apierror.NotFound(apierror.CodeDocumentNotFound, "Deleted document not found")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Document restored successfully"})
+}
+
+// Handler untuk DELETE /.../{collectionName}/{docId}/purge (Hapus permanen dokumen yang sudah di-soft delete)
+func PurgeDocument(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), 15*time.Second)
+	defer cancel()
+
+	projectIdStr := c.Params("projectId")
+	collectionName := c.Params("collectionName")
+	docIdStr := c.Params("docId")
+
+	projObjID, err := primitive.ObjectIDFromHex(projectIdStr)
+	if err != nil {
+		return apierror.InvalidObjectID("Project")
+	}
+	docObjID, err := primitive.ObjectIDFromHex(docIdStr)
+	if err != nil {
+		return apierror.InvalidObjectID("Document")
+	}
+
+	project, err := services.Projects().Get(ctx, projObjID)
+	if err != nil {
+		return err
+	}
+
+	scope, err := services.Documents().Open(ctx, project, collectionName, requestActor(c))
+	if err != nil {
+		return err
+	}
+	found, err := scope.Purge(ctx, docObjID)
+	if err != nil {
+		return apierror.Internal(err, "Failed to purge document")
+	}
+	if !found {
+		return apierror.NotFound(apierror.CodeDocumentNotFound, "Deleted document not found")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Document purged successfully"})
+}
+
 // FUNGSI BARU untuk menyajikan file
 func GetFile(c *fiber.Ctx) error {
-	projectCollection := database.GetCollection("projects")
-	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	ctx, cancel := context.WithTimeout(c.UserContext(), 15*time.Second)
 	defer cancel()
 
 	projectIdStr := c.Params("projectId")
@@ -346,39 +439,36 @@ func GetFile(c *fiber.Ctx) error {
 
 	projObjID, err := primitive.ObjectIDFromHex(projectIdStr)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid Project ID"})
+		return apierror.InvalidObjectID("Project")
 	}
 	docObjID, err := primitive.ObjectIDFromHex(docIdStr)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid Document ID"})
+		return apierror.InvalidObjectID("Document")
 	}
 
-	var project models.Project
-	err = projectCollection.FindOne(ctx, bson.M{"_id": projObjID}).Decode(&project)
+	project, err := services.Projects().Get(ctx, projObjID)
 	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Project not found"})
+		return err
 	}
 
-	userDBClient, err := connectToUserDB(ctx, project)
+	scope, err := services.Documents().Open(ctx, project, collectionName, requestActor(c))
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Could not connect to user database"})
+		return err
 	}
-	defer userDBClient.Disconnect(ctx)
-
-	userCollection := userDBClient.Database(project.DBName).Collection(collectionName)
 
+	// File milik dokumen yang sudah di-soft delete tidak lagi disajikan
 	var result bson.M
-	err = userCollection.FindOne(ctx, bson.M{"_id": docObjID}).Decode(&result)
+	err = scope.Collection.FindOne(ctx, scope.DocumentFilter(docObjID)).Decode(&result)
 	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Document not found"})
+		return apierror.NotFound(apierror.CodeDocumentNotFound, "Document not found")
 	}
 
 	fileName, ok := result[fieldName].(string)
 	if !ok || fileName == "" {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "File field not found in document"})
+		return apierror.NotFound(apierror.CodeFileNotFound, "File field not found in document")
 	}
 
-	filePath := fmt.Sprintf("./public/uploads/%s/%s/%s", projectIdStr, collectionName, fileName)
+	filePath := filepath.Join(config.Get().UploadDir, projectIdStr, collectionName, fileName)
 
 	return c.SendFile(filePath)
-}
\ No newline at end of file
+}
@@ -0,0 +1,366 @@
+// file: controllers/dynamic_api_controller_test.go
+package controllers_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/fiber-mongo/starter-kit/apierror" // Sesuaikan nama modul
+	"github.com/fiber-mongo/starter-kit/config"   // Sesuaikan nama modul
+	"github.com/fiber-mongo/starter-kit/models"   // Sesuaikan nama modul
+	"github.com/fiber-mongo/starter-kit/realtime" // Sesuaikan nama modul
+	"github.com/fiber-mongo/starter-kit/services" // Sesuaikan nama modul
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Fungsi helper untuk membuat dokumen lewat POST dan mengembalikan _id-nya
+func createDocument(t *testing.T, env *testEnv, project models.Project, collection string, fields map[string]string) string {
+	t.Helper()
+	resp := env.doForm(t, "POST", "/api/v1/data/"+project.ID.Hex()+"/"+collection, fields, map[string]string{"X-API-Key": project.ApiKey})
+	resp.expectStatus(t, fiber.StatusCreated)
+	var created struct {
+		InsertedID string `json:"InsertedID"`
+	}
+	resp.decode(t, &created)
+	return created.InsertedID
+}
+
+func TestDataAuth(t *testing.T) {
+	env := newTestEnv(t, nil)
+	project := env.createProject(t, "products")
+	other := models.Project{ApiKey: "other-key"}
+	if err := env.projects.Create(context.Background(), &other); err != nil {
+		t.Fatal(err)
+	}
+	path := "/api/v1/data/" + project.ID.Hex() + "/products"
+
+	env.do(t, "GET", path, nil, nil).expectError(t, fiber.StatusUnauthorized, apierror.CodeUnauthorized)
+	env.do(t, "GET", path, nil, map[string]string{"X-API-Key": "wrong"}).expectError(t, fiber.StatusUnauthorized, apierror.CodeInvalidAPIKey)
+	env.do(t, "GET", path, nil, map[string]string{"X-API-Key": other.ApiKey}).expectError(t, fiber.StatusForbidden, apierror.CodeForbidden)
+	env.do(t, "GET", "/api/v1/data/"+project.ID.Hex()+"/secrets", nil, map[string]string{"X-API-Key": project.ApiKey}).
+		expectError(t, fiber.StatusForbidden, apierror.CodeCollectionNotEnabled)
+	env.do(t, "GET", path, nil, map[string]string{"X-API-Key": project.ApiKey}).expectStatus(t, fiber.StatusOK)
+}
+
+func TestDocumentCRUD(t *testing.T) {
+	env := newTestEnv(t, nil)
+	project := env.createProject(t, "products")
+	auth := map[string]string{"X-API-Key": project.ApiKey}
+	base := "/api/v1/data/" + project.ID.Hex() + "/products"
+
+	id := createDocument(t, env, project, "products", map[string]string{"name": "Kopi", "price": "25000", "active": "true"})
+	createDocument(t, env, project, "products", map[string]string{"name": "Teh", "price": "15000"})
+
+	var list []map[string]interface{}
+	env.do(t, "GET", base, nil, auth).decode(t, &list)
+	if len(list) != 2 {
+		t.Fatalf("got %d documents, want 2", len(list))
+	}
+
+	var doc map[string]interface{}
+	resp := env.do(t, "GET", base+"/"+id, nil, auth)
+	resp.expectStatus(t, fiber.StatusOK)
+	resp.decode(t, &doc)
+	if doc["name"] != "Kopi" || doc["price"] != float64(25000) || doc["active"] != true {
+		t.Fatalf("form values were not converted: %v", doc)
+	}
+
+	env.do(t, "PUT", base+"/"+id, map[string]interface{}{"price": 27000}, auth).expectStatus(t, fiber.StatusOK)
+	env.do(t, "GET", base+"/"+id, nil, auth).decode(t, &doc)
+	if doc["price"] != float64(27000) || doc["name"] != "Kopi" {
+		t.Fatalf("update should only change price: %v", doc)
+	}
+
+	env.do(t, "DELETE", base+"/"+id, nil, auth).expectStatus(t, fiber.StatusOK)
+	env.do(t, "GET", base+"/"+id, nil, auth).expectError(t, fiber.StatusNotFound, apierror.CodeDocumentNotFound)
+	env.do(t, "DELETE", base+"/"+id, nil, auth).expectError(t, fiber.StatusNotFound, apierror.CodeDocumentNotFound)
+	env.do(t, "PUT", base+"/"+id, map[string]interface{}{"price": 1}, auth).expectError(t, fiber.StatusNotFound, apierror.CodeDocumentNotFound)
+
+	want := []string{realtime.OperationInsert, realtime.OperationInsert, realtime.OperationUpdate, realtime.OperationDelete}
+	if got := env.events.operations(); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("events = %v, want %v", got, want)
+	}
+}
+
+func TestDocumentInvalidIDs(t *testing.T) {
+	env := newTestEnv(t, nil)
+	project := env.createProject(t, "products")
+	auth := map[string]string{"X-API-Key": project.ApiKey}
+	base := "/api/v1/data/" + project.ID.Hex() + "/products"
+
+	env.do(t, "GET", base+"/123", nil, auth).expectError(t, fiber.StatusBadRequest, apierror.CodeInvalidObjectID)
+	env.do(t, "GET", base+"/"+primitive.NewObjectID().Hex(), nil, auth).expectError(t, fiber.StatusNotFound, apierror.CodeDocumentNotFound)
+	env.do(t, "POST", base, strings.NewReader(`{"name":"x"}`), map[string]string{"X-API-Key": project.ApiKey, fiber.HeaderContentType: fiber.MIMEApplicationJSON}).
+		expectError(t, fiber.StatusBadRequest, apierror.CodeInvalidBody)
+}
+
+func TestDocumentETags(t *testing.T) {
+	env := newTestEnv(t, nil)
+	project := env.createProject(t, "products")
+	auth := map[string]string{"X-API-Key": project.ApiKey}
+	base := "/api/v1/data/" + project.ID.Hex() + "/products"
+	id := createDocument(t, env, project, "products", map[string]string{"name": "Kopi"})
+
+	resp := env.do(t, "GET", base+"/"+id, nil, auth)
+	etag := resp.header.Get(fiber.HeaderETag)
+	if etag == "" {
+		t.Fatal("GET document did not return an ETag")
+	}
+	cached := env.do(t, "GET", base+"/"+id, nil, map[string]string{"X-API-Key": project.ApiKey, fiber.HeaderIfNoneMatch: etag})
+	cached.expectStatus(t, fiber.StatusNotModified)
+
+	listETag := env.do(t, "GET", base, nil, auth).header.Get(fiber.HeaderETag)
+	env.do(t, "GET", base, nil, map[string]string{"X-API-Key": project.ApiKey, fiber.HeaderIfNoneMatch: listETag}).
+		expectStatus(t, fiber.StatusNotModified)
+
+	ifMatch := func(tag string) map[string]string {
+		return map[string]string{"X-API-Key": project.ApiKey, fiber.HeaderIfMatch: tag}
+	}
+	env.do(t, "PUT", base+"/"+id, map[string]interface{}{"name": "Kopi Susu"}, ifMatch(`"stale"`)).
+		expectError(t, fiber.StatusPreconditionFailed, apierror.CodePreconditionFailed)
+	env.do(t, "PUT", base+"/"+id, map[string]interface{}{"name": "Kopi Susu"}, ifMatch(etag)).expectStatus(t, fiber.StatusOK)
+
+	// ETag lama tidak berlaku lagi setelah dokumen berubah
+	env.do(t, "DELETE", base+"/"+id, nil, ifMatch(etag)).expectError(t, fiber.StatusPreconditionFailed, apierror.CodePreconditionFailed)
+	env.do(t, "GET", base, nil, map[string]string{"X-API-Key": project.ApiKey, fiber.HeaderIfNoneMatch: listETag}).
+		expectStatus(t, fiber.StatusOK)
+
+	fresh := env.do(t, "GET", base+"/"+id, nil, auth).header.Get(fiber.HeaderETag)
+	env.do(t, "DELETE", base+"/"+id, nil, ifMatch(fresh)).expectStatus(t, fiber.StatusOK)
+}
+
+func TestDocumentSoftDelete(t *testing.T) {
+	env := newTestEnv(t, nil)
+	project := env.createProject(t, "products")
+	env.projects.SaveCollectionSettings(models.CollectionSettings{ProjectID: project.ID, CollectionName: "products", SoftDelete: true, Timestamps: true})
+	auth := map[string]string{"X-API-Key": project.ApiKey}
+	base := "/api/v1/data/" + project.ID.Hex() + "/products"
+	id := createDocument(t, env, project, "products", map[string]string{"name": "Kopi"})
+
+	var doc map[string]interface{}
+	env.do(t, "GET", base+"/"+id, nil, auth).decode(t, &doc)
+	if doc[models.FieldCreatedAt] == nil || doc[models.FieldCreatedBy] == nil {
+		t.Fatalf("timestamps were not set: %v", doc)
+	}
+
+	env.do(t, "PUT", base+"/"+id, map[string]interface{}{models.FieldCreatedBy: "someone else"}, auth).expectStatus(t, fiber.StatusOK)
+	env.do(t, "GET", base+"/"+id, nil, auth).decode(t, &doc)
+	if doc[models.FieldCreatedBy] == "someone else" {
+		t.Fatal("client was able to overwrite a managed field")
+	}
+
+	env.do(t, "POST", base+"/"+id+"/restore", nil, auth).expectError(t, fiber.StatusNotFound, apierror.CodeDocumentNotFound)
+	env.do(t, "DELETE", base+"/"+id, nil, auth).expectStatus(t, fiber.StatusOK)
+	env.do(t, "GET", base+"/"+id, nil, auth).expectError(t, fiber.StatusNotFound, apierror.CodeDocumentNotFound)
+	env.do(t, "GET", base+"/"+id+"?includeDeleted=true", nil, auth).expectStatus(t, fiber.StatusOK)
+
+	var list []map[string]interface{}
+	env.do(t, "GET", base, nil, auth).decode(t, &list)
+	if len(list) != 0 {
+		t.Fatalf("soft-deleted document is still listed: %v", list)
+	}
+
+	env.do(t, "POST", base+"/"+id+"/restore", nil, auth).expectStatus(t, fiber.StatusOK)
+	env.do(t, "GET", base+"/"+id, nil, auth).expectStatus(t, fiber.StatusOK)
+
+	env.do(t, "DELETE", base+"/"+id+"/purge", nil, auth).expectError(t, fiber.StatusNotFound, apierror.CodeDocumentNotFound)
+	env.do(t, "DELETE", base+"/"+id, nil, auth).expectStatus(t, fiber.StatusOK)
+	env.do(t, "DELETE", base+"/"+id+"/purge", nil, auth).expectStatus(t, fiber.StatusOK)
+	env.do(t, "GET", base+"/"+id+"?includeDeleted=true", nil, auth).expectError(t, fiber.StatusNotFound, apierror.CodeDocumentNotFound)
+}
+
+func TestGetFileOfSoftDeletedDocument(t *testing.T) {
+	cfg := *config.Default()
+	cfg.UploadDir = t.TempDir()
+	config.Set(&cfg)
+	t.Cleanup(func() { config.Set(nil) })
+
+	env := newTestEnv(t, nil)
+	project := env.createProject(t, "products")
+	env.projects.SaveCollectionSettings(models.CollectionSettings{ProjectID: project.ID, CollectionName: "products", SoftDelete: true})
+	auth := map[string]string{"X-API-Key": project.ApiKey}
+	id := createDocument(t, env, project, "products", map[string]string{"name": "Kopi", "image": "kopi.png"})
+
+	dir := filepath.Join(cfg.UploadDir, project.ID.Hex(), "products")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "kopi.png"), []byte("png"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	fileURL := "/api/v1/files/" + project.ID.Hex() + "/products/" + id + "/image"
+	env.do(t, "GET", fileURL, nil, nil).expectStatus(t, fiber.StatusOK)
+
+	env.do(t, "DELETE", "/api/v1/data/"+project.ID.Hex()+"/products/"+id, nil, auth).expectStatus(t, fiber.StatusOK)
+	env.do(t, "GET", fileURL, nil, nil).expectError(t, fiber.StatusNotFound, apierror.CodeDocumentNotFound)
+}
+
+func TestDocumentRevisions(t *testing.T) {
+	env := newTestEnv(t, nil)
+	project := env.createProject(t, "products")
+	env.projects.SaveCollectionSettings(models.CollectionSettings{ProjectID: project.ID, CollectionName: "products", Revisions: true})
+	auth := map[string]string{"X-API-Key": project.ApiKey}
+	base := "/api/v1/data/" + project.ID.Hex() + "/products"
+	id := createDocument(t, env, project, "products", map[string]string{"name": "Kopi"})
+
+	env.do(t, "PUT", base+"/"+id, map[string]interface{}{"name": "Kopi Susu"}, auth).expectStatus(t, fiber.StatusOK)
+
+	var revisions []models.DocumentRevision
+	env.do(t, "GET", base+"/"+id+"/revisions", nil, auth).decode(t, &revisions)
+	if len(revisions) != 2 || revisions[0].Revision != 2 || revisions[0].Operation != models.DocumentEventUpdate {
+		t.Fatalf("unexpected revisions (newest first): %+v", revisions)
+	}
+
+	env.do(t, "POST", base+"/"+id+"/revisions/1/restore", nil, auth).expectStatus(t, fiber.StatusOK)
+	var doc map[string]interface{}
+	env.do(t, "GET", base+"/"+id, nil, auth).decode(t, &doc)
+	if doc["name"] != "Kopi" {
+		t.Fatalf("document was not restored to revision 1: %v", doc)
+	}
+	env.do(t, "POST", base+"/"+id+"/revisions/9/restore", nil, auth).expectError(t, fiber.StatusNotFound, apierror.CodeRevisionNotFound)
+}
+
+func TestConcurrentRevisionsAreUnique(t *testing.T) {
+	env := newTestEnv(t, nil)
+	project := env.createProject(t, "products")
+	env.projects.SaveCollectionSettings(models.CollectionSettings{ProjectID: project.ID, CollectionName: "products", Revisions: true})
+	auth := map[string]string{"X-API-Key": project.ApiKey}
+	base := "/api/v1/data/" + project.ID.Hex() + "/products"
+	id := createDocument(t, env, project, "products", map[string]string{"name": "Kopi"})
+
+	const updates = 8
+	statuses := make([]int, updates)
+	var wg sync.WaitGroup
+	for i := 0; i < updates; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			statuses[i] = env.do(t, "PUT", base+"/"+id, map[string]interface{}{"stock": i}, auth).status
+		}(i)
+	}
+	wg.Wait()
+	for i, status := range statuses {
+		if status != fiber.StatusOK {
+			t.Fatalf("update %d: status = %d", i, status)
+		}
+	}
+
+	var revisions []models.DocumentRevision
+	env.do(t, "GET", base+"/"+id+"/revisions?limit=100", nil, auth).decode(t, &revisions)
+	seen := map[int]bool{}
+	for _, rev := range revisions {
+		if seen[rev.Revision] {
+			t.Fatalf("revision %d recorded twice: %+v", rev.Revision, revisions)
+		}
+		seen[rev.Revision] = true
+	}
+	if len(seen) != updates+1 {
+		t.Fatalf("got %d revisions, want %d (baseline + updates)", len(seen), updates+1)
+	}
+}
+
+func TestDocumentHookRejected(t *testing.T) {
+	env := newTestEnv(t, nil)
+	project := env.createProject(t, "products")
+	env.projects.AddCollectionHook(models.CollectionHook{
+		ProjectID:      project.ID,
+		CollectionName: "products",
+		Name:           "no-free-products",
+		Trigger:        models.HookBefore,
+		Event:          models.DocumentEventCreate,
+		Condition:      "doc.price <= 0",
+		Actions:        []models.HookAction{{Type: models.HookActionReject, Message: "price must be positive"}},
+		Active:         true,
+	})
+	base := "/api/v1/data/" + project.ID.Hex() + "/products"
+
+	resp := env.doForm(t, "POST", base, map[string]string{"name": "Gratis", "price": "0"}, map[string]string{"X-API-Key": project.ApiKey})
+	resp.expectError(t, fiber.StatusUnprocessableEntity, apierror.CodeHookRejected)
+	createDocument(t, env, project, "products", map[string]string{"name": "Kopi", "price": "25000"})
+
+	if got := env.events.operations(); len(got) != 1 {
+		t.Fatalf("events = %v, want only the accepted insert", got)
+	}
+}
+
+func TestAfterDeleteHookUpdatesSoftDeletedDocument(t *testing.T) {
+	env := newTestEnv(t, nil)
+	project := env.createProject(t, "products")
+	env.projects.SaveCollectionSettings(models.CollectionSettings{ProjectID: project.ID, CollectionName: "products", SoftDelete: true})
+	env.projects.AddCollectionHook(models.CollectionHook{
+		ProjectID:      project.ID,
+		CollectionName: "products",
+		Name:           "archive-note",
+		Trigger:        models.HookAfter,
+		Event:          models.DocumentEventDelete,
+		Condition:      `before.name == "Kopi"`,
+		Actions:        []models.HookAction{{Type: models.HookActionSet, Field: "archivedName", Expression: `upper(doc.name)`}},
+		Active:         true,
+	})
+	auth := map[string]string{"X-API-Key": project.ApiKey}
+	base := "/api/v1/data/" + project.ID.Hex() + "/products"
+	id := createDocument(t, env, project, "products", map[string]string{"name": "Kopi"})
+
+	env.do(t, "DELETE", base+"/"+id, nil, auth).expectStatus(t, fiber.StatusOK)
+
+	var doc map[string]interface{}
+	env.do(t, "GET", base+"/"+id+"?includeDeleted=true", nil, auth).decode(t, &doc)
+	if doc["archivedName"] != "KOPI" {
+		t.Fatalf("after-delete hook did not update the soft-deleted document: %v", doc)
+	}
+}
+
+// Kuota storage diperiksa di DocumentScope, jadi berlaku juga untuk penulisan di luar REST API (GraphQL)
+func TestDocumentScopeStorageQuota(t *testing.T) {
+	env := newTestEnv(t, nil)
+	project := env.createProject(t, "notes")
+	id := createDocument(t, env, project, "notes", map[string]string{"title": "first"})
+	docID, _ := primitive.ObjectIDFromHex(id)
+
+	scope, err := services.Documents().Open(context.Background(), project, "notes", models.Actor{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	scope.StorageLimit = 1
+
+	_, err = scope.Insert(context.Background(), bson.M{"title": "second"})
+	if got := apierror.From(err); got.Status != fiber.StatusInsufficientStorage || got.Code != apierror.CodeStorageQuotaExceeded {
+		t.Errorf("insert over quota = %v, want %s", err, apierror.CodeStorageQuotaExceeded)
+	}
+	_, err = scope.Update(context.Background(), docID, bson.M{"title": "changed"}, "")
+	if got := apierror.From(err); got.Code != apierror.CodeStorageQuotaExceeded {
+		t.Errorf("update over quota = %v, want %s", err, apierror.CodeStorageQuotaExceeded)
+	}
+	if got := len(env.events.operations()); got != 1 {
+		t.Errorf("events = %d, want only the first insert", got)
+	}
+}
+
+func TestPurgeRecordsRevisionAndEvent(t *testing.T) {
+	env := newTestEnv(t, nil)
+	project := env.createProject(t, "products")
+	env.projects.SaveCollectionSettings(models.CollectionSettings{ProjectID: project.ID, CollectionName: "products", SoftDelete: true, Revisions: true})
+	auth := map[string]string{"X-API-Key": project.ApiKey}
+	base := "/api/v1/data/" + project.ID.Hex() + "/products"
+	id := createDocument(t, env, project, "products", map[string]string{"name": "Kopi"})
+
+	env.do(t, "DELETE", base+"/"+id, nil, auth).expectStatus(t, fiber.StatusOK)
+	env.do(t, "DELETE", base+"/"+id+"/purge", nil, auth).expectStatus(t, fiber.StatusOK)
+
+	var revisions []models.DocumentRevision
+	env.do(t, "GET", base+"/"+id+"/revisions", nil, auth).decode(t, &revisions)
+	if len(revisions) != 3 || revisions[0].Operation != models.RevisionPurge || revisions[0].Snapshot["name"] != "Kopi" {
+		t.Fatalf("unexpected revisions (newest first): %+v", revisions)
+	}
+	if got, want := strings.Join(env.events.operations(), ","), "insert,delete,delete"; got != want {
+		t.Errorf("events = %s, want %s", got, want)
+	}
+}
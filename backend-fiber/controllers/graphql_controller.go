@@ -0,0 +1,143 @@
+// file: controllers/graphql_controller.go
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/fiber-mongo/starter-kit/apierror" // Sesuaikan dengan nama modul Anda
+	"github.com/fiber-mongo/starter-kit/database" // Sesuaikan dengan nama modul Anda
+	"github.com/fiber-mongo/starter-kit/hooks"    // Sesuaikan dengan nama modul Anda
+	"github.com/fiber-mongo/starter-kit/models"   // Sesuaikan dengan nama modul Anda
+	"github.com/fiber-mongo/starter-kit/services" // Sesuaikan dengan nama modul Anda
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Struct untuk body request GraphQL standar
+type graphQLRequest struct {
+	Query         string                 `json:"query"`
+	Variables     map[string]interface{} `json:"variables"`
+	OperationName string                 `json:"operationName"`
+}
+
+// Handler untuk GET/POST /graphql/{projectId} (GraphQL API yang dibuat dari koleksi aktif proyek)
+// Otentikasi memakai header X-API-Key yang sama dengan /data (lihat AuthMiddleware).
+func GraphQL(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), 30*time.Second)
+	defer cancel()
+
+	project, ok := c.Locals("project").(models.Project)
+	if !ok {
+		return apierror.New(fiber.StatusUnauthorized, apierror.CodeUnauthorized, "Unauthorized: Missing API Key")
+	}
+
+	var req graphQLRequest
+	if c.Method() == fiber.MethodGet {
+		req.Query = c.Query("query")
+		req.OperationName = c.Query("operationName")
+		if variables := c.Query("variables"); variables != "" {
+			if err := json.Unmarshal([]byte(variables), &req.Variables); err != nil {
+				return apierror.Validation("variables", "Invalid variables JSON")
+			}
+		}
+	} else if err := c.BodyParser(&req); err != nil {
+		return apierror.InvalidBody("Invalid request body")
+	}
+	if req.Query == "" {
+		return apierror.Validation("query", "Missing GraphQL query")
+	}
+
+	userDBClient, err := database.ProjectClient(ctx, project)
+	if err != nil {
+		return apierror.Internal(err, "Could not connect to user database")
+	}
+
+	userDB := userDBClient.Database(project.DBName)
+	schemas := map[string]bson.M{}
+	if len(project.ActiveCollections) > 0 {
+		specs, err := userDB.ListCollectionSpecifications(ctx, bson.M{"name": bson.M{"$in": project.ActiveCollections}})
+		if err != nil {
+			return apierror.Internal(err, "Failed to read collection schemas")
+		}
+		for _, spec := range specs {
+			var opts collectionOptions
+			if len(spec.Options) > 0 && bson.Unmarshal(spec.Options, &opts) == nil {
+				schemas[spec.Name] = jsonSchemaFromValidator(opts.Validator)
+			}
+		}
+	}
+
+	scopes, err := services.Documents().OpenAll(ctx, project, project.ActiveCollections, requestActor(c))
+	if err != nil {
+		return err
+	}
+	// Mutation create/update tunduk pada kuota storage yang sama dengan REST API
+	for _, scope := range scopes {
+		scope.StorageLimit = storageLimit(c)
+	}
+
+	schema, err := buildGraphQLSchema(scopes, schemas)
+	if err != nil {
+		return apierror.Internal(err, "Failed to build GraphQL schema")
+	}
+
+	result := graphql.Do(graphql.Params{
+		Schema:         schema,
+		RequestString:  req.Query,
+		VariableValues: req.Variables,
+		OperationName:  req.OperationName,
+		Context:        ctx,
+	})
+	publicGraphQLErrors(ctx, result)
+
+	return c.Status(fiber.StatusOK).JSON(result)
+}
+
+// Fungsi helper untuk mengganti error dari resolver dengan pesan publik dan kodenya di "extensions".
+// Error sintaks/validasi query (tanpa error asli) dibiarkan; penyebab error server hanya dicatat di log.
+func publicGraphQLErrors(ctx context.Context, result *graphql.Result) {
+	for i, formatted := range result.Errors {
+		cause := formatted.OriginalError()
+		var located *gqlerrors.Error
+		if errors.As(cause, &located) {
+			cause = located.OriginalError
+		}
+		if cause == nil {
+			continue
+		}
+
+		apiErr := graphQLResolverError(cause)
+		if apiErr.Status >= fiber.StatusInternalServerError {
+			slog.ErrorContext(ctx, apiErr.Message, "code", apiErr.Code, "error", errors.Unwrap(apiErr), "path", formatted.Path)
+		}
+		extensions := map[string]interface{}{"code": apiErr.Code}
+		if len(apiErr.Fields) > 0 {
+			extensions["fields"] = apiErr.Fields
+		}
+		result.Errors[i].Message = apiErr.Message
+		result.Errors[i].Extensions = extensions
+	}
+}
+
+// Fungsi helper untuk memetakan error resolver ke error API dengan aturan yang sama seperti endpoint REST
+func graphQLResolverError(err error) *apierror.Error {
+	var apiErr *apierror.Error
+	if errors.As(err, &apiErr) {
+		return apiErr
+	}
+	var hookErr *hooks.Error
+	if errors.As(err, &hookErr) {
+		return apierror.New(fiber.StatusUnprocessableEntity, apierror.CodeHookRejected, hookErr.Error()).Wrap(hookErr)
+	}
+	if errors.Is(err, services.ErrPreconditionFailed) {
+		return apierror.New(fiber.StatusPreconditionFailed, apierror.CodePreconditionFailed, "Document has been modified (ETag mismatch)")
+	}
+	return apierror.From(documentWriteError(err, "Internal server error"))
+}
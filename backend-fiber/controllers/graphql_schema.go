@@ -0,0 +1,550 @@
+// file: controllers/graphql_schema.go
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fiber-mongo/starter-kit/apierror" // Sesuaikan dengan nama modul Anda
+	"github.com/fiber-mongo/starter-kit/services" // Sesuaikan dengan nama modul Anda
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	graphQLDefaultLimit = 100
+	graphQLMaxLimit     = 1000
+)
+
+var graphQLNameSanitizer = regexp.MustCompile(`[^_0-9A-Za-z]`)
+
+// Operator query yang menjalankan JavaScript di server, tidak boleh dipakai dari filter GraphQL
+var forbiddenFilterOperators = map[string]bool{"$where": true, "$function": true, "$accumulator": true}
+
+// Scalar JSON untuk nilai bebas (filter, sub-dokumen, koleksi tanpa schema)
+var graphQLJSON = graphql.NewScalar(graphql.ScalarConfig{
+	Name:        "JSON",
+	Description: "Arbitrary JSON value",
+	Serialize:   func(value interface{}) interface{} { return value },
+	ParseValue:  func(value interface{}) interface{} { return value },
+	ParseLiteral: func(valueAST ast.Value) interface{} {
+		return parseGraphQLLiteral(valueAST)
+	},
+})
+
+func parseGraphQLLiteral(valueAST ast.Value) interface{} {
+	switch v := valueAST.(type) {
+	case *ast.StringValue:
+		return v.Value
+	case *ast.BooleanValue:
+		return v.Value
+	case *ast.EnumValue:
+		return v.Value
+	case *ast.IntValue:
+		if n, err := strconv.ParseInt(v.Value, 10, 64); err == nil {
+			return n
+		}
+	case *ast.FloatValue:
+		if f, err := strconv.ParseFloat(v.Value, 64); err == nil {
+			return f
+		}
+	case *ast.ListValue:
+		list := make([]interface{}, 0, len(v.Values))
+		for _, item := range v.Values {
+			list = append(list, parseGraphQLLiteral(item))
+		}
+		return list
+	case *ast.ObjectValue:
+		obj := make(map[string]interface{}, len(v.Fields))
+		for _, field := range v.Fields {
+			obj[field.Name.Value] = parseGraphQLLiteral(field.Value)
+		}
+		return obj
+	}
+	return nil
+}
+
+// Fungsi helper untuk membuat nama GraphQL yang valid dari nama koleksi/field
+func graphQLName(name string) string {
+	sanitized := graphQLNameSanitizer.ReplaceAllString(name, "_")
+	if sanitized == "" || (sanitized[0] >= '0' && sanitized[0] <= '9') {
+		sanitized = "_" + sanitized
+	}
+	return sanitized
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+// Fungsi helper untuk mengambil bsonType utama (bukan "null") dari definisi properti
+func primaryBSONType(def bson.M) string {
+	switch t := def["bsonType"].(type) {
+	case string:
+		return t
+	case bson.A:
+		for _, item := range t {
+			if s, ok := item.(string); ok && s != "null" {
+				return s
+			}
+		}
+	}
+	return ""
+}
+
+// Fungsi helper untuk memetakan definisi properti $jsonSchema ke tipe scalar/list GraphQL
+func graphQLFieldType(def bson.M) graphql.Type {
+	switch primaryBSONType(def) {
+	case "string", "date", "timestamp", "regex", "binData", "decimal":
+		return graphql.String
+	case "objectId":
+		return graphql.ID
+	case "int":
+		return graphql.Int
+	case "long", "double", "number":
+		return graphql.Float
+	case "bool":
+		return graphql.Boolean
+	case "array":
+		if items, ok := def["items"].(bson.M); ok {
+			if itemType := graphQLFieldType(items); itemType != graphQLJSON {
+				return graphql.NewList(itemType)
+			}
+		}
+		return graphql.NewList(graphQLJSON)
+	}
+	return graphQLJSON
+}
+
+// Fungsi helper untuk mengubah nilai dari MongoDB menjadi nilai yang bisa diserialisasi GraphQL/JSON
+func toGraphQLValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case bson.M:
+		out := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			out[k] = toGraphQLValue(item)
+		}
+		return out
+	case bson.D:
+		out := make(map[string]interface{}, len(v))
+		for _, elem := range v {
+			out[elem.Key] = toGraphQLValue(elem.Value)
+		}
+		return out
+	case bson.A:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			out[i] = toGraphQLValue(item)
+		}
+		return out
+	case primitive.ObjectID:
+		return v.Hex()
+	case primitive.DateTime:
+		return v.Time().UTC().Format(time.RFC3339Nano)
+	case primitive.Decimal128:
+		return v.String()
+	case primitive.Timestamp:
+		return time.Unix(int64(v.T), 0).UTC().Format(time.RFC3339)
+	}
+	return value
+}
+
+// Fungsi helper untuk menyesuaikan input GraphQL dengan schema koleksi (ObjectID dan tanggal)
+func coerceGraphQLInput(input map[string]interface{}, properties bson.M) bson.M {
+	doc := bson.M{}
+	for key, value := range input {
+		def, _ := properties[key].(bson.M)
+		if s, ok := value.(string); ok && def != nil {
+			switch primaryBSONType(def) {
+			case "objectId":
+				if oid, err := primitive.ObjectIDFromHex(s); err == nil {
+					doc[key] = oid
+					continue
+				}
+			case "date":
+				if t, err := time.Parse(time.RFC3339, s); err == nil {
+					doc[key] = t
+					continue
+				}
+			}
+		}
+		doc[key] = value
+	}
+	return doc
+}
+
+// Fungsi helper untuk memeriksa filter dari client dan mengubah "_id" berbentuk hex menjadi ObjectID
+func sanitizeGraphQLFilter(value interface{}, key string) (interface{}, error) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		out := bson.M{}
+		for k, item := range v {
+			if forbiddenFilterOperators[k] {
+				return nil, apierror.Validation("filter", fmt.Sprintf("operator '%s' is not allowed in filters", k))
+			}
+			childKey := k
+			if strings.HasPrefix(k, "$") {
+				childKey = key // Operator seperti $in/$eq tetap berlaku untuk field induknya
+			}
+			sanitized, err := sanitizeGraphQLFilter(item, childKey)
+			if err != nil {
+				return nil, err
+			}
+			out[k] = sanitized
+		}
+		return out, nil
+	case []interface{}:
+		out := bson.A{}
+		for _, item := range v {
+			sanitized, err := sanitizeGraphQLFilter(item, key)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, sanitized)
+		}
+		return out, nil
+	case string:
+		if key == "_id" {
+			if oid, err := primitive.ObjectIDFromHex(v); err == nil {
+				return oid, nil
+			}
+		}
+	}
+	return value, nil
+}
+
+// Fungsi helper untuk mengubah argumen sort ["-harga", "nama"] menjadi bson.D
+func graphQLSort(fields []interface{}) bson.D {
+	sort := bson.D{}
+	for _, f := range fields {
+		name, ok := f.(string)
+		if !ok || name == "" {
+			continue
+		}
+		direction := 1
+		if strings.HasPrefix(name, "-") {
+			direction, name = -1, name[1:]
+		}
+		sort = append(sort, bson.E{Key: name, Value: direction})
+	}
+	return sort
+}
+
+// Fungsi helper untuk mengambil satu dokumen dalam bentuk nilai GraphQL
+func findGraphQLDocument(ctx context.Context, scope *services.DocumentScope, id interface{}) (interface{}, error) {
+	var doc bson.M
+	err := scope.Collection.FindOne(ctx, scope.DocumentFilter(id)).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return toGraphQLValue(doc), nil
+}
+
+func documentFieldResolver(name string) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		if doc, ok := p.Source.(map[string]interface{}); ok {
+			return doc[name], nil
+		}
+		return nil, nil
+	}
+}
+
+// Fungsi helper untuk menambahkan query dan mutation satu koleksi ke schema GraphQL
+func addGraphQLCollection(queries, mutations graphql.Fields, scope *services.DocumentScope, typeName string, schema bson.M) {
+	collectionName := scope.Collection.Name()
+	properties, _ := schema["properties"].(bson.M)
+
+	objectFields := graphql.Fields{
+		"_id": &graphql.Field{Type: graphql.NewNonNull(graphql.ID), Resolve: documentFieldResolver("_id")},
+		"_document": &graphql.Field{
+			Type:        graphQLJSON,
+			Description: "The whole document, including fields not described by the collection schema",
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return p.Source, nil
+			},
+		},
+	}
+	inputFields := graphql.InputObjectConfigFieldMap{}
+	for name, def := range properties {
+		defMap, ok := def.(bson.M)
+		if !ok || name == "_id" || graphQLName(name) != name {
+			continue // Field dengan nama yang bukan nama GraphQL valid tetap bisa dibaca lewat _document
+		}
+		fieldType := graphQLFieldType(defMap)
+		description, _ := defMap["description"].(string)
+		objectFields[name] = &graphql.Field{Type: fieldType, Description: description, Resolve: documentFieldResolver(name)}
+		inputFields[name] = &graphql.InputObjectFieldConfig{Type: fieldType, Description: description}
+	}
+
+	objectType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        typeName,
+		Description: fmt.Sprintf("A document in the '%s' collection", collectionName),
+		Fields:      objectFields,
+	})
+
+	// Koleksi tanpa schema menerima input bebas berupa JSON
+	var inputType graphql.Input = graphQLJSON
+	if len(inputFields) > 0 {
+		inputType = graphql.NewInputObject(graphql.InputObjectConfig{Name: typeName + "Input", Fields: inputFields})
+	}
+
+	fieldName := graphQLFieldName(typeName)
+	idArgs := graphql.FieldConfigArgument{"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)}}
+
+	parseID := func(p graphql.ResolveParams) (primitive.ObjectID, error) {
+		id, err := primitive.ObjectIDFromHex(fmt.Sprint(p.Args["id"]))
+		if err != nil {
+			return id, apierror.InvalidObjectID("Document")
+		}
+		return id, nil
+	}
+	parseFilter := func(p graphql.ResolveParams) (bson.M, error) {
+		filter := bson.M{}
+		if raw, ok := p.Args["filter"]; ok && raw != nil {
+			sanitized, err := sanitizeGraphQLFilter(raw, "")
+			if err != nil {
+				return nil, err
+			}
+			sanitizedMap, isMap := sanitized.(bson.M)
+			if !isMap {
+				return nil, apierror.Validation("filter", "filter must be an object")
+			}
+			filter = sanitizedMap
+		}
+		// Dokumen yang sudah di-soft delete tidak terlihat lewat GraphQL
+		return scope.LiveFilter(filter), nil
+	}
+	parseInput := func(p graphql.ResolveParams) (bson.M, error) {
+		input, ok := p.Args["input"].(map[string]interface{})
+		if !ok {
+			return nil, apierror.Validation("input", "input must be an object")
+		}
+		return coerceGraphQLInput(input, properties), nil
+	}
+
+	queries[fieldName] = &graphql.Field{
+		Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(objectType))),
+		Description: fmt.Sprintf("List documents in '%s'", collectionName),
+		Args: graphql.FieldConfigArgument{
+			"filter": &graphql.ArgumentConfig{Type: graphQLJSON, Description: "MongoDB query filter"},
+			"sort":   &graphql.ArgumentConfig{Type: graphql.NewList(graphql.String), Description: `Field names, prefix with "-" for descending`},
+			"limit":  &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: graphQLDefaultLimit},
+			"skip":   &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: 0},
+		},
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			filter, err := parseFilter(p)
+			if err != nil {
+				return nil, err
+			}
+			limit, _ := p.Args["limit"].(int)
+			skip, _ := p.Args["skip"].(int)
+			if limit <= 0 || limit > graphQLMaxLimit {
+				return nil, apierror.Validation("limit", fmt.Sprintf("limit must be between 1 and %d", graphQLMaxLimit))
+			}
+			if skip < 0 {
+				return nil, apierror.Validation("skip", "skip must not be negative")
+			}
+			opts := options.Find().SetLimit(int64(limit)).SetSkip(int64(skip))
+			if sortArg, ok := p.Args["sort"].([]interface{}); ok && len(sortArg) > 0 {
+				opts.SetSort(graphQLSort(sortArg))
+			}
+
+			cursor, err := scope.Collection.Find(p.Context, filter, opts)
+			if err != nil {
+				return nil, err
+			}
+			var docs []bson.M
+			if err := cursor.All(p.Context, &docs); err != nil {
+				return nil, err
+			}
+			results := make([]interface{}, 0, len(docs))
+			for _, doc := range docs {
+				results = append(results, toGraphQLValue(doc))
+			}
+			return results, nil
+		},
+	}
+
+	queries[fieldName+"Count"] = &graphql.Field{
+		Type:        graphql.NewNonNull(graphql.Int),
+		Description: fmt.Sprintf("Count documents in '%s'", collectionName),
+		Args:        graphql.FieldConfigArgument{"filter": &graphql.ArgumentConfig{Type: graphQLJSON}},
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			filter, err := parseFilter(p)
+			if err != nil {
+				return nil, err
+			}
+			return scope.Collection.CountDocuments(p.Context, filter)
+		},
+	}
+
+	queries[fieldName+"ById"] = &graphql.Field{
+		Type:        objectType,
+		Description: fmt.Sprintf("Get one document from '%s'", collectionName),
+		Args:        idArgs,
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			id, err := parseID(p)
+			if err != nil {
+				return nil, err
+			}
+			return findGraphQLDocument(p.Context, scope, id)
+		},
+	}
+
+	mutations["create"+typeName] = &graphql.Field{
+		Type:        objectType,
+		Description: fmt.Sprintf("Create a document in '%s'", collectionName),
+		Args:        graphql.FieldConfigArgument{"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(inputType)}},
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			doc, err := parseInput(p)
+			if err != nil {
+				return nil, err
+			}
+			insertedID, err := scope.Insert(p.Context, doc)
+			if err != nil {
+				return nil, err
+			}
+			return findGraphQLDocument(p.Context, scope, insertedID)
+		},
+	}
+
+	mutations["update"+typeName] = &graphql.Field{
+		Type:        objectType,
+		Description: fmt.Sprintf("Update fields of a document in '%s'", collectionName),
+		Args: graphql.FieldConfigArgument{
+			"id":    &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+			"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(inputType)},
+		},
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			id, err := parseID(p)
+			if err != nil {
+				return nil, err
+			}
+			set, err := parseInput(p)
+			if err != nil {
+				return nil, err
+			}
+			if len(set) == 0 {
+				return nil, apierror.Validation("input", "input must contain at least one field")
+			}
+			found, err := scope.Update(p.Context, id, set, "")
+			if err != nil {
+				return nil, err
+			}
+			if !found {
+				return nil, apierror.NotFound(apierror.CodeDocumentNotFound, "Document not found")
+			}
+			return findGraphQLDocument(p.Context, scope, id)
+		},
+	}
+
+	mutations["delete"+typeName] = &graphql.Field{
+		Type:        graphql.NewNonNull(graphql.Boolean),
+		Description: fmt.Sprintf("Delete a document from '%s'. Returns false if it did not exist.", collectionName),
+		Args:        idArgs,
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			id, err := parseID(p)
+			if err != nil {
+				return nil, err
+			}
+			return scope.Delete(p.Context, id, "")
+		},
+	}
+}
+
+// Fungsi helper untuk nama field query koleksi dari nama tipenya (misal "User" -> "user")
+func graphQLFieldName(typeName string) string {
+	return strings.ToLower(typeName[:1]) + typeName[1:]
+}
+
+// Fungsi helper untuk semua nama yang dibuat addGraphQLCollection untuk satu koleksi:
+// tipe objek dan Input, field query (list, Count, ById) dan field mutation (create, update, delete)
+func graphQLGeneratedNames(typeName string) []string {
+	fieldName := graphQLFieldName(typeName)
+	return []string{
+		typeName, typeName + "Input",
+		fieldName, fieldName + "Count", fieldName + "ById",
+		"create" + typeName, "update" + typeName, "delete" + typeName,
+	}
+}
+
+// Fungsi helper untuk daftar nama yang tidak boleh dipakai koleksi: tipe root, scalar bawaan,
+// scalar JSON, dan field query bawaan "_collections"
+func reservedGraphQLNames() map[string]bool {
+	reserved := map[string]bool{}
+	for _, name := range []string{"Query", "Mutation", "Subscription", "String", "Int", "Float", "Boolean", "ID", "JSON", "_collections"} {
+		reserved[name] = true
+	}
+	return reserved
+}
+
+func anyGraphQLNameUsed(used map[string]bool, typeName string) bool {
+	for _, name := range graphQLGeneratedNames(typeName) {
+		if used[name] {
+			return true
+		}
+	}
+	return false
+}
+
+// Fungsi helper untuk membangun schema GraphQL dari koleksi aktif sebuah proyek.
+// schemas berisi $jsonSchema per koleksi (nil untuk koleksi tanpa validator).
+func buildGraphQLSchema(scopes []*services.DocumentScope, schemas map[string]bson.M) (graphql.Schema, error) {
+	queries := graphql.Fields{
+		"_collections": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String))),
+			Description: "Names of the collections exposed by this API",
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				names := make([]string, 0, len(scopes))
+				for _, scope := range scopes {
+					names = append(names, scope.Collection.Name())
+				}
+				return names, nil
+			},
+		},
+	}
+	mutations := graphql.Fields{}
+
+	used := reservedGraphQLNames()
+	for _, scope := range scopes {
+		typeName := capitalize(graphQLName(scope.Collection.Name()))
+		// Nama berawalan "__" dicadangkan untuk introspection GraphQL
+		if strings.HasPrefix(typeName, "__") {
+			typeName = "Collection" + typeName
+		}
+		// Dua koleksi bisa menghasilkan nama yang sama setelah disanitasi (misal "user-log" dan "user_log"),
+		// atau nama yang dibuat untuk koleksi bentrok dengan nama bawaan/nama koleksi lain
+		// (misal "string", "userInput" -> tipe UserInput, atau "userCount" -> query userCount)
+		for base, i := typeName, 2; anyGraphQLNameUsed(used, typeName); i++ {
+			typeName = fmt.Sprintf("%s%d", base, i)
+		}
+		for _, name := range graphQLGeneratedNames(typeName) {
+			used[name] = true
+		}
+		addGraphQLCollection(queries, mutations, scope, typeName, schemas[scope.Collection.Name()])
+	}
+
+	config := graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{Name: "Query", Fields: queries}),
+	}
+	if len(mutations) > 0 {
+		config.Mutation = graphql.NewObject(graphql.ObjectConfig{Name: "Mutation", Fields: mutations})
+	}
+	return graphql.NewSchema(config)
+}
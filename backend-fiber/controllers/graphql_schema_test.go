@@ -0,0 +1,122 @@
+// file: controllers/graphql_schema_test.go
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/fiber-mongo/starter-kit/apierror"   // Sesuaikan dengan nama modul Anda
+	"github.com/fiber-mongo/starter-kit/models"     // Sesuaikan dengan nama modul Anda
+	"github.com/fiber-mongo/starter-kit/repository" // Sesuaikan dengan nama modul Anda
+	"github.com/fiber-mongo/starter-kit/services"   // Sesuaikan dengan nama modul Anda
+
+	"github.com/graphql-go/graphql"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestBuildGraphQLSchemaReservedNames(t *testing.T) {
+	documents := repository.NewMemoryDocuments()
+	names := []string{"query", "mutation", "string", "ID", "JSON", "__type", "_collections", "user", "userInput", "user-log", "user_log", "userCount", "userById", "createUser"}
+
+	scopes := []*services.DocumentScope{}
+	schemas := map[string]bson.M{}
+	for _, name := range names {
+		coll, err := documents.Collection(context.Background(), models.Project{}, name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		scopes = append(scopes, &services.DocumentScope{Collection: coll})
+		// Schema dengan properti supaya tipe <T>Input ikut dibuat
+		schemas[name] = bson.M{"properties": bson.M{"title": bson.M{"bsonType": "string"}}}
+	}
+
+	schema, err := buildGraphQLSchema(scopes, schemas)
+	if err != nil {
+		t.Fatalf("buildGraphQLSchema: %v", err)
+	}
+	if schema.QueryType().Name() != "Query" || schema.MutationType().Name() != "Mutation" {
+		t.Fatalf("root types were replaced: %s, %s", schema.QueryType().Name(), schema.MutationType().Name())
+	}
+	if _, ok := schema.QueryType().Fields()["_collections"]; !ok {
+		t.Fatal("_collections query is missing")
+	}
+	// Setiap koleksi mendapat 3 query dan 3 mutation sendiri; nama yang bentrok akan saling menimpa
+	if got, want := len(schema.QueryType().Fields()), 1+3*len(names); got != want {
+		t.Errorf("query fields = %d, want %d", got, want)
+	}
+	if got, want := len(schema.MutationType().Fields()), 3*len(names); got != want {
+		t.Errorf("mutation fields = %d, want %d", got, want)
+	}
+	for _, field := range []string{"user", "userCount", "userById", "userCount2", "userCount2Count", "userById2", "createUser2"} {
+		if _, ok := schema.QueryType().Fields()[field]; !ok {
+			t.Errorf("query %s is missing", field)
+		}
+	}
+	for _, field := range []string{"createUser", "createUserCount2", "createCreateUser2"} {
+		if _, ok := schema.MutationType().Fields()[field]; !ok {
+			t.Errorf("mutation %s is missing", field)
+		}
+	}
+	for _, typeName := range []string{"Query2", "String2", "ID2", "JSON2", "Collection__type", "User", "UserInput2", "User_log", "User_log2"} {
+		if schema.Type(typeName) == nil {
+			t.Errorf("type %s was not generated", typeName)
+		}
+	}
+}
+
+func TestPublicGraphQLErrors(t *testing.T) {
+	documents := repository.NewMemoryDocuments()
+	coll, err := documents.Collection(context.Background(), models.Project{}, "user")
+	if err != nil {
+		t.Fatal(err)
+	}
+	schema, err := buildGraphQLSchema([]*services.DocumentScope{{Collection: coll}}, map[string]bson.M{})
+	if err != nil {
+		t.Fatalf("buildGraphQLSchema: %v", err)
+	}
+	// Resolver yang gagal dengan error driver mentah
+	schema.QueryType().AddFieldConfig("broken", &graphql.Field{
+		Type: graphql.String,
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return nil, errors.New("connection refused: mongodb://admin:secret@db:27017")
+		},
+	})
+
+	tests := []struct {
+		name    string
+		query   string
+		message string
+		code    interface{}
+	}{
+		{"validation error", `{ user(limit: 0) { _id } }`, "limit must be between 1 and 1000", apierror.CodeValidationFailed},
+		{"invalid id", `{ userById(id: "nope") { _id } }`, "Invalid Document ID format", apierror.CodeInvalidObjectID},
+		{"driver error", `{ broken }`, "Internal server error", apierror.CodeInternal},
+		{"syntax error", `{ user(`, "", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := graphql.Do(graphql.Params{Schema: schema, RequestString: tt.query, Context: context.Background()})
+			if len(result.Errors) != 1 {
+				t.Fatalf("errors = %v, want one error", result.Errors)
+			}
+			original := result.Errors[0].Message
+			publicGraphQLErrors(context.Background(), result)
+
+			got := result.Errors[0]
+			if tt.code == nil {
+				// Error sintaks dari GraphQL sendiri tidak diubah
+				if got.Message != original || got.Extensions != nil {
+					t.Errorf("syntax error was rewritten: %q %v", got.Message, got.Extensions)
+				}
+				return
+			}
+			if got.Message != tt.message {
+				t.Errorf("message = %q, want %q", got.Message, tt.message)
+			}
+			if got.Extensions["code"] != tt.code {
+				t.Errorf("extensions.code = %v, want %v", got.Extensions["code"], tt.code)
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+// file: controllers/health_controller.go
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/fiber-mongo/starter-kit/apierror" // Sesuaikan dengan nama modul Anda
+	"github.com/fiber-mongo/starter-kit/database" // Sesuaikan dengan nama modul Anda
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Handler untuk GET /healthz (Liveness: proses server masih berjalan)
+func Healthz(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"status": "ok"})
+}
+
+// Handler untuk GET /readyz (Readiness: database platform bisa dihubungi)
+func Readyz(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), 2*time.Second)
+	defer cancel()
+
+	if database.DB == nil {
+		return apierror.New(fiber.StatusServiceUnavailable, apierror.CodeServiceUnavailable, "Database not connected")
+	}
+	if err := database.DB.Ping(ctx, nil); err != nil {
+		return apierror.New(fiber.StatusServiceUnavailable, apierror.CodeServiceUnavailable, "Database ping failed").Wrap(err)
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"status": "ok"})
+}
@@ -0,0 +1,158 @@
+// file: controllers/hook_controller.go
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/fiber-mongo/starter-kit/apierror"   // Sesuaikan dengan nama modul Anda
+	"github.com/fiber-mongo/starter-kit/database"   // Sesuaikan dengan nama modul Anda
+	"github.com/fiber-mongo/starter-kit/hooks"      // Sesuaikan dengan nama modul Anda
+	"github.com/fiber-mongo/starter-kit/middleware" // Sesuaikan dengan nama modul Anda
+	"github.com/fiber-mongo/starter-kit/models"     // Sesuaikan dengan nama modul Anda
+	"github.com/fiber-mongo/starter-kit/services"   // Sesuaikan dengan nama modul Anda
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Handler untuk GET /projects/{id}/collections/{collName}/hooks (Daftar hook koleksi, sesuai urutan eksekusi)
+func GetCollectionHooks(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	projObjID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return apierror.InvalidObjectID("Project")
+	}
+
+	cursor, err := database.GetCollection("collection_hooks").Find(ctx,
+		bson.M{"projectId": projObjID, "collectionName": c.Params("collName")},
+		options.Find().SetSort(bson.D{{Key: "trigger", Value: -1}, {Key: "event", Value: 1}, {Key: "order", Value: 1}, {Key: "createdAt", Value: 1}}),
+	)
+	if err != nil {
+		return apierror.Internal(err, "Failed to fetch hooks")
+	}
+	hookList := []models.CollectionHook{}
+	if err := cursor.All(ctx, &hookList); err != nil {
+		return apierror.Internal(err, "Failed to decode hooks")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(hookList)
+}
+
+// Handler untuk POST /projects/{id}/collections/{collName}/hooks (Buat hook baru)
+func CreateCollectionHook(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	projObjID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return apierror.InvalidObjectID("Project")
+	}
+	if _, err := services.Projects().Get(ctx, projObjID); err != nil {
+		return err
+	}
+
+	var input models.HookInput
+	if err := c.BodyParser(&input); err != nil {
+		return apierror.InvalidBody("Invalid request body")
+	}
+
+	hook := models.CollectionHook{
+		ID:             primitive.NewObjectID(),
+		ProjectID:      projObjID,
+		CollectionName: c.Params("collName"),
+		Name:           input.Name,
+		Trigger:        input.Trigger,
+		Event:          input.Event,
+		Condition:      input.Condition,
+		Actions:        input.Actions,
+		Order:          input.Order,
+		Active:         input.Active == nil || *input.Active,
+		CreatedAt:      time.Now(),
+	}
+	if err := hooks.Validate(hook); err != nil {
+		return apierror.Validation("", err.Error())
+	}
+
+	c.Locals(middleware.AuditTargetIDKey, hook.ID.Hex())
+	if _, err := database.GetCollection("collection_hooks").InsertOne(ctx, hook); err != nil {
+		return apierror.Internal(err, "Failed to create hook")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(hook)
+}
+
+// Handler untuk PUT /projects/{id}/collections/{collName}/hooks/{hookId} (Ganti definisi hook)
+func UpdateCollectionHook(c *fiber.Ctx) error {
+	hookCollection := database.GetCollection("collection_hooks")
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	projObjID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return apierror.InvalidObjectID("Project")
+	}
+	hookObjID, err := primitive.ObjectIDFromHex(c.Params("hookId"))
+	if err != nil {
+		return apierror.InvalidObjectID("Hook")
+	}
+
+	var hook models.CollectionHook
+	filter := bson.M{"_id": hookObjID, "projectId": projObjID, "collectionName": c.Params("collName")}
+	if err := hookCollection.FindOne(ctx, filter).Decode(&hook); err != nil {
+		return apierror.NotFound(apierror.CodeHookNotFound, "Hook not found")
+	}
+
+	var input models.HookInput
+	if err := c.BodyParser(&input); err != nil {
+		return apierror.InvalidBody("Invalid request body")
+	}
+	hook.Name = input.Name
+	hook.Trigger = input.Trigger
+	hook.Event = input.Event
+	hook.Condition = input.Condition
+	hook.Actions = input.Actions
+	hook.Order = input.Order
+	if input.Active != nil {
+		hook.Active = *input.Active
+	}
+	if err := hooks.Validate(hook); err != nil {
+		return apierror.Validation("", err.Error())
+	}
+
+	if _, err := hookCollection.ReplaceOne(ctx, filter, hook); err != nil {
+		return apierror.Internal(err, "Failed to update hook")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(hook)
+}
+
+// Handler untuk DELETE /projects/{id}/collections/{collName}/hooks/{hookId} (Hapus hook)
+func DeleteCollectionHook(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	projObjID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return apierror.InvalidObjectID("Project")
+	}
+	hookObjID, err := primitive.ObjectIDFromHex(c.Params("hookId"))
+	if err != nil {
+		return apierror.InvalidObjectID("Hook")
+	}
+
+	result, err := database.GetCollection("collection_hooks").DeleteOne(ctx,
+		bson.M{"_id": hookObjID, "projectId": projObjID, "collectionName": c.Params("collName")})
+	if err != nil {
+		return apierror.Internal(err, "Failed to delete hook")
+	}
+	if result.DeletedCount == 0 {
+		return apierror.NotFound(apierror.CodeHookNotFound, "Hook not found")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Hook deleted successfully"})
+}
@@ -0,0 +1,35 @@
+// file: controllers/job_controller.go
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/fiber-mongo/starter-kit/apierror" // Sesuaikan dengan nama modul Anda
+	"github.com/fiber-mongo/starter-kit/jobs"     // Sesuaikan dengan nama modul Anda
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Handler untuk GET /projects/{id}/jobs/{jobId} (Status background job, misalnya migrasi schema)
+func GetJob(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	projObjID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return apierror.InvalidObjectID("Project")
+	}
+	jobObjID, err := primitive.ObjectIDFromHex(c.Params("jobId"))
+	if err != nil {
+		return apierror.InvalidObjectID("Job")
+	}
+
+	job, err := jobs.Get(ctx, projObjID, jobObjID)
+	if err != nil {
+		return apierror.NotFound(apierror.CodeJobNotFound, "Job not found")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(job)
+}
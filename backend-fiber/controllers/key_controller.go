@@ -7,6 +7,7 @@ import (
 	"encoding/hex"
 	"time"
 
+	"github.com/fiber-mongo/starter-kit/apierror" // Sesuaikan dengan nama modul Anda
 	"github.com/fiber-mongo/starter-kit/database" // Sesuaikan dengan nama modul Anda
 	"github.com/fiber-mongo/starter-kit/models"   // Sesuaikan dengan nama modul Anda
 
@@ -27,21 +28,21 @@ func generateSecureKey(length int) (string, error) {
 // Namun, dalam alur utama, logika ini sudah ada di CreateProject.
 func GenerateApiKey(c *fiber.Ctx) error {
 	keyCollection := database.GetCollection("keys") 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
 	defer cancel()
 
 	apiKey, err := generateSecureKey(16)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to generate API Key"})
+		return apierror.Internal(err, "Failed to generate API Key")
 	}
 	apiSecret, err := generateSecureKey(32)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to generate API Secret"})
+		return apierror.Internal(err, "Failed to generate API Secret")
 	}
 
 	hashedSecret, err := bcrypt.GenerateFromPassword([]byte(apiSecret), bcrypt.DefaultCost)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to process secret"})
+		return apierror.Internal(err, "Failed to process secret")
 	}
 
 	newKey := models.ApiKey{
@@ -51,7 +52,7 @@ func GenerateApiKey(c *fiber.Ctx) error {
 	}
 	_, err = keyCollection.InsertOne(ctx, newKey)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to save key"})
+		return apierror.Internal(err, "Failed to save key")
 	}
 
 	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
@@ -0,0 +1,557 @@
+// file: controllers/openapi_controller.go
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/fiber-mongo/starter-kit/apierror" // Sesuaikan dengan nama modul Anda
+	"github.com/fiber-mongo/starter-kit/database" // Sesuaikan dengan nama modul Anda
+	"github.com/fiber-mongo/starter-kit/models"   // Sesuaikan dengan nama modul Anda
+	"github.com/fiber-mongo/starter-kit/services" // Sesuaikan dengan nama modul Anda
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Pemetaan bsonType MongoDB ke type/format OpenAPI 3.1
+var bsonTypeToOpenAPI = map[string]fiber.Map{
+	"string":    {"type": "string"},
+	"objectId":  {"type": "string", "pattern": "^[0-9a-fA-F]{24}$"},
+	"int":       {"type": "integer", "format": "int32"},
+	"long":      {"type": "integer", "format": "int64"},
+	"double":    {"type": "number", "format": "double"},
+	"decimal":   {"type": "number"},
+	"number":    {"type": "number"},
+	"bool":      {"type": "boolean"},
+	"date":      {"type": "string", "format": "date-time"},
+	"timestamp": {"type": "string", "format": "date-time"},
+	"object":    {"type": "object"},
+	"array":     {"type": "array"},
+	"null":      {"type": "null"},
+	"binData":   {"type": "string", "contentEncoding": "base64"},
+}
+
+// Keyword $jsonSchema yang bisa disalin apa adanya ke schema OpenAPI
+var passthroughSchemaKeywords = []string{
+	"title", "description", "enum", "minimum", "maximum", "exclusiveMinimum", "exclusiveMaximum",
+	"minLength", "maxLength", "pattern", "minItems", "maxItems", "uniqueItems", "minProperties", "maxProperties",
+}
+
+var componentNameSanitizer = regexp.MustCompile(`[^a-zA-Z0-9._-]`)
+
+// Fungsi helper untuk mengubah $jsonSchema (dialek MongoDB) menjadi schema OpenAPI 3.1 / JSON Schema 2020-12
+func jsonSchemaToOpenAPI(schema bson.M) fiber.Map {
+	out := fiber.Map{}
+	if schema == nil {
+		return out
+	}
+
+	var types []string
+	switch bsonType := schema["bsonType"].(type) {
+	case string:
+		types = []string{bsonType}
+	case bson.A:
+		for _, t := range bsonType {
+			if s, ok := t.(string); ok {
+				types = append(types, s)
+			}
+		}
+	}
+	if len(types) == 1 {
+		for k, v := range bsonTypeToOpenAPI[types[0]] {
+			out[k] = v
+		}
+	} else if len(types) > 1 {
+		// OpenAPI 3.1 mengizinkan array pada "type", tapi format tidak bisa digabung
+		openAPITypes := []string{}
+		seen := map[string]bool{}
+		for _, t := range types {
+			if mapped, ok := bsonTypeToOpenAPI[t]; ok && !seen[mapped["type"].(string)] {
+				seen[mapped["type"].(string)] = true
+				openAPITypes = append(openAPITypes, mapped["type"].(string))
+			}
+		}
+		out["type"] = openAPITypes
+	} else if t, ok := schema["type"]; ok {
+		out["type"] = t
+	}
+
+	for _, keyword := range passthroughSchemaKeywords {
+		if v, ok := schema[keyword]; ok {
+			out[keyword] = v
+		}
+	}
+
+	if properties, ok := schema["properties"].(bson.M); ok {
+		props := fiber.Map{}
+		for name, def := range properties {
+			if defMap, ok := def.(bson.M); ok {
+				props[name] = jsonSchemaToOpenAPI(defMap)
+			}
+		}
+		out["properties"] = props
+		if _, hasType := out["type"]; !hasType {
+			out["type"] = "object"
+		}
+	}
+	if required, ok := schema["required"].(bson.A); ok && len(required) > 0 {
+		out["required"] = required
+	}
+	switch additional := schema["additionalProperties"].(type) {
+	case bool:
+		out["additionalProperties"] = additional
+	case bson.M:
+		out["additionalProperties"] = jsonSchemaToOpenAPI(additional)
+	}
+	switch items := schema["items"].(type) {
+	case bson.M:
+		out["items"] = jsonSchemaToOpenAPI(items)
+	case bson.A:
+		// Bentuk tuple lama (items berupa array) menjadi prefixItems di 2020-12
+		prefixItems := []fiber.Map{}
+		for _, item := range items {
+			if itemMap, ok := item.(bson.M); ok {
+				prefixItems = append(prefixItems, jsonSchemaToOpenAPI(itemMap))
+			}
+		}
+		out["prefixItems"] = prefixItems
+	}
+	for _, combinator := range []string{"anyOf", "oneOf", "allOf"} {
+		if options, ok := schema[combinator].(bson.A); ok {
+			converted := []fiber.Map{}
+			for _, option := range options {
+				if optionMap, ok := option.(bson.M); ok {
+					converted = append(converted, jsonSchemaToOpenAPI(optionMap))
+				}
+			}
+			out[combinator] = converted
+		}
+	}
+	return out
+}
+
+// Fungsi helper untuk membuat schema dokumen (respons) dari schema input: ditambah _id yang read-only
+func documentResponseSchema(input fiber.Map) fiber.Map {
+	doc := fiber.Map{"type": "object"}
+	for k, v := range input {
+		doc[k] = v
+	}
+	props := fiber.Map{"_id": fiber.Map{"type": "string", "pattern": "^[0-9a-fA-F]{24}$", "readOnly": true}}
+	if inputProps, ok := input["properties"].(fiber.Map); ok {
+		for k, v := range inputProps {
+			props[k] = v
+		}
+	}
+	doc["properties"] = props
+	return doc
+}
+
+// Komponen bersama di spesifikasi; nama komponen koleksi tidak boleh memakai nama ini
+func reservedOpenAPIComponents() map[string]bool {
+	return map[string]bool{"Error": true, "Message": true, "Revision": true, "RevisionDiff": true}
+}
+
+// Fungsi helper untuk memilih nama komponen koleksi (<name> dan <name>Input) yang belum dipakai.
+// Nama yang bentrok setelah disanitasi (misalnya "a b" dan "a_b") diberi akhiran angka.
+func openAPIComponentName(collectionName string, used map[string]bool) string {
+	base := componentNameSanitizer.ReplaceAllString(collectionName, "_")
+	name := base
+	for i := 2; used[name] || used[name+"Input"]; i++ {
+		name = fmt.Sprintf("%s%d", base, i)
+	}
+	used[name] = true
+	used[name+"Input"] = true
+	return name
+}
+
+// Fungsi helper untuk respons error dengan schema Error
+func openAPIErrorResponse(description string) fiber.Map {
+	return fiber.Map{
+		"description": description,
+		"content":     fiber.Map{"application/json": fiber.Map{"schema": fiber.Map{"$ref": "#/components/schemas/Error"}}},
+	}
+}
+
+// Fungsi helper untuk melengkapi respons sebuah operasi dengan error yang berlaku di semua rute Data API
+func openAPIResponses(responses fiber.Map) fiber.Map {
+	responses["401"] = openAPIErrorResponse("Missing or invalid API key")
+	responses["403"] = openAPIErrorResponse("Collection is not enabled for this project")
+	responses["429"] = openAPIErrorResponse("Rate limit or monthly request quota exceeded")
+	return responses
+}
+
+// Fungsi helper untuk menyusun semua path Data API satu koleksi (CRUD, restore/purge, dan revisi)
+func collectionPaths(collectionName, component string) fiber.Map {
+	docRef := fiber.Map{"$ref": "#/components/schemas/" + component}
+	inputRef := fiber.Map{"$ref": "#/components/schemas/" + component + "Input"}
+	messageResponse := fiber.Map{
+		"description": "Success",
+		"content":     fiber.Map{"application/json": fiber.Map{"schema": fiber.Map{"$ref": "#/components/schemas/Message"}}},
+	}
+	notModified := fiber.Map{"description": "Not modified (If-None-Match matches the current ETag)"}
+	docIDParam := fiber.Map{
+		"name": "docId", "in": "path", "required": true,
+		"schema": fiber.Map{"type": "string", "pattern": "^[0-9a-fA-F]{24}$"},
+	}
+	ifNoneMatchParam := fiber.Map{"name": "If-None-Match", "in": "header", "schema": fiber.Map{"type": "string"}}
+	ifMatchParam := fiber.Map{
+		"name": "If-Match", "in": "header", "schema": fiber.Map{"type": "string"},
+		"description": "Only write if the document still has this ETag",
+	}
+	tags := []string{collectionName}
+	base := "/" + collectionName
+
+	collectionPath := fiber.Map{
+		"get": fiber.Map{
+			"tags":        tags,
+			"summary":     fmt.Sprintf("List all documents in '%s'", collectionName),
+			"operationId": "list_" + component,
+			"parameters": []fiber.Map{
+				ifNoneMatchParam,
+				{"name": "includeDeleted", "in": "query", "schema": fiber.Map{"type": "boolean"}},
+			},
+			"responses": openAPIResponses(fiber.Map{
+				"200": fiber.Map{
+					"description": "All documents in the collection",
+					"content":     fiber.Map{"application/json": fiber.Map{"schema": fiber.Map{"type": "array", "items": docRef}}},
+				},
+				"304": notModified,
+			}),
+		},
+		"post": fiber.Map{
+			"tags":        tags,
+			"summary":     fmt.Sprintf("Create a document in '%s'", collectionName),
+			"operationId": "create_" + component,
+			"requestBody": fiber.Map{
+				"required": true,
+				"content":  fiber.Map{"multipart/form-data": fiber.Map{"schema": inputRef}},
+			},
+			"responses": openAPIResponses(fiber.Map{
+				"201": fiber.Map{
+					"description": "Document created",
+					"content": fiber.Map{"application/json": fiber.Map{"schema": fiber.Map{
+						"type":       "object",
+						"properties": fiber.Map{"InsertedID": fiber.Map{"type": "string"}},
+					}}},
+				},
+				"400": openAPIErrorResponse("Invalid form data"),
+				"422": openAPIErrorResponse("Rejected by a collection hook"),
+				"507": openAPIErrorResponse("Storage quota exceeded"),
+			}),
+		},
+	}
+
+	documentPath := fiber.Map{
+		"parameters": []fiber.Map{docIDParam},
+		"get": fiber.Map{
+			"tags":        tags,
+			"summary":     fmt.Sprintf("Get one document from '%s'", collectionName),
+			"operationId": "get_" + component,
+			"parameters":  []fiber.Map{ifNoneMatchParam},
+			"responses": openAPIResponses(fiber.Map{
+				"200": fiber.Map{"description": "The document", "content": fiber.Map{"application/json": fiber.Map{"schema": docRef}}},
+				"304": notModified,
+				"400": openAPIErrorResponse("Invalid document ID"),
+				"404": openAPIErrorResponse("Document not found"),
+			}),
+		},
+		"put": fiber.Map{
+			"tags":        tags,
+			"summary":     fmt.Sprintf("Update a document in '%s'", collectionName),
+			"operationId": "update_" + component,
+			"parameters":  []fiber.Map{ifMatchParam},
+			"requestBody": fiber.Map{
+				"required": true,
+				"content": fiber.Map{
+					"application/json":    fiber.Map{"schema": inputRef},
+					"multipart/form-data": fiber.Map{"schema": inputRef},
+				},
+			},
+			"responses": openAPIResponses(fiber.Map{
+				"200": messageResponse,
+				"400": openAPIErrorResponse("Invalid request body"),
+				"404": openAPIErrorResponse("Document not found"),
+				"412": openAPIErrorResponse("Document has been modified (If-Match does not match)"),
+				"422": openAPIErrorResponse("Rejected by a collection hook"),
+				"507": openAPIErrorResponse("Storage quota exceeded"),
+			}),
+		},
+		"delete": fiber.Map{
+			"tags":        tags,
+			"summary":     fmt.Sprintf("Delete a document from '%s'", collectionName),
+			"operationId": "delete_" + component,
+			"parameters":  []fiber.Map{ifMatchParam},
+			"responses": openAPIResponses(fiber.Map{
+				"200": messageResponse,
+				"404": openAPIErrorResponse("Document not found"),
+				"412": openAPIErrorResponse("Document has been modified (If-Match does not match)"),
+				"422": openAPIErrorResponse("Rejected by a collection hook"),
+			}),
+		},
+	}
+
+	restorePath := fiber.Map{
+		"parameters": []fiber.Map{docIDParam},
+		"post": fiber.Map{
+			"tags":        tags,
+			"summary":     fmt.Sprintf("Restore a soft-deleted document in '%s'", collectionName),
+			"operationId": "restore_" + component,
+			"responses": openAPIResponses(fiber.Map{
+				"200": messageResponse,
+				"404": openAPIErrorResponse("Deleted document not found"),
+			}),
+		},
+	}
+
+	purgePath := fiber.Map{
+		"parameters": []fiber.Map{docIDParam},
+		"delete": fiber.Map{
+			"tags":        tags,
+			"summary":     fmt.Sprintf("Permanently delete a soft-deleted document from '%s'", collectionName),
+			"operationId": "purge_" + component,
+			"responses": openAPIResponses(fiber.Map{
+				"200": messageResponse,
+				"404": openAPIErrorResponse("Deleted document not found"),
+			}),
+		},
+	}
+
+	revisionsPath := fiber.Map{
+		"parameters": []fiber.Map{docIDParam},
+		"get": fiber.Map{
+			"tags":        tags,
+			"summary":     fmt.Sprintf("List revisions of a document in '%s', newest first", collectionName),
+			"operationId": "listRevisions_" + component,
+			"parameters": []fiber.Map{
+				{"name": "limit", "in": "query", "schema": fiber.Map{"type": "integer", "minimum": 1, "maximum": maxRevisionLimit, "default": defaultRevisionLimit}},
+			},
+			"responses": openAPIResponses(fiber.Map{
+				"200": fiber.Map{
+					"description": "Revisions of the document",
+					"content": fiber.Map{"application/json": fiber.Map{"schema": fiber.Map{
+						"type": "array", "items": fiber.Map{"$ref": "#/components/schemas/Revision"},
+					}}},
+				},
+				"400": openAPIErrorResponse("Invalid limit"),
+			}),
+		},
+	}
+
+	diffPath := fiber.Map{
+		"parameters": []fiber.Map{docIDParam},
+		"get": fiber.Map{
+			"tags":        tags,
+			"summary":     fmt.Sprintf("Compare two revisions of a document in '%s'", collectionName),
+			"operationId": "diffRevisions_" + component,
+			"parameters": []fiber.Map{
+				{"name": "from", "in": "query", "required": true, "schema": fiber.Map{"type": "integer", "minimum": 1}},
+				{"name": "to", "in": "query", "schema": fiber.Map{"type": "integer", "minimum": 1}, "description": "Defaults to the latest revision"},
+			},
+			"responses": openAPIResponses(fiber.Map{
+				"200": fiber.Map{
+					"description": "Field changes between the two revisions",
+					"content":     fiber.Map{"application/json": fiber.Map{"schema": fiber.Map{"$ref": "#/components/schemas/RevisionDiff"}}},
+				},
+				"400": openAPIErrorResponse("Missing or invalid 'from'"),
+				"404": openAPIErrorResponse("Revision not found"),
+			}),
+		},
+	}
+
+	restoreRevisionPath := fiber.Map{
+		"parameters": []fiber.Map{
+			docIDParam,
+			{"name": "revision", "in": "path", "required": true, "schema": fiber.Map{"type": "integer", "minimum": 1}},
+		},
+		"post": fiber.Map{
+			"tags":        tags,
+			"summary":     fmt.Sprintf("Restore a document in '%s' to an earlier revision", collectionName),
+			"operationId": "restoreRevision_" + component,
+			"responses": openAPIResponses(fiber.Map{
+				"200": messageResponse,
+				"400": openAPIErrorResponse("Invalid revision number"),
+				"404": openAPIErrorResponse("Revision not found"),
+			}),
+		},
+	}
+
+	return fiber.Map{
+		base:                             collectionPath,
+		base + "/{docId}":                documentPath,
+		base + "/{docId}/restore":        restorePath,
+		base + "/{docId}/purge":          purgePath,
+		base + "/{docId}/revisions":      revisionsPath,
+		base + "/{docId}/revisions/diff": diffPath,
+		base + "/{docId}/revisions/{revision}/restore": restoreRevisionPath,
+	}
+}
+
+// Fungsi helper untuk menyusun dokumen OpenAPI 3.1 sebuah proyek
+func buildOpenAPISpec(project models.Project, baseURL string, schemas map[string]bson.M) fiber.Map {
+	paths := fiber.Map{}
+	components := fiber.Map{
+		"Error": fiber.Map{
+			"type": "object",
+			"properties": fiber.Map{
+				"error":     fiber.Map{"type": "string"},
+				"code":      fiber.Map{"type": "string"},
+				"fields":    fiber.Map{"type": "object", "additionalProperties": fiber.Map{"type": "string"}},
+				"requestId": fiber.Map{"type": "string"},
+			},
+			"required": []string{"error", "code"},
+		},
+		"Message": fiber.Map{
+			"type":       "object",
+			"properties": fiber.Map{"message": fiber.Map{"type": "string"}},
+		},
+		"Revision": fiber.Map{
+			"type": "object",
+			"properties": fiber.Map{
+				"id":         fiber.Map{"type": "string"},
+				"documentId": fiber.Map{"type": "string"},
+				"revision":   fiber.Map{"type": "integer"},
+				"operation":  fiber.Map{"type": "string", "enum": []string{"baseline", "create", "update", "delete", "restore", "purge"}},
+				"snapshot":   fiber.Map{"type": "object"},
+				"actor": fiber.Map{
+					"type":       "object",
+					"properties": fiber.Map{"userId": fiber.Map{"type": "string"}, "apiKeyId": fiber.Map{"type": "string"}},
+				},
+				"createdAt": fiber.Map{"type": "string", "format": "date-time"},
+			},
+		},
+		"RevisionDiff": fiber.Map{
+			"type": "object",
+			"properties": fiber.Map{
+				"documentId": fiber.Map{"type": "string"},
+				"from":       fiber.Map{"type": "integer"},
+				"to":         fiber.Map{"type": "integer"},
+				"changes": fiber.Map{
+					"type": "array",
+					"items": fiber.Map{
+						"type": "object",
+						"properties": fiber.Map{
+							"path":   fiber.Map{"type": "string"},
+							"change": fiber.Map{"type": "string", "enum": []string{"added", "removed", "changed"}},
+							"from":   fiber.Map{},
+							"to":     fiber.Map{},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	used := reservedOpenAPIComponents()
+	for _, collectionName := range project.ActiveCollections {
+		component := openAPIComponentName(collectionName, used)
+		input := jsonSchemaToOpenAPI(schemas[collectionName])
+		if _, ok := input["type"]; !ok {
+			input["type"] = "object"
+		}
+		components[component+"Input"] = input
+		components[component] = documentResponseSchema(input)
+
+		for path, item := range collectionPaths(collectionName, component) {
+			paths[path] = item
+		}
+	}
+
+	return fiber.Map{
+		"openapi": "3.1.0",
+		"info": fiber.Map{
+			"title":       project.Name + " API",
+			"version":     "1.0.0",
+			"description": "Auto-generated REST API for the active collections of this project.",
+		},
+		"servers": []fiber.Map{
+			{"url": fmt.Sprintf("%s/api/v1/data/%s", strings.TrimRight(baseURL, "/"), project.ID.Hex())},
+		},
+		"security": []fiber.Map{{"ApiKeyAuth": []string{}}},
+		"paths":    paths,
+		"components": fiber.Map{
+			"schemas": components,
+			"securitySchemes": fiber.Map{
+				"ApiKeyAuth": fiber.Map{"type": "apiKey", "in": "header", "name": "X-API-Key"},
+			},
+		},
+	}
+}
+
+// Handler untuk GET /projects/{id}/openapi.json (Spesifikasi OpenAPI yang dibuat otomatis)
+func GetProjectOpenAPI(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), 15*time.Second)
+	defer cancel()
+
+	projObjID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return apierror.InvalidObjectID("Project")
+	}
+
+	project, err := services.Projects().Get(ctx, projObjID)
+	if err != nil {
+		return err
+	}
+
+	schemas := map[string]bson.M{}
+	if len(project.ActiveCollections) > 0 {
+		userDBClient, err := database.ProjectClient(ctx, project)
+		if err != nil {
+			return apierror.Internal(err, "Could not connect to user database")
+		}
+
+		userDB := userDBClient.Database(project.DBName)
+		for _, collectionName := range project.ActiveCollections {
+			// Koleksi tanpa validator (atau belum dibuat) tetap didokumentasikan sebagai objek bebas
+			if opts, err := readCollectionOptions(ctx, userDB, collectionName); err == nil {
+				schemas[collectionName] = jsonSchemaFromValidator(opts.Validator)
+			}
+		}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(buildOpenAPISpec(project, c.BaseURL(), schemas))
+}
+
+// Halaman dokumentasi interaktif (Swagger UI dari CDN)
+const swaggerUIPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8" />
+  <title>%s - API Docs</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css" />
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js" crossorigin></script>
+  <script>
+    window.onload = () => {
+      window.ui = SwaggerUIBundle({ url: %q, dom_id: '#swagger-ui', persistAuthorization: true });
+    };
+  </script>
+</body>
+</html>`
+
+// Handler untuk GET /projects/{id}/docs (Halaman Swagger UI untuk spesifikasi OpenAPI proyek)
+func GetProjectAPIDocs(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), 15*time.Second)
+	defer cancel()
+
+	projObjID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return apierror.InvalidObjectID("Project")
+	}
+
+	project, err := services.Projects().Get(ctx, projObjID)
+	if err != nil {
+		return err
+	}
+
+	specURL := fmt.Sprintf("/api/v1/projects/%s/openapi.json", project.ID.Hex())
+	c.Type("html")
+	return c.Status(fiber.StatusOK).SendString(fmt.Sprintf(swaggerUIPage, html.EscapeString(project.Name), specURL))
+}
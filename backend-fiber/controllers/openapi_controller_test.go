@@ -0,0 +1,55 @@
+// file: controllers/openapi_controller_test.go
+package controllers
+
+import (
+	"testing"
+
+	"github.com/fiber-mongo/starter-kit/models" // Sesuaikan dengan nama modul Anda
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestBuildOpenAPISpecComponentNames(t *testing.T) {
+	project := models.Project{
+		ID:                primitive.NewObjectID(),
+		Name:              "Test",
+		ActiveCollections: []string{"a b", "a_b", "Error", "Revision", "foo", "fooInput"},
+	}
+	spec := buildOpenAPISpec(project, "http://localhost:8080", map[string]bson.M{})
+	components := spec["components"].(fiber.Map)["schemas"].(fiber.Map)
+
+	// 6 koleksi x 2 komponen + Error, Message, Revision, RevisionDiff
+	if got, want := len(components), 6*2+4; got != want {
+		t.Errorf("components = %d, want %d", got, want)
+	}
+	for _, name := range []string{"a_b", "a_b2", "Error2", "Error2Input", "Revision2", "foo", "fooInput", "fooInput2", "fooInput2Input"} {
+		if _, ok := components[name]; !ok {
+			t.Errorf("component %s is missing", name)
+		}
+	}
+	if _, ok := components["Error"].(fiber.Map)["properties"].(fiber.Map)["code"]; !ok {
+		t.Error("shared Error component was replaced by a collection schema")
+	}
+
+	paths := spec["paths"].(fiber.Map)
+	if got, want := len(paths), 6*7; got != want {
+		t.Errorf("paths = %d, want %d", got, want)
+	}
+	update := paths["/foo/{docId}"].(fiber.Map)["put"].(fiber.Map)["responses"].(fiber.Map)
+	for _, status := range []string{"200", "400", "401", "403", "404", "412", "422", "429", "507"} {
+		if _, ok := update[status]; !ok {
+			t.Errorf("update response %s is missing", status)
+		}
+	}
+	list := paths["/foo"].(fiber.Map)["get"].(fiber.Map)["responses"].(fiber.Map)
+	if _, ok := list["304"]; !ok {
+		t.Error("list response 304 is missing")
+	}
+	for _, path := range []string{"/foo/{docId}/restore", "/foo/{docId}/purge", "/foo/{docId}/revisions", "/foo/{docId}/revisions/diff", "/foo/{docId}/revisions/{revision}/restore"} {
+		if _, ok := paths[path]; !ok {
+			t.Errorf("path %s is missing", path)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"net/http"
 	"time"
+	"github.com/fiber-mongo/starter-kit/apierror" // Sesuaikan dengan path modulmu
 	"github.com/fiber-mongo/starter-kit/database" // Sesuaikan dengan path modulmu
 	"github.com/fiber-mongo/starter-kit/models"   // Sesuaikan dengan path modulmu
 
@@ -14,19 +15,19 @@ import (
 // Handler untuk mendapatkan semua produk
 func GetAllProducts(c *fiber.Ctx) error {
 	productCollection := database.GetCollection("produk")
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
 	defer cancel()
 
 	var products []models.Product
 
 	cursor, err := productCollection.Find(ctx, bson.M{})
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return apierror.Internal(err, "Failed to fetch products")
 	}
 	defer cursor.Close(ctx)
 
 	if err = cursor.All(ctx, &products); err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return apierror.Internal(err, "Failed to decode products")
 	}
     
     if products == nil {
@@ -39,20 +40,20 @@ func GetAllProducts(c *fiber.Ctx) error {
 // Handler untuk membuat produk baru
 func CreateProduct(c *fiber.Ctx) error {
 	productCollection := database.GetCollection("produk")
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
 	defer cancel()
 
 	product := new(models.Product)
 
 	// Parse body request ke struct Product
 	if err := c.BodyParser(product); err != nil {
-		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+		return apierror.InvalidBody("Invalid request body")
 	}
 
 	// Masukkan ke database
 	result, err := productCollection.InsertOne(ctx, product)
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to create product"})
+		return apierror.Internal(err, "Failed to create product")
 	}
 
 	return c.Status(http.StatusCreated).JSON(fiber.Map{"result": result})
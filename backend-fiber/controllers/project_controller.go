@@ -5,11 +5,17 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"net/url"
+	"io"
+	"log/slog"
+	"strings"
 	"time"
 
-	"github.com/fiber-mongo/starter-kit/database" // Sesuaikan dengan nama modul Anda
-	"github.com/fiber-mongo/starter-kit/models"   // Sesuaikan dengan nama modul Anda
+	"github.com/fiber-mongo/starter-kit/apierror"   // Sesuaikan dengan nama modul Anda
+	"github.com/fiber-mongo/starter-kit/database"   // Sesuaikan dengan nama modul Anda
+	"github.com/fiber-mongo/starter-kit/jobs"       // Sesuaikan dengan nama modul Anda
+	"github.com/fiber-mongo/starter-kit/middleware" // Sesuaikan dengan nama modul Anda
+	"github.com/fiber-mongo/starter-kit/models"     // Sesuaikan dengan nama modul Anda
+	"github.com/fiber-mongo/starter-kit/services"   // Sesuaikan dengan nama modul Anda
 
 	"github.com/gofiber/fiber/v2"
 	"go.mongodb.org/mongo-driver/bson"
@@ -19,159 +25,193 @@ import (
 )
 
 func CreateProject(c *fiber.Ctx) error {
-	projectCollection := database.GetCollection("projects")
-	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	ctx, cancel := context.WithTimeout(c.UserContext(), 20*time.Second)
 	defer cancel()
 
 	var input models.ProjectInput
 	if err := c.BodyParser(&input); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+		return apierror.InvalidBody("Invalid request body")
+	}
+	if err := readCertificateFiles(c, &input); err != nil {
+		return err
 	}
 
-	if input.DBPort == "" {
-		input.DBPort = "27017"
+	project, err := services.Projects().Create(ctx, input)
+	if err != nil {
+		return err
 	}
-	if input.AuthSource == "" {
-		input.AuthSource = "admin"
+	c.Locals(middleware.AuditProjectIDKey, project.ID)
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Project connected and saved successfully!",
+		"result":  fiber.Map{"InsertedID": project.ID},
+		"apiKey":  project.ApiKey, // Kirimkan API Key ke frontend
+	})
+}
+
+// Handler untuk POST /projects/test-connection (Uji detail koneksi tanpa menyimpan proyek)
+func TestConnection(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), 20*time.Second)
+	defer cancel()
+
+	var input models.ProjectInput
+	if err := c.BodyParser(&input); err != nil {
+		return apierror.InvalidBody("Invalid request body")
+	}
+	if err := readCertificateFiles(c, &input); err != nil {
+		return err
 	}
 
-	encodedPassword := url.QueryEscape(input.DBPassword)
-	uri := fmt.Sprintf("mongodb://%s:%s@%s:%s/%s?authSource=%s",
-		input.DBUser, encodedPassword, input.DBHost, input.DBPort, input.DBName, input.AuthSource)
-	if input.DBUser == "" || input.DBPassword == "" {
-		uri = fmt.Sprintf("mongodb://%s:%s/%s", input.DBHost, input.DBPort, input.DBName)
+	if err := services.Projects().TestConnection(ctx, input); err != nil {
+		return err
 	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Connection successful"})
+}
+
+// Handler untuk POST /projects/{id}/connection/test (Uji detail koneksi baru proyek tanpa menyimpannya)
+func TestProjectConnection(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), 20*time.Second)
+	defer cancel()
 
-	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	projObjID, err := primitive.ObjectIDFromHex(c.Params("id"))
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Failed to build valid MongoDB URI from provided details."})
+		return apierror.InvalidObjectID("Project")
 	}
-	err = client.Ping(ctx, nil)
-	if err != nil {
-		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "Failed to connect. Check your connection details and firewall."})
+
+	var input models.ProjectInput
+	if err := c.BodyParser(&input); err != nil {
+		return apierror.InvalidBody("Invalid request body")
 	}
-	defer client.Disconnect(ctx)
+	if err := readCertificateFiles(c, &input); err != nil {
+		return err
+	}
+
+	if err := services.Projects().TestProjectConnection(ctx, projObjID, input); err != nil {
+		return err
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Connection successful"})
+}
 
-	// Generate API Key unik untuk proyek ini
-	apiKey, err := generateSecureKey(16) // Memanggil helper dari key_controller.go
+// Handler untuk PUT /projects/{id}/connection (Ubah detail koneksi database user).
+// Password dan sertifikat yang dikosongkan tetap memakai nilai lama.
+func UpdateProjectConnection(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), 20*time.Second)
+	defer cancel()
+
+	projObjID, err := primitive.ObjectIDFromHex(c.Params("id"))
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to generate API Key"})
+		return apierror.InvalidObjectID("Project")
 	}
 
-	newProject := models.Project{
-		Name:       input.Name,
-		DBHost:     input.DBHost,
-		DBPort:     input.DBPort,
-		DBUser:     input.DBUser,
-		DBPassword: input.DBPassword,
-		DBName:     input.DBName,
-		AuthSource: input.AuthSource,
-		CreatedAt:  time.Now(),
-		ApiKey:     apiKey, // Simpan API Key yang baru dibuat
+	var input models.ProjectInput
+	if err := c.BodyParser(&input); err != nil {
+		return apierror.InvalidBody("Invalid request body")
+	}
+	if err := readCertificateFiles(c, &input); err != nil {
+		return err
 	}
 
-	result, err := projectCollection.InsertOne(ctx, newProject)
+	project, err := services.Projects().UpdateConnection(ctx, projObjID, input)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to save the project"})
+		return err
 	}
+	return c.Status(fiber.StatusOK).JSON(project)
+}
 
-	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
-		"message": "Project connected and saved successfully!",
-		"result":  result,
-		"apiKey":  apiKey, // Kirimkan API Key ke frontend
-	})
+// Ukuran maksimal file sertifikat PEM yang diunggah
+const maxCertificateSize = 64 * 1024
+
+// Fungsi helper untuk membaca sertifikat TLS yang diunggah sebagai file (multipart/form-data).
+// File menggantikan isi PEM yang dikirim sebagai teks dengan nama field yang sama.
+func readCertificateFiles(c *fiber.Ctx, input *models.ProjectInput) error {
+	fields := map[string]*string{"tlsCACert": &input.TLSCACert, "tlsClientCert": &input.TLSClientCert}
+	for field, target := range fields {
+		fileHeader, err := c.FormFile(field)
+		if err != nil {
+			continue // Bukan multipart atau file tidak diunggah
+		}
+		if fileHeader.Size > maxCertificateSize {
+			return apierror.Validation(field, "Certificate file is too large")
+		}
+		file, err := fileHeader.Open()
+		if err != nil {
+			return apierror.Internal(err, "Failed to read uploaded certificate")
+		}
+		data, err := io.ReadAll(file)
+		file.Close()
+		if err != nil {
+			return apierror.Internal(err, "Failed to read uploaded certificate")
+		}
+		*target = string(data)
+	}
+	return nil
 }
 
 func GetAllProjects(c *fiber.Ctx) error {
-	projectCollection := database.GetCollection("projects")
-	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	ctx, cancel := context.WithTimeout(c.UserContext(), 15*time.Second)
 	defer cancel()
 
-	var projects []models.Project
-
-	cursor, err := projectCollection.Find(ctx, bson.M{})
+	projects, err := services.Projects().List(ctx)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch projects"})
-	}
-	defer cursor.Close(ctx)
-
-	if err = cursor.All(ctx, &projects); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to decode projects"})
-	}
-
-	if projects == nil {
-		projects = []models.Project{}
+		return err
 	}
 
 	return c.Status(fiber.StatusOK).JSON(projects)
 }
 
 func DeleteProject(c *fiber.Ctx) error {
-	projectCollection := database.GetCollection("projects")
-	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	ctx, cancel := context.WithTimeout(c.UserContext(), 15*time.Second)
 	defer cancel()
 
 	projectIdStr := c.Params("id")
 	objID, err := primitive.ObjectIDFromHex(projectIdStr)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid Project ID format"})
-	}
-
-	result, err := projectCollection.DeleteOne(ctx, bson.M{"_id": objID})
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to delete project"})
+		return apierror.InvalidObjectID("Project")
 	}
 
-	if result.DeletedCount == 0 {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Project not found to delete"})
+	if err := services.Projects().Delete(ctx, objID); err != nil {
+		return err
 	}
 
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Project deleted successfully"})
 }
 
+// Handler untuk GET /projects/{id}/collections (Daftar koleksi beserta metadata dan statistiknya)
 func ListCollections(c *fiber.Ctx) error {
-	projectCollection := database.GetCollection("projects")
-	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	ctx, cancel := context.WithTimeout(c.UserContext(), 15*time.Second)
 	defer cancel()
 
 	projObjID, err := primitive.ObjectIDFromHex(c.Params("id"))
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid Project ID format"})
+		return apierror.InvalidObjectID("Project")
 	}
 
-	var project models.Project
-	err = projectCollection.FindOne(ctx, bson.M{"_id": projObjID}).Decode(&project)
+	project, err := services.Projects().Get(ctx, projObjID)
 	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Project not found"})
+		return err
 	}
 
-	userDBClient, err := connectToUserDB(ctx, project)
+	userDBClient, err := database.ProjectClient(ctx, project)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Could not connect to user database"})
+		return apierror.Internal(err, "Could not connect to user database")
 	}
-	defer userDBClient.Disconnect(ctx)
 
-	collectionNames, err := userDBClient.Database(project.DBName).ListCollectionNames(ctx, bson.M{})
+	collections, err := readCollectionInfos(ctx, userDBClient.Database(project.DBName), project.ActiveCollections)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to list collections"})
-	}
-
-	if collectionNames == nil {
-		collectionNames = []string{}
+		return apierror.Internal(err, "Failed to list collections")
 	}
 
-	return c.Status(fiber.StatusOK).JSON(collectionNames)
+	return c.Status(fiber.StatusOK).JSON(collections)
 }
 
 // Handler untuk PUT /projects/{id} (Update detail proyek, termasuk ActiveCollections)
 func UpdateProject(c *fiber.Ctx) error {
-	projectCollection := database.GetCollection("projects")
-	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	ctx, cancel := context.WithTimeout(c.UserContext(), 15*time.Second)
 	defer cancel()
 
 	projObjID, err := primitive.ObjectIDFromHex(c.Params("id"))
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid Project ID format"})
+		return apierror.InvalidObjectID("Project")
 	}
 
 	var payload struct {
@@ -179,56 +219,65 @@ func UpdateProject(c *fiber.Ctx) error {
 	}
 
 	if err := c.BodyParser(&payload); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+		return apierror.InvalidBody("Invalid request body")
 	}
 
-	update := bson.M{"$set": bson.M{"activeCollections": payload.ActiveCollections}}
-	_, err = projectCollection.UpdateOne(ctx, bson.M{"_id": projObjID}, update)
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to update project settings"})
+	if err := services.Projects().SetActiveCollections(ctx, projObjID, payload.ActiveCollections); err != nil {
+		return err
 	}
 
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Project settings updated successfully"})
 }
 
 func CreateUserCollection(c *fiber.Ctx) error {
-	projectCollection := database.GetCollection("projects")
-	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	ctx, cancel := context.WithTimeout(c.UserContext(), 15*time.Second)
 	defer cancel()
 
 	// 1. Ambil projectId dan validasi
 	projObjID, err := primitive.ObjectIDFromHex(c.Params("id"))
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid Project ID format"})
+		return apierror.InvalidObjectID("Project")
 	}
 
 	// 2. Parse payload dari frontend
 	var input models.CreateCollectionInput
 	if err := c.BodyParser(&input); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+		return apierror.InvalidBody("Invalid request body")
 	}
 
+	c.Locals(middleware.AuditTargetIDKey, input.CollectionName)
+
 	if input.CollectionName == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Collection name cannot be empty"})
+		return apierror.Validation("collectionName", "Collection name cannot be empty")
+	}
+	if strings.HasSuffix(input.CollectionName, models.RevisionCollectionSuffix) {
+		return apierror.Validation("collectionName", fmt.Sprintf("Collection names ending in '%s' are reserved", models.RevisionCollectionSuffix))
+	}
+	if err := validateValidationSettings(input.ValidationLevel, input.ValidationAction); err != nil {
+		return apierror.Validation("", err.Error())
 	}
 
 	// 3. Ambil detail proyek dan konek ke DB user
-	var project models.Project
-	err = projectCollection.FindOne(ctx, bson.M{"_id": projObjID}).Decode(&project)
+	project, err := services.Projects().Get(ctx, projObjID)
 	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Project not found"})
+		return err
 	}
-	userDBClient, err := connectToUserDB(ctx, project)
+	userDBClient, err := database.ProjectClient(ctx, project)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Could not connect to user database"})
+		return apierror.Internal(err, "Could not connect to user database")
 	}
-	defer userDBClient.Disconnect(ctx)
 
 	// 4. Siapkan opsi untuk membuat koleksi dengan schema validator
 	collectionOptions := options.CreateCollection()
 	if input.Schema != nil && len(input.Schema) > 0 {
 		validator := bson.M{"$jsonSchema": input.Schema}
 		collectionOptions.SetValidator(validator)
+		if input.ValidationLevel != "" {
+			collectionOptions.SetValidationLevel(input.ValidationLevel)
+		}
+		if input.ValidationAction != "" {
+			collectionOptions.SetValidationAction(input.ValidationAction)
+		}
 	}
 
 	// 5. Jalankan perintah CreateCollection
@@ -240,11 +289,35 @@ func CreateUserCollection(c *fiber.Ctx) error {
 		if errors.As(err, &cmdErr) {
 			// Kode 48 adalah "NamespaceExists", artinya collection sudah ada
 			if cmdErr.Code == 48 {
-				return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": fmt.Sprintf("Collection '%s' already exists.", input.CollectionName)})
+				return apierror.Newf(fiber.StatusConflict, apierror.CodeCollectionExists, "Collection '%s' already exists.", input.CollectionName)
 			}
 		}
 		// Jika error lain, kembalikan sebagai server error
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to create collection"})
+		return apierror.Internal(err, "Failed to create collection")
+	}
+
+	// Catat schema awal sebagai versi pertama
+	if len(input.Schema) > 0 {
+		now := time.Now()
+		version := models.SchemaVersion{
+			ProjectID:        project.ID,
+			CollectionName:   input.CollectionName,
+			Schema:           input.Schema,
+			ValidationLevel:  input.ValidationLevel,
+			ValidationAction: input.ValidationAction,
+			Status:           models.SchemaVersionApplied,
+			AppliedAt:        &now,
+		}
+		if version.ValidationLevel == "" {
+			version.ValidationLevel = "strict"
+		}
+		if version.ValidationAction == "" {
+			version.ValidationAction = "error"
+		}
+		// Koleksi sudah dibuat; versi schema yang gagal dicatat hanya dilaporkan di log
+		if err := insertSchemaVersion(ctx, &version); err != nil {
+			slog.WarnContext(ctx, "collections: failed to record schema version", "projectId", project.ID.Hex(), "collection", input.CollectionName, "error", err)
+		}
 	}
 
 	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
@@ -252,96 +325,251 @@ func CreateUserCollection(c *fiber.Ctx) error {
 	})
 }
 
+// Handler untuk PUT /projects/{id}/collections/{collName} (Ubah schema validator koleksi).
+// Setiap perubahan disimpan sebagai versi baru. Dengan "dryRun" hanya dilaporkan jumlah dokumen yang akan melanggar,
+// dan jika ada "migrations" maka migrasi data dijalankan sebagai background job sebelum validator diterapkan.
 func UpdateCollection(c *fiber.Ctx) error {
-	projectCollection := database.GetCollection("projects")
-	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	ctx, cancel := context.WithTimeout(c.UserContext(), 15*time.Second)
 	defer cancel()
 
 	projObjID, err := primitive.ObjectIDFromHex(c.Params("id"))
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid Project ID format"})
+		return apierror.InvalidObjectID("Project")
 	}
 	collectionName := c.Params("collName")
 
-	var project models.Project
-	err = projectCollection.FindOne(ctx, bson.M{"_id": projObjID}).Decode(&project)
+	project, err := services.Projects().Get(ctx, projObjID)
 	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Project not found"})
+		return err
 	}
 
-	userDBClient, err := connectToUserDB(ctx, project)
+	userDBClient, err := database.ProjectClient(ctx, project)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Could not connect to user database"})
+		return apierror.Internal(err, "Could not connect to user database")
 	}
-	defer userDBClient.Disconnect(ctx)
 
-	var payload struct {
-		Schema bson.M `json:"schema"`
-	}
+	var payload models.UpdateCollectionInput
 	if err := c.BodyParser(&payload); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+		return apierror.InvalidBody("Invalid request body")
+	}
+	if err := validateValidationSettings(payload.ValidationLevel, payload.ValidationAction); err != nil {
+		return apierror.Validation("", err.Error())
 	}
+	if payload.Schema == nil {
+		payload.Schema = bson.M{}
+	}
+
+	pipeline, err := migrationPipeline(payload.Migrations)
+	if err != nil {
+		return apierror.Validation("", err.Error())
+	}
+
+	userDB := userDBClient.Database(project.DBName)
+
+	// validationLevel/validationAction yang tidak dikirim tetap memakai pengaturan koleksi saat ini
+	if payload.ValidationLevel == "" || payload.ValidationAction == "" {
+		current, err := readCollectionOptions(ctx, userDB, collectionName)
+		if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+			return apierror.Internal(err, "Failed to read collection options")
+		}
+		if payload.ValidationLevel == "" {
+			payload.ValidationLevel = current.ValidationLevel
+		}
+		if payload.ValidationAction == "" {
+			payload.ValidationAction = current.ValidationAction
+		}
+	}
+	if payload.ValidationLevel == "" {
+		payload.ValidationLevel = "strict"
+	}
+	if payload.ValidationAction == "" {
+		payload.ValidationAction = "error"
+	}
+
+	if payload.DryRun || c.QueryBool("dryRun") {
+		report, err := dryRunSchemaChange(ctx, userDB.Collection(collectionName), pipeline, payload.Schema)
+		if err != nil {
+			return apierror.Internal(err, "Failed to evaluate schema against existing documents")
+		}
+		report.MigrationSteps = len(payload.Migrations)
+		return c.Status(fiber.StatusOK).JSON(report)
+	}
+
+	version := models.SchemaVersion{
+		ProjectID:        project.ID,
+		CollectionName:   collectionName,
+		Schema:           payload.Schema,
+		ValidationLevel:  payload.ValidationLevel,
+		ValidationAction: payload.ValidationAction,
+		Migrations:       payload.Migrations,
+		Status:           models.SchemaVersionPending,
+	}
+
+	// Ada migrasi: simpan versi sebagai "pending" lalu jalankan migrasi + collMod di background
+	if len(payload.Migrations) > 0 {
+		if err := insertSchemaVersion(ctx, &version); err != nil {
+			return apierror.Internal(err, "Failed to save schema version")
+		}
+		job, err := jobs.Start(ctx, project.ID, "schema_migration",
+			bson.M{"collectionName": collectionName, "version": version.Version},
+			schemaMigrationJob(project, version, pipeline))
+		if err != nil {
+			setSchemaVersionStatus(ctx, version.ID, models.SchemaVersionFailed, "failed to start migration job")
+			return apierror.Internal(err, "Failed to start migration job")
+		}
+		if _, err := database.GetCollection("schema_versions").UpdateOne(ctx, bson.M{"_id": version.ID}, bson.M{"$set": bson.M{"jobId": job.ID}}); err != nil {
+			slog.WarnContext(ctx, "collections: failed to link schema version to migration job", "versionId", version.ID.Hex(), "jobId", job.ID.Hex(), "error", err)
+		}
+
+		return c.Status(fiber.StatusAccepted).JSON(fiber.Map{
+			"message": "Schema migration started. The new validator will be applied when the job completes.",
+			"version": version.Version,
+			"jobId":   job.ID,
+		})
+	}
+
+	err = applyCollectionValidator(ctx, userDB, collectionName, payload.Schema, payload.ValidationLevel, payload.ValidationAction)
+	if err != nil {
+		return apierror.Internal(err, "Failed to update collection schema")
+	}
+
+	version.Status = models.SchemaVersionApplied
+	now := time.Now()
+	version.AppliedAt = &now
+	if err := insertSchemaVersion(ctx, &version); err != nil {
+		return apierror.Internal(err, "Schema updated but failed to record schema version")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Collection schema updated successfully", "version": version.Version})
+}
+
+// Handler untuk GET /projects/{id}/collections/{collName}/schema/versions (Riwayat versi schema)
+func GetSchemaVersions(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), 15*time.Second)
+	defer cancel()
 
-	command := bson.D{
-		{"collMod", collectionName},
-		{"validator", bson.M{"$jsonSchema": payload.Schema}},
-		{"validationLevel", "strict"},
+	projObjID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return apierror.InvalidObjectID("Project")
+	}
+
+	cursor, err := database.GetCollection("schema_versions").Find(ctx,
+		bson.M{"projectId": projObjID, "collectionName": c.Params("collName")},
+		options.Find().SetSort(bson.M{"version": -1}),
+	)
+	if err != nil {
+		return apierror.Internal(err, "Failed to fetch schema versions")
+	}
+	defer cursor.Close(ctx)
+
+	versions := []models.SchemaVersion{}
+	if err = cursor.All(ctx, &versions); err != nil {
+		return apierror.Internal(err, "Failed to decode schema versions")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(versions)
+}
+
+// Handler untuk GET /projects/{id}/collections/{collName}/schema (Baca schema validator yang sedang aktif)
+func GetCollectionSchema(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), 15*time.Second)
+	defer cancel()
+
+	projObjID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return apierror.InvalidObjectID("Project")
+	}
+	collectionName := c.Params("collName")
+
+	project, err := services.Projects().Get(ctx, projObjID)
+	if err != nil {
+		return err
 	}
 
-	err = userDBClient.Database(project.DBName).RunCommand(ctx, command).Err()
+	userDBClient, err := database.ProjectClient(ctx, project)
+	if err != nil {
+		return apierror.Internal(err, "Could not connect to user database")
+	}
+
+	opts, err := readCollectionOptions(ctx, userDBClient.Database(project.DBName), collectionName)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return apierror.Newf(fiber.StatusNotFound, apierror.CodeCollectionNotFound, "Collection '%s' not found.", collectionName)
+	}
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to update collection schema", "details": err.Error()})
+		return apierror.Internal(err, "Failed to read collection options")
 	}
 
-	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Collection schema updated successfully"})
+	// Nilai default MongoDB jika opsi tidak pernah di-set
+	if opts.ValidationLevel == "" {
+		opts.ValidationLevel = "strict"
+	}
+	if opts.ValidationAction == "" {
+		opts.ValidationAction = "error"
+	}
+
+	fields, editable := schemaFieldsFromValidator(opts.Validator)
+	return c.Status(fiber.StatusOK).JSON(models.CollectionSchema{
+		CollectionName:   collectionName,
+		Schema:           jsonSchemaFromValidator(opts.Validator),
+		Fields:           fields,
+		Editable:         editable,
+		ValidationLevel:  opts.ValidationLevel,
+		ValidationAction: opts.ValidationAction,
+	})
 }
 
 // Handler untuk DELETE /projects/{id}/collections/{collName} (Hapus Koleksi)
 func DeleteCollection(c *fiber.Ctx) error {
-	projectCollection := database.GetCollection("projects")
-	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	ctx, cancel := context.WithTimeout(c.UserContext(), 15*time.Second)
 	defer cancel()
 
 	projObjID, err := primitive.ObjectIDFromHex(c.Params("id"))
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid Project ID format"})
+		return apierror.InvalidObjectID("Project")
 	}
 	collectionName := c.Params("collName")
 
-	var project models.Project
-	err = projectCollection.FindOne(ctx, bson.M{"_id": projObjID}).Decode(&project)
+	project, err := services.Projects().Get(ctx, projObjID)
 	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Project not found"})
+		return err
 	}
 
-	userDBClient, err := connectToUserDB(ctx, project)
+	userDBClient, err := database.ProjectClient(ctx, project)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Could not connect to user database"})
+		return apierror.Internal(err, "Could not connect to user database")
 	}
-	defer userDBClient.Disconnect(ctx)
 
 	err = userDBClient.Database(project.DBName).Collection(collectionName).Drop(ctx)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to drop collection", "details": err.Error()})
+		return apierror.Internal(err, "Failed to drop collection")
+	}
+
+	// Koleksi sudah terhapus; revisi, riwayat schema, hook dan settings yang gagal dibersihkan hanya dicatat di log
+	if err := userDBClient.Database(project.DBName).Collection(collectionName + models.RevisionCollectionSuffix).Drop(ctx); err != nil {
+		slog.WarnContext(ctx, "collections: failed to drop revision collection", "projectId", project.ID.Hex(), "collection", collectionName, "error", err)
+	}
+	for _, name := range []string{"schema_versions", "collection_hooks", "collection_settings"} {
+		filter := bson.M{"projectId": project.ID, "collectionName": collectionName}
+		if _, err := database.GetCollection(name).DeleteMany(ctx, filter); err != nil {
+			slog.WarnContext(ctx, "collections: failed to clean up collection data", "projectId", project.ID.Hex(), "collection", collectionName, "from", name, "error", err)
+		}
 	}
 
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Collection dropped successfully"})
 }
 
 func GetOneProject(c *fiber.Ctx) error {
-	projectCollection := database.GetCollection("projects")
-	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	ctx, cancel := context.WithTimeout(c.UserContext(), 15*time.Second)
 	defer cancel()
 
 	projObjID, err := primitive.ObjectIDFromHex(c.Params("id"))
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid Project ID format"})
+		return apierror.InvalidObjectID("Project")
 	}
 
-	var project models.Project
-	err = projectCollection.FindOne(ctx, bson.M{"_id": projObjID}).Decode(&project)
+	project, err := services.Projects().Get(ctx, projObjID)
 	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Project not found"})
+		return err
 	}
 
 	return c.Status(fiber.StatusOK).JSON(project)
@@ -0,0 +1,151 @@
+// file: controllers/rate_limit_controller.go
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/fiber-mongo/starter-kit/apierror"   // Sesuaikan dengan nama modul Anda
+	"github.com/fiber-mongo/starter-kit/database"   // Sesuaikan dengan nama modul Anda
+	"github.com/fiber-mongo/starter-kit/middleware" // Sesuaikan dengan nama modul Anda
+	"github.com/fiber-mongo/starter-kit/models"     // Sesuaikan dengan nama modul Anda
+	"github.com/fiber-mongo/starter-kit/ratelimit"  // Sesuaikan dengan nama modul Anda
+	"github.com/fiber-mongo/starter-kit/services"   // Sesuaikan dengan nama modul Anda
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Fungsi helper untuk membaca kuota storage proyek (byte, 0 = tanpa batas) yang dimuat middleware RateLimit.
+// Nilainya dipasang di DocumentScope.StorageLimit supaya semua penulisan (REST dan GraphQL) ikut diperiksa.
+func storageLimit(c *fiber.Ctx) int64 {
+	settings, _ := c.Locals(middleware.RateLimitSettingsKey).(models.RateLimitSettings)
+	return settings.StorageBytes
+}
+
+// Fungsi helper untuk mengecek kuota storage proyek sebelum upload file diproses.
+// Ukuran data dibaca dari dbStats database user (di-cache sebentar oleh package ratelimit).
+func storageQuotaExceeded(ctx context.Context, c *fiber.Ctx, project models.Project) bool {
+	return services.Documents().StorageQuotaExceeded(ctx, project, storageLimit(c))
+}
+
+// Handler untuk GET /projects/{id}/rate-limits (Settings rate limit dan pemakaian kuota bulan ini)
+func GetRateLimits(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	projObjID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return apierror.InvalidObjectID("Project")
+	}
+	if _, err := services.Projects().Get(ctx, projObjID); err != nil {
+		return err
+	}
+
+	settings, err := ratelimit.LoadSettings(ctx, projObjID)
+	if err != nil {
+		return apierror.Internal(err, "Failed to read rate limit settings")
+	}
+
+	period, periodEnd := ratelimit.QuotaPeriod(time.Now())
+	used, err := ratelimit.MonthlyUsage(ctx, projObjID, period)
+	if err != nil {
+		return apierror.Internal(err, "Failed to read quota usage")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"settings": settings,
+		"usage": fiber.Map{
+			"period":          period,
+			"periodEnd":       periodEnd,
+			"monthlyRequests": used,
+		},
+	})
+}
+
+// Handler untuk PUT /projects/{id}/rate-limits (Ubah rate limit dan kuota proyek)
+func UpdateRateLimits(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	projObjID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return apierror.InvalidObjectID("Project")
+	}
+	if _, err := services.Projects().Get(ctx, projObjID); err != nil {
+		return err
+	}
+
+	var input models.RateLimitSettingsInput
+	if err := c.BodyParser(&input); err != nil {
+		return apierror.InvalidBody("Invalid request body")
+	}
+	for _, v := range []*float64{input.ProjectRate, input.KeyRate} {
+		if v != nil && *v < 0 {
+			return apierror.Validation("", "Rates must not be negative (use 0 for unlimited)")
+		}
+	}
+	for _, v := range []*int{input.ProjectBurst, input.KeyBurst} {
+		if v != nil && *v < 0 {
+			return apierror.Validation("", "Bursts must not be negative")
+		}
+	}
+	for _, v := range []*int64{input.MonthlyRequests, input.StorageBytes} {
+		if v != nil && *v < 0 {
+			return apierror.Validation("", "Quotas must not be negative (use 0 for no quota)")
+		}
+	}
+
+	// Field yang tidak dikirim diisi nilai default saat dokumen pertama kali dibuat
+	// (field yang sama tidak boleh ada di $set dan $setOnInsert sekaligus)
+	defaults := ratelimit.DefaultSettings(projObjID)
+	set := bson.M{"updatedAt": time.Now()}
+	setOnInsert := bson.M{
+		"projectRate":     defaults.ProjectRate,
+		"projectBurst":    defaults.ProjectBurst,
+		"keyRate":         defaults.KeyRate,
+		"keyBurst":        defaults.KeyBurst,
+		"monthlyRequests": defaults.MonthlyRequests,
+		"storageBytes":    defaults.StorageBytes,
+	}
+	if input.ProjectRate != nil {
+		set["projectRate"] = *input.ProjectRate
+	}
+	if input.ProjectBurst != nil {
+		set["projectBurst"] = *input.ProjectBurst
+	}
+	if input.KeyRate != nil {
+		set["keyRate"] = *input.KeyRate
+	}
+	if input.KeyBurst != nil {
+		set["keyBurst"] = *input.KeyBurst
+	}
+	if input.MonthlyRequests != nil {
+		set["monthlyRequests"] = *input.MonthlyRequests
+	}
+	if input.StorageBytes != nil {
+		set["storageBytes"] = *input.StorageBytes
+	}
+	for field := range set {
+		delete(setOnInsert, field)
+	}
+	update := bson.M{"$set": set}
+	if len(setOnInsert) > 0 {
+		update["$setOnInsert"] = setOnInsert
+	}
+
+	var settings models.RateLimitSettings
+	err = database.GetCollection("rate_limits").FindOneAndUpdate(ctx,
+		bson.M{"projectId": projObjID},
+		update,
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(&settings)
+	if err != nil {
+		return apierror.Internal(err, "Failed to update rate limit settings")
+	}
+	ratelimit.Invalidate(projObjID)
+
+	return c.Status(fiber.StatusOK).JSON(settings)
+}
@@ -0,0 +1,370 @@
+// file: controllers/realtime_controller.go
+package controllers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/fiber-mongo/starter-kit/apierror" // Sesuaikan dengan nama modul Anda
+	"github.com/fiber-mongo/starter-kit/database" // Sesuaikan dengan nama modul Anda
+	"github.com/fiber-mongo/starter-kit/models"   // Sesuaikan dengan nama modul Anda
+	"github.com/fiber-mongo/starter-kit/realtime" // Sesuaikan dengan nama modul Anda
+
+	"github.com/gofiber/contrib/websocket"
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// Prefix token resume yang berasal dari change stream MongoDB
+	changeStreamTokenPrefix = "cs:"
+	realtimeHeartbeat       = 25 * time.Second
+)
+
+// Context induk semua stream realtime; dibatalkan oleh CloseRealtimeStreams saat server berhenti
+var streamsCtx, cancelStreams = context.WithCancel(context.Background())
+
+// CloseRealtimeStreams menutup semua langganan SSE dan WebSocket supaya shutdown
+// tidak menunggu koneksi yang memang tidak pernah selesai sendiri
+func CloseRealtimeStreams() {
+	cancelStreams()
+}
+
+// Kode error MongoDB saat change stream tidak didukung (server standalone tanpa replica set)
+var changeStreamUnsupportedCodes = map[int32]bool{20: true, 40573: true}
+
+// Kode error MongoDB ChangeStreamHistoryLost: resume token sudah keluar dari oplog
+const changeStreamHistoryLostCode = 286
+
+// Langganan perubahan yang sudah terbuka: event dikirim ke channel Events sampai ditutup
+type changeFeed struct {
+	Events <-chan realtime.Event
+	Source string // "changeStream" atau "eventBus"
+	close  func()
+}
+
+func (f *changeFeed) Close() {
+	f.close()
+}
+
+// Fungsi helper untuk membaca parameter langganan dari query string / header.
+// Error yang dikembalikan berupa *apierror.Error dengan pesan tetap (detail parser JSON tidak dikirim ke client).
+func realtimeParams(filterJSON, resumeToken string) (bson.M, string, error) {
+	filter := bson.M{}
+	if filterJSON != "" {
+		if err := json.Unmarshal([]byte(filterJSON), &filter); err != nil {
+			return nil, "", apierror.Validation("filter", "Invalid filter JSON")
+		}
+		if err := realtime.ValidateFilter(filter); err != nil {
+			return nil, "", apierror.Validation("filter", "Invalid filter: "+err.Error())
+		}
+	}
+	if resumeToken != "" && !realtime.IsBusToken(resumeToken) && !strings.HasPrefix(resumeToken, changeStreamTokenPrefix) {
+		return nil, "", apierror.Validation("resumeToken", "Invalid resume token")
+	}
+	return filter, resumeToken, nil
+}
+
+// Fungsi helper untuk mengubah error saat membuka langganan menjadi error API.
+// Penyebab asli hanya dicatat di log, tidak dikirim ke client.
+func subscriptionError(err error) *apierror.Error {
+	switch {
+	case errors.Is(err, realtime.ErrResumeTokenExpired):
+		return apierror.New(fiber.StatusGone, apierror.CodeResumeTokenExpired,
+			"Resume token expired: some events after it are no longer available, subscribe again without a resume token")
+	case errors.Is(err, realtime.ErrInvalidResumeToken):
+		return apierror.Validation("resumeToken", "Invalid resume token")
+	}
+	return apierror.Internal(err, "Failed to open subscription")
+}
+
+// Fungsi helper untuk membuka langganan perubahan sebuah koleksi.
+// Change stream MongoDB dipakai jika tersedia (replica set / sharded cluster);
+// jika tidak, event diambil dari event bus di dalam proses yang diisi oleh handler penulisan /data.
+func openChangeFeed(ctx context.Context, project models.Project, client *mongo.Client, collectionName string, filter bson.M, resumeToken string) (*changeFeed, error) {
+	coll := client.Database(project.DBName).Collection(collectionName)
+
+	if !realtime.IsBusToken(resumeToken) {
+		match := bson.M{"operationType": bson.M{"$in": bson.A{"insert", "update", "replace", "delete"}}}
+		if len(filter) > 0 {
+			// Event delete tidak membawa isi dokumen, jadi selalu diteruskan
+			match = bson.M{"$and": bson.A{match, bson.M{"$or": bson.A{
+				bson.M{"operationType": "delete"},
+				realtime.PrefixFilter(filter, "fullDocument."),
+			}}}}
+		}
+		opts := options.ChangeStream().SetFullDocument(options.UpdateLookup)
+		if strings.HasPrefix(resumeToken, changeStreamTokenPrefix) {
+			opts.SetResumeAfter(bson.M{"_data": strings.TrimPrefix(resumeToken, changeStreamTokenPrefix)})
+		}
+
+		stream, err := coll.Watch(ctx, mongo.Pipeline{{{Key: "$match", Value: match}}}, opts)
+		if err == nil {
+			return changeStreamFeed(ctx, project, collectionName, stream), nil
+		}
+		var cmdErr mongo.CommandError
+		if errors.As(err, &cmdErr) && cmdErr.Code == changeStreamHistoryLostCode {
+			return nil, realtime.ErrResumeTokenExpired
+		}
+		if !errors.As(err, &cmdErr) || !changeStreamUnsupportedCodes[cmdErr.Code] || resumeToken != "" {
+			return nil, err
+		}
+	}
+
+	return eventBusFeed(ctx, project, collectionName, filter, resumeToken)
+}
+
+// Struct untuk decode event change stream
+type changeStreamEvent struct {
+	ID struct {
+		Data string `bson:"_data"`
+	} `bson:"_id"`
+	OperationType string              `bson:"operationType"`
+	DocumentKey   bson.M              `bson:"documentKey"`
+	FullDocument  bson.M              `bson:"fullDocument"`
+	ClusterTime   primitive.Timestamp `bson:"clusterTime"`
+}
+
+func changeStreamFeed(ctx context.Context, project models.Project, collectionName string, stream *mongo.ChangeStream) *changeFeed {
+	ctx, cancel := context.WithCancel(ctx)
+	events := make(chan realtime.Event)
+
+	go func() {
+		defer close(events)
+		defer stream.Close(context.Background())
+		for stream.Next(ctx) {
+			var raw changeStreamEvent
+			if err := stream.Decode(&raw); err != nil {
+				return
+			}
+			event := realtime.Event{
+				ID:            changeStreamTokenPrefix + raw.ID.Data,
+				ProjectID:     project.ID.Hex(),
+				Collection:    collectionName,
+				OperationType: raw.OperationType,
+				DocumentID:    raw.DocumentKey["_id"],
+				Timestamp:     time.Unix(int64(raw.ClusterTime.T), 0),
+			}
+			// Replace dari sisi klien sama saja dengan update
+			if event.OperationType == "replace" {
+				event.OperationType = realtime.OperationUpdate
+			}
+			if event.OperationType != realtime.OperationDelete {
+				event.FullDocument = raw.FullDocument
+			}
+			select {
+			case events <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return &changeFeed{Events: events, Source: "changeStream", close: cancel}
+}
+
+func eventBusFeed(ctx context.Context, project models.Project, collectionName string, filter bson.M, resumeToken string) (*changeFeed, error) {
+	sub, missed, err := realtime.DefaultBus.Subscribe(project.ID.Hex(), collectionName, resumeToken)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	events := make(chan realtime.Event)
+
+	accept := func(e realtime.Event) bool {
+		return e.OperationType == realtime.OperationDelete || realtime.Match(filter, e.FullDocument)
+	}
+	send := func(e realtime.Event) bool {
+		select {
+		case events <- e:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	go func() {
+		defer close(events)
+		defer realtime.DefaultBus.Unsubscribe(sub)
+		for _, e := range missed {
+			if accept(e) && !send(e) {
+				return
+			}
+		}
+		for {
+			select {
+			case e, ok := <-sub.C:
+				if !ok {
+					return
+				}
+				if accept(e) && !send(e) {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return &changeFeed{Events: events, Source: "eventBus", close: cancel}, nil
+}
+
+// Fungsi helper untuk menyambung ke database user dan membuka langganan.
+// Langganan tetap terbuka selama stream berjalan dan ditutup lewat fungsi cleanup.
+func subscribeCollection(ctx context.Context, project models.Project, collectionName string, filter bson.M, resumeToken string) (*changeFeed, func(), error) {
+	connectCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	userDBClient, err := database.ProjectClient(connectCtx, project)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not connect to user database: %w", err)
+	}
+
+	feed, err := openChangeFeed(ctx, project, userDBClient, collectionName, filter, resumeToken)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open change stream: %w", err)
+	}
+	return feed, feed.Close, nil
+}
+
+// Handler untuk GET /realtime/{projectId}/{collectionName}/sse (Server-Sent Events)
+// Query: filter (JSON), resumeToken; header Last-Event-ID otomatis dikirim ulang oleh EventSource saat reconnect.
+func SubscribeSSE(c *fiber.Ctx) error {
+	project, ok := c.Locals("project").(models.Project)
+	if !ok {
+		return apierror.New(fiber.StatusUnauthorized, apierror.CodeUnauthorized, "Unauthorized: Missing API Key")
+	}
+	collectionName := c.Params("collectionName")
+
+	resumeToken := c.Get("Last-Event-ID")
+	if resumeToken == "" {
+		resumeToken = c.Query("resumeToken")
+	}
+	filter, resumeToken, err := realtimeParams(c.Query("filter"), resumeToken)
+	if err != nil {
+		return err
+	}
+
+	// Stream berjalan setelah handler selesai, jadi tidak boleh memakai context request Fiber
+	ctx, cancel := context.WithCancel(streamsCtx)
+	feed, cleanup, err := subscribeCollection(ctx, project, collectionName, filter, resumeToken)
+	if err != nil {
+		cancel()
+		return subscriptionError(err)
+	}
+
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set(fiber.HeaderConnection, "keep-alive")
+	c.Set("X-Accel-Buffering", "no")
+	c.Set("X-Realtime-Source", feed.Source)
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer cancel()
+		defer cleanup()
+
+		heartbeat := time.NewTicker(realtimeHeartbeat)
+		defer heartbeat.Stop()
+
+		fmt.Fprintf(w, "retry: 3000\n\n")
+		if err := w.Flush(); err != nil {
+			return
+		}
+		for {
+			select {
+			case event, ok := <-feed.Events:
+				if !ok {
+					return
+				}
+				data, err := json.Marshal(event)
+				if err != nil {
+					continue
+				}
+				fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", event.ID, event.OperationType, data)
+			case <-heartbeat.C:
+				fmt.Fprintf(w, ": ping\n\n")
+			}
+			// Flush gagal berarti client sudah menutup koneksi
+			if err := w.Flush(); err != nil {
+				return
+			}
+		}
+	})
+	return nil
+}
+
+// Middleware untuk GET /realtime/{projectId}/{collectionName}/ws: hanya menerima request upgrade WebSocket
+func RequireWebSocketUpgrade(c *fiber.Ctx) error {
+	if !websocket.IsWebSocketUpgrade(c) {
+		return apierror.New(fiber.StatusUpgradeRequired, apierror.CodeUpgradeRequired, "WebSocket upgrade required")
+	}
+	return c.Next()
+}
+
+// Handler untuk GET /realtime/{projectId}/{collectionName}/ws (WebSocket)
+// Setiap event dikirim sebagai satu pesan JSON; kesalahan dikirim sebagai {"error": "...", "code": "..."} lalu koneksi ditutup.
+var SubscribeWebSocket = websocket.New(func(conn *websocket.Conn) {
+	project, ok := conn.Locals("project").(models.Project)
+	if !ok {
+		conn.WriteJSON(apierror.New(fiber.StatusUnauthorized, apierror.CodeUnauthorized, "Unauthorized: Missing API Key"))
+		return
+	}
+	collectionName := conn.Params("collectionName")
+
+	filter, resumeToken, err := realtimeParams(conn.Query("filter"), conn.Query("resumeToken"))
+	if err != nil {
+		conn.WriteJSON(err)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(streamsCtx)
+	defer cancel()
+
+	feed, cleanup, err := subscribeCollection(ctx, project, collectionName, filter, resumeToken)
+	if err != nil {
+		slog.Error("Failed to open subscription", "error", err, "projectId", project.ID.Hex(), "collection", collectionName)
+		conn.WriteJSON(subscriptionError(err))
+		return
+	}
+	defer cleanup()
+
+	// Pesan dari client tidak dipakai; loop baca hanya untuk mendeteksi koneksi yang ditutup
+	go func() {
+		defer cancel()
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	heartbeat := time.NewTicker(realtimeHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case event, ok := <-feed.Events:
+			if !ok {
+				return
+			}
+			if err := conn.WriteJSON(event); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+})
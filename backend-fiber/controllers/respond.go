@@ -0,0 +1,42 @@
+// file: controllers/respond.go
+package controllers
+
+import (
+	"errors"
+
+	"github.com/fiber-mongo/starter-kit/apierror" // Sesuaikan dengan nama modul Anda
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Kode error MongoDB saat dokumen ditolak oleh validator $jsonSchema koleksi
+const documentValidationFailure = 121
+
+// Fungsi helper untuk error saat menulis dokumen user: dokumen yang ditolak validator koleksi
+// adalah kesalahan input (400 VALIDATION_FAILED), error API dari DocumentScope (misalnya kuota storage)
+// diteruskan apa adanya, error lain dianggap error server
+func documentWriteError(err error, message string) error {
+	var apiErr *apierror.Error
+	if errors.As(err, &apiErr) {
+		return apiErr
+	}
+	var serverErr mongo.ServerError
+	if errors.As(err, &serverErr) && serverErr.HasErrorCode(documentValidationFailure) {
+		return apierror.Validation("", "Document failed schema validation").Wrap(err)
+	}
+	return apierror.Internal(err, message)
+}
+
+// Fungsi helper untuk mengubah daftar dokumen mentah menjadi bson.M untuk respons JSON
+func rawToMaps(docs []bson.Raw) ([]bson.M, error) {
+	results := make([]bson.M, 0, len(docs))
+	for _, raw := range docs {
+		var doc bson.M
+		if err := bson.Unmarshal(raw, &doc); err != nil {
+			return nil, err
+		}
+		results = append(results, doc)
+	}
+	return results, nil
+}
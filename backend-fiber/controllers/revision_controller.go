@@ -0,0 +1,185 @@
+// file: controllers/revision_controller.go
+package controllers
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/fiber-mongo/starter-kit/apierror" // Sesuaikan dengan nama modul Anda
+	"github.com/fiber-mongo/starter-kit/models"   // Sesuaikan dengan nama modul Anda
+	"github.com/fiber-mongo/starter-kit/services" // Sesuaikan dengan nama modul Anda
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	defaultRevisionLimit = 50
+	maxRevisionLimit     = 500
+)
+
+// Fungsi helper untuk membandingkan dua nilai secara rekursif (sub-dokumen dibandingkan per field)
+func diffValues(path string, from, to interface{}, changes *[]models.FieldChange) {
+	fromDoc, fromIsDoc := from.(bson.M)
+	toDoc, toIsDoc := to.(bson.M)
+	if !fromIsDoc || !toIsDoc {
+		if !reflect.DeepEqual(from, to) {
+			*changes = append(*changes, models.FieldChange{Path: path, Change: "changed", From: from, To: to})
+		}
+		return
+	}
+
+	keys := []string{}
+	for k := range fromDoc {
+		keys = append(keys, k)
+	}
+	for k := range toDoc {
+		if _, ok := fromDoc[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		fieldPath := k
+		if path != "" {
+			fieldPath = path + "." + k
+		}
+		fromValue, inFrom := fromDoc[k]
+		toValue, inTo := toDoc[k]
+		switch {
+		case !inFrom:
+			*changes = append(*changes, models.FieldChange{Path: fieldPath, Change: "added", To: toValue})
+		case !inTo:
+			*changes = append(*changes, models.FieldChange{Path: fieldPath, Change: "removed", From: fromValue})
+		default:
+			diffValues(fieldPath, fromValue, toValue, changes)
+		}
+	}
+}
+
+// Fungsi helper untuk menyiapkan scope Data API dari parameter route (dipakai oleh handler revisi)
+func revisionScope(ctx context.Context, c *fiber.Ctx) (*services.DocumentScope, primitive.ObjectID, error) {
+	projObjID, err := primitive.ObjectIDFromHex(c.Params("projectId"))
+	if err != nil {
+		return nil, primitive.NilObjectID, apierror.InvalidObjectID("Project")
+	}
+	docObjID, err := primitive.ObjectIDFromHex(c.Params("docId"))
+	if err != nil {
+		return nil, primitive.NilObjectID, apierror.InvalidObjectID("Document")
+	}
+
+	project, err := services.Projects().Get(ctx, projObjID)
+	if err != nil {
+		return nil, primitive.NilObjectID, err
+	}
+
+	scope, err := services.Documents().Open(ctx, project, c.Params("collectionName"), requestActor(c))
+	if err != nil {
+		return nil, primitive.NilObjectID, err
+	}
+	return scope, docObjID, nil
+}
+
+// Handler untuk GET /.../{collectionName}/{docId}/revisions?limit=N (Riwayat revisi dokumen, terbaru dulu)
+func GetDocumentRevisions(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), 15*time.Second)
+	defer cancel()
+
+	scope, docObjID, err := revisionScope(ctx, c)
+	if err != nil {
+		return err
+	}
+
+	limit := c.QueryInt("limit", defaultRevisionLimit)
+	if limit <= 0 || limit > maxRevisionLimit {
+		return apierror.Validation("limit", "limit must be between 1 and "+strconv.Itoa(maxRevisionLimit))
+	}
+
+	cursor, err := scope.Revisions().Find(ctx, bson.M{"documentId": docObjID},
+		options.Find().SetSort(bson.M{"revision": -1}).SetLimit(int64(limit)))
+	if err != nil {
+		return apierror.Internal(err, "Failed to fetch revisions")
+	}
+	revisionList := []models.DocumentRevision{}
+	if err := cursor.All(ctx, &revisionList); err != nil {
+		return apierror.Internal(err, "Failed to decode revisions")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(revisionList)
+}
+
+// Handler untuk GET /.../{collectionName}/{docId}/revisions/diff?from=N&to=M
+// (Perbedaan isi dua revisi; "to" default ke revisi terbaru)
+func DiffDocumentRevisions(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), 15*time.Second)
+	defer cancel()
+
+	scope, docObjID, err := revisionScope(ctx, c)
+	if err != nil {
+		return err
+	}
+
+	from := c.QueryInt("from", 0)
+	if from <= 0 {
+		return apierror.Validation("from", "Query parameter 'from' must be a revision number")
+	}
+
+	var fromRev models.DocumentRevision
+	err = scope.Revisions().FindOne(ctx, bson.M{"documentId": docObjID, "revision": from}).Decode(&fromRev)
+	if err != nil {
+		return apierror.NotFound(apierror.CodeRevisionNotFound, "Revision "+strconv.Itoa(from)+" not found")
+	}
+
+	var toRev models.DocumentRevision
+	toFilter := bson.M{"documentId": docObjID}
+	toOpts := options.FindOne().SetSort(bson.M{"revision": -1})
+	if to := c.QueryInt("to", 0); to > 0 {
+		toFilter["revision"] = to
+	}
+	err = scope.Revisions().FindOne(ctx, toFilter, toOpts).Decode(&toRev)
+	if err != nil {
+		return apierror.NotFound(apierror.CodeRevisionNotFound, "Target revision not found")
+	}
+
+	changes := []models.FieldChange{}
+	diffValues("", fromRev.Snapshot, toRev.Snapshot, &changes)
+
+	return c.Status(fiber.StatusOK).JSON(models.RevisionDiff{
+		DocumentID: docObjID,
+		From:       fromRev.Revision,
+		To:         toRev.Revision,
+		Changes:    changes,
+	})
+}
+
+// Handler untuk POST /.../{collectionName}/{docId}/revisions/{revision}/restore (Kembalikan dokumen ke isi revisi tersebut)
+func RestoreDocumentRevision(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), 15*time.Second)
+	defer cancel()
+
+	revision, err := strconv.Atoi(c.Params("revision"))
+	if err != nil || revision <= 0 {
+		return apierror.Validation("revision", "Invalid revision number")
+	}
+
+	scope, docObjID, err := revisionScope(ctx, c)
+	if err != nil {
+		return err
+	}
+
+	found, err := scope.RestoreRevision(ctx, docObjID, revision)
+	if err != nil {
+		return documentWriteError(err, "Failed to restore revision")
+	}
+	if !found {
+		return apierror.NotFound(apierror.CodeRevisionNotFound, "Revision not found")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Document restored to revision " + strconv.Itoa(revision)})
+}
@@ -0,0 +1,324 @@
+// file: controllers/schema_inference.go
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/fiber-mongo/starter-kit/apierror" // Sesuaikan dengan nama modul Anda
+	"github.com/fiber-mongo/starter-kit/database" // Sesuaikan dengan nama modul Anda
+	"github.com/fiber-mongo/starter-kit/models"   // Sesuaikan dengan nama modul Anda
+	"github.com/fiber-mongo/starter-kit/services" // Sesuaikan dengan nama modul Anda
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	defaultInferSampleSize = 100
+	maxInferSampleSize     = 1000
+)
+
+// Statistik satu level objek (dokumen root, sub-dokumen, atau elemen array berupa objek)
+type objectStats struct {
+	total  int
+	fields map[string]*fieldStats
+	order  []string // Urutan field saat pertama kali terlihat
+}
+
+// Statistik satu field: berapa kali muncul, tipe apa saja, dan struktur anaknya
+type fieldStats struct {
+	count    int
+	types    map[string]int
+	children *objectStats // Jika field berupa objek
+	items    *fieldStats  // Gabungan semua elemen jika field berupa array
+}
+
+func newObjectStats() *objectStats {
+	return &objectStats{fields: map[string]*fieldStats{}}
+}
+
+func newFieldStats() *fieldStats {
+	return &fieldStats{types: map[string]int{}}
+}
+
+// Nama tipe untuk nilai yang tipenya tidak dikenali. Field dengan tipe ini tidak diberi batasan bsonType
+// (supaya validator hasil inferensi tidak menolak dokumen sampelnya sendiri) dan tetap terlihat di laporan.
+const unknownBSONType = "unknown"
+
+// Fungsi helper untuk mendapatkan nama bsonType dari nilai hasil decode driver
+func bsonTypeName(v interface{}) string {
+	switch v := v.(type) {
+	case nil, primitive.Null:
+		return "null"
+	case string:
+		return "string"
+	case int32:
+		return "int"
+	case int64:
+		return "long"
+	case int:
+		// Driver menyimpan int sebagai int32 jika muat
+		if v >= math.MinInt32 && v <= math.MaxInt32 {
+			return "int"
+		}
+		return "long"
+	case float64:
+		return "double"
+	case bool:
+		return "bool"
+	case primitive.DateTime, time.Time:
+		return "date"
+	case primitive.ObjectID:
+		return "objectId"
+	case primitive.Decimal128:
+		return "decimal"
+	case primitive.Binary:
+		return "binData"
+	case primitive.Timestamp:
+		return "timestamp"
+	case primitive.Regex:
+		return "regex"
+	case primitive.MinKey:
+		return "minKey"
+	case primitive.MaxKey:
+		return "maxKey"
+	case primitive.JavaScript:
+		return "javascript"
+	case primitive.CodeWithScope:
+		return "javascriptWithScope"
+	case primitive.Symbol:
+		return "symbol"
+	case primitive.DBPointer:
+		return "dbPointer"
+	case primitive.Undefined:
+		return "undefined"
+	case bson.D, bson.M:
+		return "object"
+	case bson.A:
+		return "array"
+	default:
+		return unknownBSONType
+	}
+}
+
+func (o *objectStats) observe(doc bson.D) {
+	o.total++
+	for _, elem := range doc {
+		field, ok := o.fields[elem.Key]
+		if !ok {
+			field = newFieldStats()
+			o.fields[elem.Key] = field
+			o.order = append(o.order, elem.Key)
+		}
+		field.observe(elem.Value)
+	}
+}
+
+func (f *fieldStats) observe(value interface{}) {
+	f.count++
+	f.types[bsonTypeName(value)]++
+
+	switch v := value.(type) {
+	case bson.D:
+		if f.children == nil {
+			f.children = newObjectStats()
+		}
+		f.children.observe(v)
+	case bson.M:
+		if f.children == nil {
+			f.children = newObjectStats()
+		}
+		f.children.observe(mapToD(v))
+	case bson.A:
+		if f.items == nil {
+			f.items = newFieldStats()
+		}
+		for _, item := range v {
+			f.items.observe(item)
+		}
+	}
+}
+
+func mapToD(m bson.M) bson.D {
+	d := make(bson.D, 0, len(m))
+	for k, v := range m {
+		d = append(d, bson.E{Key: k, Value: v})
+	}
+	return d
+}
+
+var numericBSONTypes = map[string]bool{"int": true, "long": true, "double": true, "decimal": true}
+
+// Fungsi helper untuk menyusun definisi $jsonSchema dari statistik sebuah field
+func (f *fieldStats) toSchema() bson.M {
+	def := bson.M{}
+
+	types := []string{}
+	allNumeric := true
+	nonNullTypes := 0
+	for t := range f.types {
+		types = append(types, t)
+		if t != "null" {
+			nonNullTypes++
+			if !numericBSONTypes[t] {
+				allNumeric = false
+			}
+		}
+	}
+	sort.Strings(types)
+
+	// Campuran int/long/double cukup diwakili alias "number"
+	if nonNullTypes > 1 && allNumeric {
+		merged := []string{"number"}
+		if f.types["null"] > 0 {
+			merged = append(merged, "null")
+		}
+		types = merged
+	}
+	switch {
+	case f.types[unknownBSONType] > 0:
+		// Tipe tidak dikenali: lebih aman tanpa batasan bsonType daripada menebak
+	case len(types) == 1:
+		def["bsonType"] = types[0]
+	default:
+		def["bsonType"] = types
+	}
+
+	if f.children != nil {
+		properties, required := f.children.toSchema()
+		def["properties"] = properties
+		if len(required) > 0 {
+			def["required"] = required
+		}
+	}
+	if f.items != nil && f.items.count > 0 {
+		def["items"] = f.items.toSchema()
+	}
+	return def
+}
+
+// Fungsi helper untuk menyusun "properties" dan "required" dari satu level objek.
+// Field dianggap wajib jika muncul di semua objek dan tidak pernah bernilai null.
+func (o *objectStats) toSchema() (bson.M, []string) {
+	properties := bson.M{}
+	required := []string{}
+	for _, name := range o.order {
+		field := o.fields[name]
+		properties[name] = field.toSchema()
+		if field.count == o.total && field.types["null"] == 0 {
+			required = append(required, name)
+		}
+	}
+	return properties, required
+}
+
+// Fungsi helper untuk membuat laporan frekuensi per field (path dengan notasi titik, "[]" untuk elemen array)
+func (o *objectStats) report(prefix string, out *[]models.InferredField) {
+	for _, name := range o.order {
+		o.fields[name].report(prefix+name, o.total, out)
+	}
+}
+
+func (f *fieldStats) report(path string, parentTotal int, out *[]models.InferredField) {
+	types := map[string]float64{}
+	for t, n := range f.types {
+		types[t] = percentage(n, f.count)
+	}
+	*out = append(*out, models.InferredField{
+		Path:      path,
+		Count:     f.count,
+		Frequency: percentage(f.count, parentTotal),
+		Types:     types,
+	})
+	if f.children != nil {
+		f.children.report(path+".", out)
+	}
+	if f.items != nil && f.items.count > 0 {
+		f.items.report(path+"[]", f.items.count, out)
+	}
+}
+
+func percentage(part, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Round(float64(part)/float64(total)*10000) / 100
+}
+
+// Fungsi helper untuk menebak $jsonSchema dari sekumpulan dokumen sampel
+func inferSchema(docs []bson.D) (bson.M, []models.InferredField) {
+	root := newObjectStats()
+	for _, doc := range docs {
+		root.observe(doc)
+	}
+
+	properties, required := root.toSchema()
+	schema := bson.M{"bsonType": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+
+	fields := []models.InferredField{}
+	root.report("", &fields)
+	return schema, fields
+}
+
+// Handler untuk GET /projects/{id}/collections/{collName}/schema/infer?sampleSize=N
+// (Usulan $jsonSchema dari sampel dokumen; field "schema" di respons bisa langsung dikirim ke UpdateCollection)
+func InferCollectionSchema(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), 30*time.Second)
+	defer cancel()
+
+	projObjID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return apierror.InvalidObjectID("Project")
+	}
+	collectionName := c.Params("collName")
+
+	sampleSize := c.QueryInt("sampleSize", defaultInferSampleSize)
+	if sampleSize <= 0 || sampleSize > maxInferSampleSize {
+		return apierror.Validation("sampleSize", fmt.Sprintf("sampleSize must be between 1 and %d", maxInferSampleSize))
+	}
+
+	project, err := services.Projects().Get(ctx, projObjID)
+	if err != nil {
+		return err
+	}
+
+	userDBClient, err := database.ProjectClient(ctx, project)
+	if err != nil {
+		return apierror.Internal(err, "Could not connect to user database")
+	}
+
+	userDB := userDBClient.Database(project.DBName)
+	if _, err := readCollectionOptions(ctx, userDB, collectionName); errors.Is(err, mongo.ErrNoDocuments) {
+		return apierror.Newf(fiber.StatusNotFound, apierror.CodeCollectionNotFound, "Collection '%s' not found.", collectionName)
+	}
+
+	cursor, err := userDB.Collection(collectionName).Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$sample", Value: bson.M{"size": sampleSize}}},
+	})
+	if err != nil {
+		return apierror.Internal(err, "Failed to sample documents")
+	}
+	var docs []bson.D
+	if err := cursor.All(ctx, &docs); err != nil {
+		return apierror.Internal(err, "Failed to decode sampled documents")
+	}
+
+	schema, fields := inferSchema(docs)
+	return c.Status(fiber.StatusOK).JSON(models.InferredSchema{
+		CollectionName:   collectionName,
+		SampleSize:       sampleSize,
+		SampledDocuments: len(docs),
+		Schema:           schema,
+		Fields:           fields,
+	})
+}
@@ -0,0 +1,177 @@
+// file: controllers/schema_inference_test.go
+package controllers
+
+import (
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestBSONTypeName(t *testing.T) {
+	cases := map[string]interface{}{
+		"null":                nil,
+		"int":                 int32(1),
+		"long":                int64(1) << 40,
+		"minKey":              primitive.MinKey{},
+		"maxKey":              primitive.MaxKey{},
+		"javascript":          primitive.JavaScript("return 1"),
+		"javascriptWithScope": primitive.CodeWithScope{Code: "x", Scope: bson.D{}},
+		"symbol":              primitive.Symbol("s"),
+		"dbPointer":           primitive.DBPointer{DB: "db.c", Pointer: primitive.NewObjectID()},
+		"undefined":           primitive.Undefined{},
+		"object":              bson.D{},
+		"array":               bson.A{},
+		unknownBSONType:       struct{}{},
+	}
+	for want, value := range cases {
+		if got := bsonTypeName(value); got != want {
+			t.Errorf("bsonTypeName(%T) = %q, want %q", value, got, want)
+		}
+	}
+	if got := bsonTypeName(1 << 40); got != "long" {
+		t.Errorf("bsonTypeName(large int) = %q, want long", got)
+	}
+}
+
+func TestInferSchemaUnknownType(t *testing.T) {
+	docs := []bson.D{
+		{{Key: "name", Value: "a"}, {Key: "odd", Value: struct{}{}}, {Key: "marker", Value: primitive.MinKey{}}},
+	}
+	schema, fields := inferSchema(docs)
+
+	properties := schema["properties"].(bson.M)
+	if _, ok := properties["odd"].(bson.M)["bsonType"]; ok {
+		t.Fatalf("unknown type must not be constrained: %v", properties["odd"])
+	}
+	if got := properties["marker"].(bson.M)["bsonType"]; got != "minKey" {
+		t.Fatalf("marker bsonType = %v, want minKey", got)
+	}
+	for _, field := range fields {
+		if field.Path == "odd" && field.Types[unknownBSONType] != 100 {
+			t.Fatalf("unknown type is not flagged in the report: %+v", field)
+		}
+	}
+}
+
+func TestInferSchema(t *testing.T) {
+	tests := []struct {
+		name      string
+		docs      []bson.D
+		schema    bson.M
+		frequency map[string]float64 // Path -> Fields[].Frequency
+	}{
+		{
+			name: "required and optional",
+			docs: []bson.D{
+				{{Key: "name", Value: "a"}, {Key: "age", Value: int32(1)}},
+				{{Key: "name", Value: "b"}},
+			},
+			schema: bson.M{
+				"bsonType": "object",
+				"properties": bson.M{
+					"name": bson.M{"bsonType": "string"},
+					"age":  bson.M{"bsonType": "int"},
+				},
+				"required": []string{"name"},
+			},
+			frequency: map[string]float64{"name": 100, "age": 50},
+		},
+		{
+			name: "null is not required",
+			docs: []bson.D{
+				{{Key: "note", Value: "x"}},
+				{{Key: "note", Value: nil}},
+				{{Key: "note", Value: nil}},
+			},
+			schema: bson.M{
+				"bsonType":   "object",
+				"properties": bson.M{"note": bson.M{"bsonType": []string{"null", "string"}}},
+			},
+			frequency: map[string]float64{"note": 100},
+		},
+		{
+			name: "numeric types merge to number",
+			docs: []bson.D{
+				{{Key: "price", Value: int32(1)}, {Key: "qty", Value: int32(1)}},
+				{{Key: "price", Value: 1.5}, {Key: "qty", Value: int64(1) << 40}},
+				{{Key: "price", Value: nil}, {Key: "qty", Value: "many"}},
+			},
+			schema: bson.M{
+				"bsonType": "object",
+				"properties": bson.M{
+					"price": bson.M{"bsonType": []string{"number", "null"}},
+					// Campuran angka dengan tipe lain tidak digabung
+					"qty": bson.M{"bsonType": []string{"int", "long", "string"}},
+				},
+				"required": []string{"qty"},
+			},
+			frequency: map[string]float64{"price": 100, "qty": 100},
+		},
+		{
+			name: "nested object",
+			docs: []bson.D{
+				{{Key: "address", Value: bson.D{{Key: "city", Value: "Bandung"}, {Key: "zip", Value: "40111"}}}},
+				{{Key: "address", Value: bson.D{{Key: "city", Value: "Medan"}}}},
+				{},
+			},
+			schema: bson.M{
+				"bsonType": "object",
+				"properties": bson.M{
+					"address": bson.M{
+						"bsonType": "object",
+						"properties": bson.M{
+							"city": bson.M{"bsonType": "string"},
+							"zip":  bson.M{"bsonType": "string"},
+						},
+						"required": []string{"city"},
+					},
+				},
+			},
+			frequency: map[string]float64{"address": 66.67, "address.city": 100, "address.zip": 50},
+		},
+		{
+			name: "array items",
+			docs: []bson.D{
+				{{Key: "tags", Value: bson.A{"a", int32(1)}}, {Key: "lines", Value: bson.A{
+					bson.D{{Key: "sku", Value: "A1"}},
+					bson.D{{Key: "sku", Value: "B2"}, {Key: "qty", Value: int32(2)}},
+				}}},
+				{{Key: "tags", Value: bson.A{}}, {Key: "lines", Value: bson.A{}}},
+			},
+			schema: bson.M{
+				"bsonType": "object",
+				"properties": bson.M{
+					"tags": bson.M{"bsonType": "array", "items": bson.M{"bsonType": []string{"int", "string"}}},
+					"lines": bson.M{"bsonType": "array", "items": bson.M{
+						"bsonType": "object",
+						"properties": bson.M{
+							"sku": bson.M{"bsonType": "string"},
+							"qty": bson.M{"bsonType": "int"},
+						},
+						"required": []string{"sku"},
+					}},
+				},
+				"required": []string{"tags", "lines"},
+			},
+			frequency: map[string]float64{"tags": 100, "tags[]": 100, "lines": 100, "lines[]": 100, "lines[].sku": 100, "lines[].qty": 50},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema, fields := inferSchema(tt.docs)
+			if !reflect.DeepEqual(schema, tt.schema) {
+				t.Errorf("schema = %v, want %v", schema, tt.schema)
+			}
+			frequency := map[string]float64{}
+			for _, field := range fields {
+				frequency[field.Path] = field.Frequency
+			}
+			if !reflect.DeepEqual(frequency, tt.frequency) {
+				t.Errorf("frequency = %v, want %v", frequency, tt.frequency)
+			}
+		})
+	}
+}
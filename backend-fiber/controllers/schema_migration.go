@@ -0,0 +1,262 @@
+// file: controllers/schema_migration.go
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/fiber-mongo/starter-kit/database" // Sesuaikan dengan nama modul Anda
+	"github.com/fiber-mongo/starter-kit/models"   // Sesuaikan dengan nama modul Anda
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Jumlah _id dokumen pelanggar yang dikembalikan oleh dry-run
+const dryRunSampleSize = 10
+
+// Tipe target yang didukung oleh operator $convert
+var convertibleTypes = map[string]bool{
+	"double": true, "string": true, "objectId": true, "bool": true,
+	"date": true, "int": true, "long": true, "decimal": true,
+}
+
+// Fungsi helper untuk mengubah langkah migrasi menjadi pipeline agregasi.
+// Pipeline yang sama dipakai untuk dry-run (Aggregate) dan migrasi sungguhan (UpdateMany).
+func migrationPipeline(steps []models.MigrationStep) (mongo.Pipeline, error) {
+	pipeline := mongo.Pipeline{}
+	for i, step := range steps {
+		if step.Field == "" || strings.HasPrefix(step.Field, "$") {
+			return nil, fmt.Errorf("migration step %d: invalid field name '%s'", i+1, step.Field)
+		}
+		fieldRef := "$" + step.Field
+		fieldMissing := bson.M{"$eq": bson.A{bson.M{"$type": fieldRef}, "missing"}}
+
+		switch step.Op {
+		case "rename":
+			if step.To == "" || strings.HasPrefix(step.To, "$") {
+				return nil, fmt.Errorf("migration step %d: invalid target field name '%s'", i+1, step.To)
+			}
+			pipeline = append(pipeline,
+				bson.D{{Key: "$set", Value: bson.M{step.To: bson.M{"$cond": bson.A{fieldMissing, "$" + step.To, fieldRef}}}}},
+				bson.D{{Key: "$unset", Value: step.Field}},
+			)
+		case "default":
+			pipeline = append(pipeline,
+				bson.D{{Key: "$set", Value: bson.M{step.Field: bson.M{"$ifNull": bson.A{fieldRef, bson.M{"$literal": step.Value}}}}}},
+			)
+		case "convert":
+			if !convertibleTypes[step.To] {
+				return nil, fmt.Errorf("migration step %d: cannot convert to type '%s'", i+1, step.To)
+			}
+			convert := bson.M{"$convert": bson.M{"input": fieldRef, "to": step.To, "onError": fieldRef, "onNull": nil}}
+			pipeline = append(pipeline,
+				bson.D{{Key: "$set", Value: bson.M{step.Field: bson.M{"$cond": bson.A{fieldMissing, "$$REMOVE", convert}}}}},
+			)
+		case "remove":
+			pipeline = append(pipeline, bson.D{{Key: "$unset", Value: step.Field}})
+		default:
+			return nil, fmt.Errorf("migration step %d: unknown op '%s' (expected rename, default, convert or remove)", i+1, step.Op)
+		}
+	}
+	return pipeline, nil
+}
+
+// Fungsi helper untuk menghitung berapa dokumen yang akan melanggar schema baru,
+// setelah langkah migrasi (jika ada) diterapkan secara simulasi.
+func dryRunSchemaChange(ctx context.Context, coll *mongo.Collection, pipeline mongo.Pipeline, schema bson.M) (models.SchemaDryRunReport, error) {
+	report := models.SchemaDryRunReport{SampleViolations: []interface{}{}}
+
+	total, err := coll.CountDocuments(ctx, bson.M{})
+	if err != nil {
+		return report, err
+	}
+	report.TotalDocuments = total
+
+	violations := append(mongo.Pipeline{}, pipeline...)
+	violations = append(violations, bson.D{{Key: "$match", Value: bson.M{"$nor": bson.A{bson.M{"$jsonSchema": schema}}}}})
+	violations = append(violations, bson.D{{Key: "$facet", Value: bson.M{
+		"count":  bson.A{bson.M{"$count": "n"}},
+		"sample": bson.A{bson.M{"$limit": dryRunSampleSize}, bson.M{"$project": bson.M{"_id": 1}}},
+	}}})
+
+	cursor, err := coll.Aggregate(ctx, violations)
+	if err != nil {
+		return report, err
+	}
+	var results []struct {
+		Count []struct {
+			N int64 `bson:"n"`
+		} `bson:"count"`
+		Sample []bson.M `bson:"sample"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		return report, err
+	}
+	if len(results) > 0 {
+		if len(results[0].Count) > 0 {
+			report.ViolatingDocuments = results[0].Count[0].N
+		}
+		for _, doc := range results[0].Sample {
+			report.SampleViolations = append(report.SampleViolations, doc["_id"])
+		}
+	}
+	return report, nil
+}
+
+// Berapa kali penyimpanan versi schema dicoba ulang saat nomor versinya sudah dipakai request lain
+const schemaVersionInsertAttempts = 5
+
+// Fungsi helper untuk menyimpan versi schema baru dengan nomor versi berikutnya.
+// Index unik (projectId, collectionName, version) mencegah dua request mendapat nomor yang sama;
+// jika bentrok, nomor versi dihitung ulang.
+func insertSchemaVersion(ctx context.Context, version *models.SchemaVersion) error {
+	versions := database.GetCollection("schema_versions")
+	if version.Migrations == nil {
+		version.Migrations = []models.MigrationStep{}
+	}
+
+	var err error
+	for attempt := 0; attempt < schemaVersionInsertAttempts; attempt++ {
+		var latest models.SchemaVersion
+		err = versions.FindOne(ctx,
+			bson.M{"projectId": version.ProjectID, "collectionName": version.CollectionName},
+			options.FindOne().SetSort(bson.M{"version": -1}),
+		).Decode(&latest)
+		if err != nil && err != mongo.ErrNoDocuments {
+			return err
+		}
+
+		version.ID = primitive.NewObjectID()
+		version.Version = latest.Version + 1
+		version.CreatedAt = time.Now()
+		_, err = versions.InsertOne(ctx, version)
+		if !mongo.IsDuplicateKeyError(err) {
+			return err
+		}
+	}
+	return err
+}
+
+// Fungsi helper untuk memperbarui status sebuah versi schema
+func setSchemaVersionStatus(ctx context.Context, versionID primitive.ObjectID, status string, errMsg string) error {
+	set := bson.M{"status": status}
+	if status == models.SchemaVersionApplied {
+		set["appliedAt"] = time.Now()
+	}
+	if errMsg != "" {
+		set["error"] = errMsg
+	}
+	_, err := database.GetCollection("schema_versions").UpdateOne(ctx, bson.M{"_id": versionID}, bson.M{"$set": set})
+	return err
+}
+
+// Fungsi helper untuk menerapkan validator ke koleksi lewat collMod
+func applyCollectionValidator(ctx context.Context, db *mongo.Database, collectionName string, schema bson.M, level, action string) error {
+	command := bson.D{
+		{Key: "collMod", Value: collectionName},
+		{Key: "validator", Value: bson.M{"$jsonSchema": schema}},
+		{Key: "validationLevel", Value: level},
+		{Key: "validationAction", Value: action},
+	}
+	return db.RunCommand(ctx, command).Err()
+}
+
+// Jumlah dokumen yang dimigrasikan per batch (progress job dilaporkan setiap batch)
+const migrationBatchSize = 500
+
+// Fungsi helper yang membuat fungsi job migrasi: jalankan langkah migrasi ke semua dokumen per batch,
+// lalu terapkan validator baru dan tandai versinya sebagai "applied".
+// Validator lama dilewati (bypassDocumentValidation) karena dokumen hasil migrasi memang belum cocok dengannya,
+// misalnya saat field wajib di-rename atau dihapus.
+func schemaMigrationJob(project models.Project, version models.SchemaVersion, pipeline mongo.Pipeline) func(context.Context, func(int64, int64)) (bson.M, error) {
+	return func(ctx context.Context, progress func(int64, int64)) (result bson.M, err error) {
+		defer func() {
+			status, errMsg := models.SchemaVersionApplied, ""
+			if err != nil {
+				status, errMsg = models.SchemaVersionFailed, err.Error()
+			}
+			statusCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			setSchemaVersionStatus(statusCtx, version.ID, status, errMsg)
+		}()
+
+		userDBClient, err := database.ProjectClient(ctx, project)
+		if err != nil {
+			return nil, fmt.Errorf("could not connect to user database: %w", err)
+		}
+
+		db := userDBClient.Database(project.DBName)
+		coll := db.Collection(version.CollectionName)
+
+		total, err := coll.CountDocuments(ctx, bson.M{})
+		if err != nil {
+			return nil, err
+		}
+		progress(0, total)
+
+		var matched, modified int64
+		if len(pipeline) > 0 {
+			matched, modified, err = migrateInBatches(ctx, coll, pipeline, func(done int64) { progress(done, total) })
+			if err != nil {
+				return nil, fmt.Errorf("migration failed after %d documents: %w", matched, err)
+			}
+		}
+		progress(total, total)
+
+		if err := applyCollectionValidator(ctx, db, version.CollectionName, version.Schema, version.ValidationLevel, version.ValidationAction); err != nil {
+			return nil, fmt.Errorf("failed to apply validator: %w", err)
+		}
+
+		return bson.M{
+			"version":           version.Version,
+			"matchedDocuments":  matched,
+			"modifiedDocuments": modified,
+		}, nil
+	}
+}
+
+// Fungsi helper untuk menjalankan pipeline migrasi ke semua dokumen, berurutan berdasarkan _id per batch
+func migrateInBatches(ctx context.Context, coll *mongo.Collection, pipeline mongo.Pipeline, progress func(int64)) (matched, modified int64, err error) {
+	updateOpts := options.Update().SetBypassDocumentValidation(true)
+	filter := bson.M{}
+	for {
+		cursor, err := coll.Find(ctx, filter, options.Find().
+			SetSort(bson.M{"_id": 1}).
+			SetLimit(migrationBatchSize).
+			SetProjection(bson.M{"_id": 1}))
+		if err != nil {
+			return matched, modified, err
+		}
+		var batch []struct {
+			ID interface{} `bson:"_id"`
+		}
+		if err := cursor.All(ctx, &batch); err != nil {
+			return matched, modified, err
+		}
+		if len(batch) == 0 {
+			return matched, modified, nil
+		}
+
+		ids := make(bson.A, len(batch))
+		for i, doc := range batch {
+			ids[i] = doc.ID
+		}
+		result, err := coll.UpdateMany(ctx, bson.M{"_id": bson.M{"$in": ids}}, pipeline, updateOpts)
+		if err != nil {
+			return matched, modified, err
+		}
+		matched += result.MatchedCount
+		modified += result.ModifiedCount
+		progress(matched)
+
+		if len(batch) < migrationBatchSize {
+			return matched, modified, nil
+		}
+		filter = bson.M{"_id": bson.M{"$gt": batch[len(batch)-1].ID}}
+	}
+}
@@ -0,0 +1,259 @@
+// file: controllers/usage_controller.go
+package controllers
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/fiber-mongo/starter-kit/apierror" // Sesuaikan dengan nama modul Anda
+	"github.com/fiber-mongo/starter-kit/database" // Sesuaikan dengan nama modul Anda
+	"github.com/fiber-mongo/starter-kit/models"   // Sesuaikan dengan nama modul Anda
+	"github.com/fiber-mongo/starter-kit/services" // Sesuaikan dengan nama modul Anda
+	"github.com/fiber-mongo/starter-kit/usage"    // Sesuaikan dengan nama modul Anda
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Rentang waktu default dan maksimal untuk statistik per granularitas
+var usageRanges = map[string]struct{ def, max time.Duration }{
+	models.UsageHourly: {def: 24 * time.Hour, max: 31 * 24 * time.Hour},
+	models.UsageDaily:  {def: 30 * 24 * time.Hour, max: 366 * 24 * time.Hour},
+}
+
+// Hasil $group statistik per bucket (dan per nilai groupBy)
+type usageRow struct {
+	ID struct {
+		Bucket time.Time `bson:"bucket"`
+		Key    string    `bson:"key"`
+	} `bson:"_id"`
+	Requests     int64            `bson:"requests"`
+	ClientErrors int64            `bson:"clientErrors"`
+	ServerErrors int64            `bson:"serverErrors"`
+	BytesIn      int64            `bson:"bytesIn"`
+	BytesOut     int64            `bson:"bytesOut"`
+	LatencyMsSum float64          `bson:"latencyMsSum"`
+	Latency      map[string]int64 `bson:"latency"`
+	StorageBytes int64            `bson:"storageBytes"`
+}
+
+// SampleStorage mencatat ukuran data setiap koleksi aktif di semua proyek ke statistik pemakaian.
+// Dijalankan berkala oleh package usage.
+func SampleStorage(ctx context.Context) {
+	projects, err := services.Projects().List(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "usage: failed to list projects", "error", err)
+		return
+	}
+
+	now := time.Now()
+	for _, project := range projects {
+		if ctx.Err() != nil {
+			return
+		}
+		if err := sampleProjectStorage(ctx, project, now); err != nil {
+			slog.WarnContext(ctx, "usage: failed to sample storage", "projectId", project.ID.Hex(), "error", err)
+		}
+	}
+}
+
+// Fungsi helper untuk mencatat ukuran data koleksi aktif satu proyek
+func sampleProjectStorage(ctx context.Context, project models.Project, now time.Time) error {
+	userDBClient, err := database.ProjectClient(ctx, project)
+	if err != nil {
+		return err
+	}
+
+	infos, err := readCollectionInfos(ctx, userDBClient.Database(project.DBName), project.ActiveCollections)
+	if err != nil {
+		return err
+	}
+	for _, info := range infos {
+		if !info.Active {
+			continue
+		}
+		if err := usage.RecordStorage(ctx, project.ID, info.Name, info.DataSize, now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Fungsi helper untuk memperkirakan persentil latency dari histogram (batas atas bucket, dalam ms).
+// Bucket "inf" dilaporkan sebagai batas terakhir.
+func latencyPercentile(hist map[string]int64, total int64, q float64) int64 {
+	if total == 0 {
+		return 0
+	}
+	target := int64(q*float64(total) + 0.5)
+	if target < 1 {
+		target = 1
+	}
+	var seen int64
+	for _, bound := range models.UsageLatencyBoundsMs {
+		seen += hist["le_"+strconv.FormatInt(bound, 10)]
+		if seen >= target {
+			return bound
+		}
+	}
+	return models.UsageLatencyBoundsMs[len(models.UsageLatencyBoundsMs)-1]
+}
+
+// Fungsi helper untuk mengubah hasil agregasi menjadi titik deret waktu
+func usagePoint(row usageRow) models.UsagePoint {
+	point := models.UsagePoint{
+		Key:          row.ID.Key,
+		Requests:     row.Requests,
+		ClientErrors: row.ClientErrors,
+		ServerErrors: row.ServerErrors,
+		BytesIn:      row.BytesIn,
+		BytesOut:     row.BytesOut,
+		Latency:      row.Latency,
+		StorageBytes: row.StorageBytes,
+	}
+	if !row.ID.Bucket.IsZero() {
+		bucket := row.ID.Bucket
+		point.Bucket = &bucket
+	}
+	if row.Requests > 0 {
+		point.ErrorRate = float64(row.ClientErrors+row.ServerErrors) / float64(row.Requests)
+		point.AvgLatencyMs = row.LatencyMsSum / float64(row.Requests)
+		point.P50LatencyMs = latencyPercentile(row.Latency, row.Requests, 0.50)
+		point.P95LatencyMs = latencyPercentile(row.Latency, row.Requests, 0.95)
+		point.P99LatencyMs = latencyPercentile(row.Latency, row.Requests, 0.99)
+	}
+	return point
+}
+
+// Handler untuk GET /projects/{id}/usage?granularity=hour|day&from=&to=&collection=&apiKeyId=&groupBy=collection|apiKey
+// (Deret waktu statistik pemakaian Data API untuk dashboard, beserta total dalam rentang tersebut)
+func GetProjectUsage(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), 15*time.Second)
+	defer cancel()
+
+	projObjID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return apierror.InvalidObjectID("Project")
+	}
+	if _, err := services.Projects().Get(ctx, projObjID); err != nil {
+		return err
+	}
+
+	granularity := c.Query("granularity", models.UsageHourly)
+	ranges, ok := usageRanges[granularity]
+	if !ok {
+		return apierror.Validation("granularity", "granularity must be 'hour' or 'day'")
+	}
+	to := time.Now()
+	if value := c.Query("to"); value != "" {
+		if to, err = parseQueryTime(value); err != nil {
+			return apierror.Validation("to", "Invalid 'to' date, use RFC 3339 or YYYY-MM-DD")
+		}
+	}
+	from := to.Add(-ranges.def)
+	if value := c.Query("from"); value != "" {
+		if from, err = parseQueryTime(value); err != nil {
+			return apierror.Validation("from", "Invalid 'from' date, use RFC 3339 or YYYY-MM-DD")
+		}
+	}
+	if !from.Before(to) || to.Sub(from) > ranges.max {
+		return apierror.Validation("from", "Invalid range: 'from' must be before 'to' and within "+ranges.max.String())
+	}
+
+	match := bson.M{
+		"projectId":   projObjID,
+		"granularity": granularity,
+		"bucket":      bson.M{"$gte": usage.BucketStart(from, granularity), "$lte": to},
+	}
+	if collection := c.Query("collection"); collection != "" {
+		match["collectionName"] = collection
+	}
+	if apiKeyID := c.Query("apiKeyId"); apiKeyID != "" {
+		match["apiKeyId"] = apiKeyID
+	}
+
+	var groupKey interface{}
+	switch groupBy := c.Query("groupBy"); groupBy {
+	case "":
+	case "collection":
+		groupKey = "$collectionName"
+	case "apiKey":
+		groupKey = "$apiKeyId"
+		// Dokumen sampel storage tidak punya API Key
+		if _, filtered := match["apiKeyId"]; !filtered {
+			match["apiKeyId"] = bson.M{"$ne": ""}
+		}
+	default:
+		return apierror.Validation("groupBy", "groupBy must be 'collection' or 'apiKey'")
+	}
+
+	group := bson.M{
+		"_id":          bson.M{"bucket": "$bucket", "key": groupKey},
+		"requests":     bson.M{"$sum": "$requests"},
+		"clientErrors": bson.M{"$sum": "$clientErrors"},
+		"serverErrors": bson.M{"$sum": "$serverErrors"},
+		"bytesIn":      bson.M{"$sum": "$bytesIn"},
+		"bytesOut":     bson.M{"$sum": "$bytesOut"},
+		"latencyMsSum": bson.M{"$sum": "$latencyMsSum"},
+		"storageBytes": bson.M{"$sum": "$storageBytes"},
+	}
+	latency := bson.M{}
+	for _, bound := range models.UsageLatencyBoundsMs {
+		name := "le_" + strconv.FormatInt(bound, 10)
+		group["latency_"+name] = bson.M{"$sum": "$latency." + name}
+		latency[name] = "$latency_" + name
+	}
+	group["latency_inf"] = bson.M{"$sum": "$latency.inf"}
+	latency["inf"] = "$latency_inf"
+
+	cursor, err := database.GetCollection("usage_stats").Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$group", Value: group}},
+		{{Key: "$set", Value: bson.M{"latency": latency}}},
+		{{Key: "$sort", Value: bson.D{{Key: "_id.bucket", Value: 1}, {Key: "_id.key", Value: 1}}}},
+	})
+	if err != nil {
+		return apierror.Internal(err, "Failed to aggregate usage stats")
+	}
+	var rows []usageRow
+	if err := cursor.All(ctx, &rows); err != nil {
+		return apierror.Internal(err, "Failed to decode usage stats")
+	}
+
+	// Total dijumlahkan dari semua bucket; storage diambil dari bucket terakhir yang punya sampel
+	points := make([]models.UsagePoint, 0, len(rows))
+	total := usageRow{Latency: map[string]int64{}}
+	var storageBucket time.Time
+	for _, row := range rows {
+		points = append(points, usagePoint(row))
+		total.Requests += row.Requests
+		total.ClientErrors += row.ClientErrors
+		total.ServerErrors += row.ServerErrors
+		total.BytesIn += row.BytesIn
+		total.BytesOut += row.BytesOut
+		total.LatencyMsSum += row.LatencyMsSum
+		for name, n := range row.Latency {
+			total.Latency[name] += n
+		}
+		if row.StorageBytes > 0 {
+			if row.ID.Bucket.After(storageBucket) {
+				storageBucket = row.ID.Bucket
+				total.StorageBytes = 0
+			}
+			if row.ID.Bucket.Equal(storageBucket) {
+				total.StorageBytes += row.StorageBytes
+			}
+		}
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"granularity": granularity,
+		"from":        from,
+		"to":          to,
+		"points":      points,
+		"totals":      usagePoint(total),
+	})
+}
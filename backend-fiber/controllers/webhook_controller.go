@@ -0,0 +1,270 @@
+// file: controllers/webhook_controller.go
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/fiber-mongo/starter-kit/apierror"   // Sesuaikan dengan nama modul Anda
+	"github.com/fiber-mongo/starter-kit/database"   // Sesuaikan dengan nama modul Anda
+	"github.com/fiber-mongo/starter-kit/middleware" // Sesuaikan dengan nama modul Anda
+	"github.com/fiber-mongo/starter-kit/models"     // Sesuaikan dengan nama modul Anda
+	"github.com/fiber-mongo/starter-kit/services"   // Sesuaikan dengan nama modul Anda
+	"github.com/fiber-mongo/starter-kit/webhooks"   // Sesuaikan dengan nama modul Anda
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	defaultDeliveryLimit = 50
+	maxDeliveryLimit     = 200
+)
+
+var validWebhookEvents = map[string]bool{
+	models.DocumentEventCreate: true,
+	models.DocumentEventUpdate: true,
+	models.DocumentEventDelete: true,
+}
+
+// Fungsi helper untuk memvalidasi input webhook
+func validateWebhookInput(ctx context.Context, input models.WebhookInput) error {
+	if err := webhooks.ValidateURL(ctx, input.URL); err != nil {
+		return err
+	}
+	if len(input.Events) == 0 {
+		return errors.New("at least one event is required (create, update, delete)")
+	}
+	for _, event := range input.Events {
+		if !validWebhookEvents[event] {
+			return fmt.Errorf("unknown event '%s' (expected create, update or delete)", event)
+		}
+	}
+	for _, collectionName := range input.Collections {
+		if collectionName == "" {
+			return errors.New("collection names must not be empty")
+		}
+	}
+	return nil
+}
+
+// Handler untuk POST /projects/{id}/webhooks (Daftarkan webhook baru)
+// Secret hanya dikembalikan di respons ini; simpan untuk memverifikasi header X-Webhook-Signature.
+func CreateWebhook(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	projObjID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return apierror.InvalidObjectID("Project")
+	}
+	if _, err := services.Projects().Get(ctx, projObjID); err != nil {
+		return err
+	}
+
+	var input models.WebhookInput
+	if err := c.BodyParser(&input); err != nil {
+		return apierror.InvalidBody("Invalid request body")
+	}
+	if err := validateWebhookInput(ctx, input); err != nil {
+		return apierror.Validation("", err.Error())
+	}
+
+	if input.Secret == "" {
+		input.Secret, err = generateSecureKey(32)
+		if err != nil {
+			return apierror.Internal(err, "Failed to generate webhook secret")
+		}
+	}
+	if input.Collections == nil {
+		input.Collections = []string{}
+	}
+
+	webhook := models.Webhook{
+		ID:          primitive.NewObjectID(),
+		ProjectID:   projObjID,
+		URL:         input.URL,
+		Collections: input.Collections,
+		Events:      input.Events,
+		Secret:      input.Secret,
+		Active:      input.Active == nil || *input.Active,
+		CreatedAt:   time.Now(),
+	}
+	c.Locals(middleware.AuditTargetIDKey, webhook.ID.Hex())
+	if _, err := database.GetCollection("webhooks").InsertOne(ctx, webhook); err != nil {
+		return apierror.Internal(err, "Failed to create webhook")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(webhook)
+}
+
+// Handler untuk GET /projects/{id}/webhooks (Daftar webhook proyek, tanpa secret)
+func GetWebhooks(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	projObjID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return apierror.InvalidObjectID("Project")
+	}
+	if _, err := services.Projects().Get(ctx, projObjID); err != nil {
+		return err
+	}
+
+	cursor, err := database.GetCollection("webhooks").Find(ctx,
+		bson.M{"projectId": projObjID},
+		options.Find().SetSort(bson.M{"createdAt": 1}).SetProjection(bson.M{"secret": 0}),
+	)
+	if err != nil {
+		return apierror.Internal(err, "Failed to fetch webhooks")
+	}
+	webhookList := []models.Webhook{}
+	if err := cursor.All(ctx, &webhookList); err != nil {
+		return apierror.Internal(err, "Failed to decode webhooks")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(webhookList)
+}
+
+// Handler untuk PUT /projects/{id}/webhooks/{webhookId} (Ubah webhook; secret hanya diganti jika dikirim)
+func UpdateWebhook(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	projObjID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return apierror.InvalidObjectID("Project")
+	}
+	webhookObjID, err := primitive.ObjectIDFromHex(c.Params("webhookId"))
+	if err != nil {
+		return apierror.InvalidObjectID("Webhook")
+	}
+
+	var input models.WebhookInput
+	if err := c.BodyParser(&input); err != nil {
+		return apierror.InvalidBody("Invalid request body")
+	}
+	if err := validateWebhookInput(ctx, input); err != nil {
+		return apierror.Validation("", err.Error())
+	}
+	if input.Collections == nil {
+		input.Collections = []string{}
+	}
+
+	update := bson.M{
+		"url":         input.URL,
+		"collections": input.Collections,
+		"events":      input.Events,
+	}
+	if input.Secret != "" {
+		update["secret"] = input.Secret
+	}
+	if input.Active != nil {
+		update["active"] = *input.Active
+	}
+
+	var webhook models.Webhook
+	err = database.GetCollection("webhooks").FindOneAndUpdate(ctx,
+		bson.M{"_id": webhookObjID, "projectId": projObjID},
+		bson.M{"$set": update},
+		options.FindOneAndUpdate().SetReturnDocument(options.After).SetProjection(bson.M{"secret": 0}),
+	).Decode(&webhook)
+	if err == mongo.ErrNoDocuments {
+		return apierror.NotFound(apierror.CodeWebhookNotFound, "Webhook not found")
+	}
+	if err != nil {
+		return apierror.Internal(err, "Failed to update webhook")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(webhook)
+}
+
+// Handler untuk DELETE /projects/{id}/webhooks/{webhookId} (Hapus webhook beserta log pengirimannya)
+func DeleteWebhook(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	projObjID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return apierror.InvalidObjectID("Project")
+	}
+	webhookObjID, err := primitive.ObjectIDFromHex(c.Params("webhookId"))
+	if err != nil {
+		return apierror.InvalidObjectID("Webhook")
+	}
+
+	result, err := database.GetCollection("webhooks").DeleteOne(ctx, bson.M{"_id": webhookObjID, "projectId": projObjID})
+	if err != nil {
+		return apierror.Internal(err, "Failed to delete webhook")
+	}
+	if result.DeletedCount == 0 {
+		return apierror.NotFound(apierror.CodeWebhookNotFound, "Webhook not found")
+	}
+	// Webhook sudah terhapus; log pengiriman yang gagal dibersihkan hanya dicatat di log
+	if _, err := database.GetCollection("webhook_deliveries").DeleteMany(ctx, bson.M{"webhookId": webhookObjID}); err != nil {
+		slog.WarnContext(ctx, "webhooks: failed to clean up deliveries", "webhookId", webhookObjID.Hex(), "error", err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Webhook deleted successfully"})
+}
+
+// Handler untuk GET /projects/{id}/webhooks/{webhookId}/deliveries?limit=N (Log pengiriman terbaru)
+func GetWebhookDeliveries(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	projObjID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return apierror.InvalidObjectID("Project")
+	}
+	webhookObjID, err := primitive.ObjectIDFromHex(c.Params("webhookId"))
+	if err != nil {
+		return apierror.InvalidObjectID("Webhook")
+	}
+	limit := c.QueryInt("limit", defaultDeliveryLimit)
+	if limit <= 0 || limit > maxDeliveryLimit {
+		return apierror.Validation("limit", fmt.Sprintf("limit must be between 1 and %d", maxDeliveryLimit))
+	}
+
+	deliveries, err := webhooks.ListDeliveries(ctx, projObjID, webhookObjID, int64(limit))
+	if err != nil {
+		return apierror.Internal(err, "Failed to fetch deliveries")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(deliveries)
+}
+
+// Handler untuk POST /projects/{id}/webhooks/{webhookId}/deliveries/{deliveryId}/redeliver (Kirim ulang secara manual)
+func RedeliverWebhook(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	projObjID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return apierror.InvalidObjectID("Project")
+	}
+	webhookObjID, err := primitive.ObjectIDFromHex(c.Params("webhookId"))
+	if err != nil {
+		return apierror.InvalidObjectID("Webhook")
+	}
+	deliveryObjID, err := primitive.ObjectIDFromHex(c.Params("deliveryId"))
+	if err != nil {
+		return apierror.InvalidObjectID("Delivery")
+	}
+
+	err = webhooks.Redeliver(ctx, projObjID, webhookObjID, deliveryObjID)
+	if err == mongo.ErrNoDocuments {
+		return apierror.NotFound(apierror.CodeDeliveryNotFound, "Delivery not found or currently being delivered")
+	}
+	if err != nil {
+		return apierror.Internal(err, "Failed to schedule redelivery")
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(fiber.Map{"message": "Delivery scheduled for redelivery"})
+}
@@ -0,0 +1,172 @@
+// file: corspolicy/corspolicy.go
+package corspolicy
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fiber-mongo/starter-kit/database" // Sesuaikan dengan nama modul Anda
+	"github.com/fiber-mongo/starter-kit/models"   // Sesuaikan dengan nama modul Anda
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	CollectionName = "cors_settings"
+
+	// Settings di-cache karena preflight dan setiap request Data API membutuhkannya
+	settingsCacheTTL = 30 * time.Second
+
+	DefaultMaxAge = 600
+	maxMaxAge     = 86400
+)
+
+var (
+	// Method dan header default untuk proyek yang belum mengatur CORS sendiri
+	DefaultMethods = []string{"GET", "POST", "PUT", "DELETE"}
+	DefaultHeaders = []string{"Content-Type", "X-API-Key", "X-User-ID", "X-Request-ID", "If-Match", "If-None-Match", "traceparent", "tracestate"}
+
+	// Header respons yang boleh dibaca JavaScript di browser (ETag, rate limit, kuota)
+	ExposeHeaders = "ETag, X-Request-ID, RateLimit-Limit, RateLimit-Remaining, RateLimit-Reset, Retry-After, X-Quota-Limit, X-Quota-Remaining"
+
+	allowedMethods = map[string]bool{"GET": true, "HEAD": true, "POST": true, "PUT": true, "PATCH": true, "DELETE": true}
+)
+
+type cachedSettings struct {
+	settings  models.CORSSettings
+	expiresAt time.Time
+}
+
+var (
+	cacheMu       sync.Mutex
+	settingsCache = map[primitive.ObjectID]cachedSettings{}
+)
+
+// DefaultSettings mengembalikan settings untuk proyek yang belum punya dokumen di koleksi "cors_settings"
+func DefaultSettings(projectID primitive.ObjectID) models.CORSSettings {
+	return models.CORSSettings{
+		ProjectID:    projectID,
+		AllowOrigins: []string{},
+		AllowMethods: append([]string{}, DefaultMethods...),
+		AllowHeaders: append([]string{}, DefaultHeaders...),
+		MaxAge:       DefaultMaxAge,
+	}
+}
+
+// Load membaca settings CORS sebuah proyek (dengan cache singkat)
+func Load(ctx context.Context, projectID primitive.ObjectID) (models.CORSSettings, error) {
+	now := time.Now()
+	cacheMu.Lock()
+	cached, ok := settingsCache[projectID]
+	cacheMu.Unlock()
+	if ok && now.Before(cached.expiresAt) {
+		return cached.settings, nil
+	}
+
+	settings := DefaultSettings(projectID)
+	err := database.GetCollection(CollectionName).FindOne(ctx, bson.M{"projectId": projectID}).Decode(&settings)
+	if err != nil && err != mongo.ErrNoDocuments {
+		return settings, err
+	}
+
+	cacheMu.Lock()
+	settingsCache[projectID] = cachedSettings{settings: settings, expiresAt: now.Add(settingsCacheTTL)}
+	cacheMu.Unlock()
+	return settings, nil
+}
+
+// Invalidate membuang cache settings sebuah proyek (dipanggil setelah settings diubah)
+func Invalidate(projectID primitive.ObjectID) {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+	delete(settingsCache, projectID)
+}
+
+// AllowsOrigin mengecek origin request terhadap daftar origin (persis, wildcard subdomain, atau "*")
+func AllowsOrigin(allowed []string, origin string) bool {
+	requested, err := url.Parse(origin)
+	if err != nil || requested.Scheme == "" || requested.Host == "" {
+		return false
+	}
+	scheme, host := strings.ToLower(requested.Scheme), strings.ToLower(requested.Host)
+
+	for _, pattern := range allowed {
+		if pattern == "*" {
+			return true
+		}
+		p, err := url.Parse(pattern)
+		if err != nil || strings.ToLower(p.Scheme) != scheme {
+			continue
+		}
+		patternHost := strings.ToLower(p.Host)
+		if patternHost == host {
+			return true
+		}
+		if suffix, ok := strings.CutPrefix(patternHost, "*"); ok && strings.HasSuffix(host, suffix) && len(host) > len(suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsMethod mengecek method yang diminta preflight (Access-Control-Request-Method)
+func AllowsMethod(settings models.CORSSettings, method string) bool {
+	for _, m := range settings.AllowMethods {
+		if strings.EqualFold(m, method) {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateOrigin memeriksa format origin yang akan disimpan
+func ValidateOrigin(origin string) error {
+	if origin == "*" {
+		return nil
+	}
+	u, err := url.Parse(origin)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || (u.Path != "" && u.Path != "/") || u.RawQuery != "" {
+		return fmt.Errorf("invalid origin %q (use scheme://host[:port], scheme://*.domain or *)", origin)
+	}
+	if strings.Contains(strings.TrimPrefix(u.Host, "*."), "*") {
+		return fmt.Errorf("invalid origin %q (a wildcard is only allowed as the first subdomain)", origin)
+	}
+	return nil
+}
+
+// NormalizeMethods mengubah method menjadi huruf besar dan menolak method yang tidak dikenal
+func NormalizeMethods(methods []string) ([]string, error) {
+	result := []string{}
+	for _, m := range methods {
+		m = strings.ToUpper(strings.TrimSpace(m))
+		if !allowedMethods[m] {
+			return nil, fmt.Errorf("unsupported method %q", m)
+		}
+		result = append(result, m)
+	}
+	return result, nil
+}
+
+// NormalizeHeaders membuang spasi dan menolak nama header yang tidak valid
+func NormalizeHeaders(headers []string) ([]string, error) {
+	result := []string{}
+	for _, h := range headers {
+		h = strings.TrimSpace(h)
+		if h == "" || strings.ContainsAny(h, " \t,:;\"()<>@[]{}/?=\\") {
+			return nil, fmt.Errorf("invalid header name %q", h)
+		}
+		result = append(result, h)
+	}
+	return result, nil
+}
+
+// ValidMaxAge mengecek durasi cache preflight (0 sampai 24 jam)
+func ValidMaxAge(seconds int) bool {
+	return seconds >= 0 && seconds <= maxMaxAge
+}
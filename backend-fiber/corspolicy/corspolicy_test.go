@@ -0,0 +1,55 @@
+// file: corspolicy/corspolicy_test.go
+package corspolicy
+
+import "testing"
+
+func TestAllowsOrigin(t *testing.T) {
+	allowed := []string{"https://app.example.com", "https://*.shop.test", "http://localhost:3000"}
+	cases := map[string]bool{
+		"https://app.example.com":  true,
+		"HTTPS://APP.EXAMPLE.COM":  true,
+		"http://app.example.com":   false,
+		"https://evil.example.com": false,
+		"https://a.shop.test":      true,
+		"https://a.b.shop.test":    true,
+		"https://shop.test":        false,
+		"https://evilshop.test":    false,
+		"http://localhost:3000":    true,
+		"http://localhost:3001":    false,
+		"null":                     false,
+	}
+	for origin, want := range cases {
+		if got := AllowsOrigin(allowed, origin); got != want {
+			t.Errorf("AllowsOrigin(%q) = %v, want %v", origin, got, want)
+		}
+	}
+	if !AllowsOrigin([]string{"*"}, "https://anything.example") {
+		t.Error("* should allow every origin")
+	}
+}
+
+func TestValidateOrigin(t *testing.T) {
+	for _, origin := range []string{"*", "https://app.example.com", "http://localhost:3000", "https://*.example.com"} {
+		if err := ValidateOrigin(origin); err != nil {
+			t.Errorf("ValidateOrigin(%q) = %v, want nil", origin, err)
+		}
+	}
+	for _, origin := range []string{"", "app.example.com", "ftp://example.com", "https://example.com/path", "https://a.*.example.com"} {
+		if err := ValidateOrigin(origin); err == nil {
+			t.Errorf("ValidateOrigin(%q) = nil, want error", origin)
+		}
+	}
+}
+
+func TestNormalizeMethodsAndHeaders(t *testing.T) {
+	methods, err := NormalizeMethods([]string{" get", "Post "})
+	if err != nil || len(methods) != 2 || methods[0] != "GET" || methods[1] != "POST" {
+		t.Fatalf("NormalizeMethods = %v, %v", methods, err)
+	}
+	if _, err := NormalizeMethods([]string{"TRACE"}); err == nil {
+		t.Error("TRACE should be rejected")
+	}
+	if _, err := NormalizeHeaders([]string{"X-API-Key", "Bad Header"}); err == nil {
+		t.Error("header names with spaces should be rejected")
+	}
+}
@@ -2,10 +2,11 @@ package database
 
 import (
 	"context"
-	"fmt"
-	"log"
-	"time"
+	"log/slog"
+	"os"
 	"github.com/fiber-mongo/starter-kit/config" // Sesuaikan dengan path modulmu
+	"github.com/fiber-mongo/starter-kit/metrics" // Sesuaikan dengan path modulmu
+	"github.com/fiber-mongo/starter-kit/tracing" // Sesuaikan dengan path modulmu
 	
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
@@ -15,40 +16,53 @@ var DB *mongo.Client // Variabel global untuk menyimpan instance koneksi
 
 // Fungsi untuk menghubungkan aplikasi dengan MongoDB
 func ConnectDB() {
-	uri := config.Env("MONGO_URI")
-	if uri == "" {
-		log.Fatal("MONGO_URI environment variable not set.")
-	}
-
-	client, err := mongo.NewClient(options.Client().ApplyURI(uri))
+	cfg := config.Get()
+	client, err := mongo.NewClient(options.Client().ApplyURI(cfg.MongoURI).
+		SetPoolMonitor(metrics.PoolMonitor(metrics.PlatformPool)).
+		SetMonitor(tracing.CommandMonitor("")))
 	if err != nil {
-		log.Fatal(err)
+		fatal("Invalid MongoDB connection settings", err)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.DBTimeout)
 	defer cancel()
 
 	err = client.Connect(ctx)
 	if err != nil {
-		log.Fatal(err)
+		fatal("Failed to connect to MongoDB", err)
 	}
 
 	// Cek koneksi
 	err = client.Ping(ctx, nil)
 	if err != nil {
-		log.Fatal("Failed to connect to MongoDB:", err)
+		fatal("Failed to connect to MongoDB", err)
 	}
 	
-	fmt.Println("✅ Successfully connected to MongoDB!")
+	slog.Info("Successfully connected to MongoDB")
 	DB = client
 }
 
 // Fungsi untuk mendapatkan koleksi tertentu dari database
 func GetCollection(collectionName string) *mongo.Collection {
-    dbName := config.Env("DB_NAME")
-    if dbName == "" {
-        log.Fatal("DB_NAME environment variable not set.")
-    }
-	collection := DB.Database(dbName).Collection(collectionName)
+	// Nama database dibaca dari konfigurasi yang sudah dimuat saat startup
+	collection := DB.Database(config.Get().DBName).Collection(collectionName)
 	return collection
-}
\ No newline at end of file
+}
+
+// Fungsi untuk menutup koneksi database platform (dipanggil saat server berhenti)
+func DisconnectDB(ctx context.Context) error {
+	if DB == nil {
+		return nil
+	}
+	return DB.Disconnect(ctx)
+}
+
+// Fungsi helper untuk menghentikan server saat koneksi database platform tidak bisa disiapkan
+func fatal(message string, err error) {
+	if err != nil {
+		slog.Error(message, "error", err)
+	} else {
+		slog.Error(message)
+	}
+	os.Exit(1)
+}
@@ -0,0 +1,76 @@
+// file: database/indexes.go
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Index di database platform yang dibuat saat startup. Index unik menjaga nomor versi
+// dan bucket statistik tetap unik walaupun server berjalan di beberapa instance.
+var platformIndexes = map[string][]mongo.IndexModel{
+	"schema_versions": {
+		{
+			Keys:    bson.D{{Key: "projectId", Value: 1}, {Key: "collectionName", Value: 1}, {Key: "version", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+	},
+	// Counter kuota request bulanan (package ratelimit); dihapus otomatis setelah periodenya berakhir
+	"request_quotas": {
+		{
+			Keys:    bson.D{{Key: "projectId", Value: 1}, {Key: "period", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "expiresAt", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
+	},
+	// Satu dokumen statistik per bucket; upsert dari beberapa instance tidak boleh membuat duplikat
+	"usage_stats": {
+		{
+			Keys: bson.D{
+				{Key: "projectId", Value: 1}, {Key: "collectionName", Value: 1}, {Key: "apiKeyId", Value: 1},
+				{Key: "granularity", Value: 1}, {Key: "bucket", Value: 1},
+			},
+			Options: options.Index().SetUnique(true),
+		},
+	},
+	// Audit log dibaca terurut waktu, seluruhnya atau per proyek
+	"audit_logs": {
+		{Keys: bson.D{{Key: "timestamp", Value: -1}}},
+		{Keys: bson.D{{Key: "projectId", Value: 1}, {Key: "timestamp", Value: -1}}},
+	},
+}
+
+// Index koleksi revisi "<nama>__revisions" di database user. Nomor revisi unik per dokumen;
+// dokumen counter (lihat services.nextRevision) tidak punya field revision sehingga tidak ikut index unik.
+var revisionIndexes = []mongo.IndexModel{
+	{
+		Keys: bson.D{{Key: "documentId", Value: 1}, {Key: "revision", Value: 1}},
+		Options: options.Index().SetUnique(true).
+			SetPartialFilterExpression(bson.M{"revision": bson.M{"$exists": true}}),
+	},
+}
+
+// Fungsi untuk membuat index database platform (idempotent, aman dipanggil setiap startup)
+func EnsureIndexes(ctx context.Context) error {
+	for collectionName, models := range platformIndexes {
+		if _, err := GetCollection(collectionName).Indexes().CreateMany(ctx, models); err != nil {
+			return fmt.Errorf("create indexes on %s: %w", collectionName, err)
+		}
+	}
+	return nil
+}
+
+// Fungsi untuk membuat index koleksi revisi di database user (dipanggil saat revisi diaktifkan)
+func EnsureRevisionIndexes(ctx context.Context, coll *mongo.Collection) error {
+	if _, err := coll.Indexes().CreateMany(ctx, revisionIndexes); err != nil {
+		return fmt.Errorf("create indexes on %s: %w", coll.Name(), err)
+	}
+	return nil
+}
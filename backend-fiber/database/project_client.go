@@ -0,0 +1,198 @@
+// file: database/project_client.go
+package database
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"net/url"
+	"strings"
+
+	"github.com/fiber-mongo/starter-kit/models"  // Sesuaikan dengan path modulmu
+	"github.com/fiber-mongo/starter-kit/secrets" // Sesuaikan dengan path modulmu
+	"github.com/fiber-mongo/starter-kit/tracing" // Sesuaikan dengan path modulmu
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// Mekanisme auth yang boleh dipilih untuk database user
+var AuthMechanisms = []string{"SCRAM-SHA-1", "SCRAM-SHA-256", "MONGODB-X509", "MONGODB-AWS", "PLAIN"}
+
+// Read preference yang dikenal driver MongoDB
+var ReadPreferences = []string{"primary", "primaryPreferred", "secondary", "secondaryPreferred", "nearest"}
+
+// Detail koneksi database user yang sudah didekripsi dan siap dipakai driver
+type ProjectConnection struct {
+	URI       string
+	TLSConfig *tls.Config // nil jika TLS (kalau ada) memakai pengaturan bawaan driver
+	key       string      // Pembeda cache client: berubah jika URI atau sertifikat berubah
+}
+
+// ClientOptions mengembalikan opsi driver untuk koneksi ini
+func (c ProjectConnection) ClientOptions() *options.ClientOptions {
+	opts := options.Client().ApplyURI(c.URI)
+	if c.TLSConfig != nil {
+		opts.SetTLSConfig(c.TLSConfig)
+	}
+	return opts
+}
+
+// Rahasia koneksi proyek dalam bentuk asli (belum terenkripsi)
+type ConnectionSecrets struct {
+	DBPassword       string // Password untuk opsi koneksi terstruktur
+	ConnectionString string
+	TLSCACert        string // PEM
+	TLSClientCert    string // PEM sertifikat + private key
+}
+
+// DecryptSecrets membuka password, connection string dan sertifikat yang tersimpan terenkripsi di proyek
+func DecryptSecrets(project models.Project) (ConnectionSecrets, error) {
+	var plain ConnectionSecrets
+	if project.DBPassword != "" {
+		decrypted, err := secrets.Decrypt(project.DBPassword)
+		switch {
+		case errors.Is(err, secrets.ErrMalformed):
+			// Proyek lama menyimpan password sebagai teks biasa
+			plain.DBPassword = project.DBPassword
+		case err != nil:
+			return plain, err
+		default:
+			plain.DBPassword = string(decrypted)
+		}
+	}
+	for _, field := range []struct {
+		stored string
+		target *string
+	}{
+		{project.ConnectionString, &plain.ConnectionString},
+		{project.TLSCACert, &plain.TLSCACert},
+		{project.TLSClientCert, &plain.TLSClientCert},
+	} {
+		if field.stored == "" {
+			continue
+		}
+		decrypted, err := secrets.Decrypt(field.stored)
+		if err != nil {
+			return plain, err
+		}
+		*field.target = string(decrypted)
+	}
+	return plain, nil
+}
+
+// NewProjectConnection menyusun koneksi database user dari detail proyek yang tersimpan
+func NewProjectConnection(project models.Project) (ProjectConnection, error) {
+	plain, err := DecryptSecrets(project)
+	if err != nil {
+		return ProjectConnection{}, err
+	}
+	return BuildConnection(project, plain)
+}
+
+// BuildConnection menyusun koneksi dari connection string (jika ada) atau opsi terstruktur proyek
+// (host, replica set, auth, TLS), ditambah sertifikat TLS. Dipakai juga untuk menguji detail yang belum disimpan.
+func BuildConnection(project models.Project, plain ConnectionSecrets) (ProjectConnection, error) {
+	uri := plain.ConnectionString
+	if uri == "" {
+		project.DBPassword = plain.DBPassword
+		uri = ProjectURI(project)
+	}
+
+	fingerprint := sha256.New()
+	fingerprint.Write([]byte(uri))
+	conn := ProjectConnection{URI: uri}
+
+	if plain.TLSCACert != "" || plain.TLSClientCert != "" {
+		conn.TLSConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	if plain.TLSCACert != "" {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(plain.TLSCACert)) {
+			return ProjectConnection{}, errors.New("CA certificate is not valid PEM")
+		}
+		conn.TLSConfig.RootCAs = pool
+		fingerprint.Write([]byte(plain.TLSCACert))
+	}
+	if plain.TLSClientCert != "" {
+		// Sertifikat dan private key disimpan dalam satu file PEM (seperti tlsCertificateKeyFile)
+		cert, err := tls.X509KeyPair([]byte(plain.TLSClientCert), []byte(plain.TLSClientCert))
+		if err != nil {
+			return ProjectConnection{}, err
+		}
+		conn.TLSConfig.Certificates = []tls.Certificate{cert}
+		fingerprint.Write([]byte(plain.TLSClientCert))
+	}
+
+	conn.key = hex.EncodeToString(fingerprint.Sum(nil))
+	return conn, nil
+}
+
+// ProjectURI menyusun connection string database user dari opsi koneksi terstruktur proyek.
+// project.DBPassword harus sudah berisi password asli (lihat BuildConnection).
+func ProjectURI(project models.Project) string {
+	hosts := []string{project.DBHost + ":" + project.DBPort}
+	if project.DBHost == "" {
+		hosts = nil
+	}
+	hosts = append(hosts, project.DBHosts...)
+
+	u := url.URL{Scheme: "mongodb", Host: strings.Join(hosts, ","), Path: "/" + project.DBName}
+	query := url.Values{}
+
+	// X.509 dan AWS tidak selalu memakai password; SCRAM tanpa password berarti tanpa auth (seperti sebelumnya)
+	externalAuth := project.AuthMechanism == "MONGODB-X509" || project.AuthMechanism == "MONGODB-AWS"
+	switch {
+	case project.DBUser != "" && project.DBPassword != "":
+		u.User = url.UserPassword(project.DBUser, project.DBPassword)
+	case project.DBUser != "" && externalAuth:
+		u.User = url.User(project.DBUser)
+	}
+	if u.User != nil || project.AuthMechanism != "" {
+		authSource := project.AuthSource
+		if externalAuth {
+			authSource = "$external"
+		}
+		if authSource != "" {
+			query.Set("authSource", authSource)
+		}
+	}
+	if project.AuthMechanism != "" {
+		query.Set("authMechanism", project.AuthMechanism)
+	}
+	if project.ReplicaSet != "" {
+		query.Set("replicaSet", project.ReplicaSet)
+	}
+	if project.ReadPreference != "" {
+		query.Set("readPreference", project.ReadPreference)
+	}
+	if project.TLS || project.TLSCACert != "" || project.TLSClientCert != "" {
+		query.Set("tls", "true")
+	}
+
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+// ProjectClient mengembalikan client untuk database user sebuah proyek.
+// Client di-cache per proyek (lihat UserClient), jadi pemanggil tidak perlu memanggil Disconnect.
+func ProjectClient(ctx context.Context, project models.Project) (*mongo.Client, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "connectToUserDB")
+	defer span.End()
+	span.SetAttributes(tracing.AttrProjectID.String(project.ID.Hex()))
+
+	conn, err := NewProjectConnection(project)
+	var client *mongo.Client
+	if err == nil {
+		client, err = UserClient(ctx, project.ID.Hex(), conn)
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "connect failed")
+	}
+	return client, err
+}
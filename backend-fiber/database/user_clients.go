@@ -0,0 +1,107 @@
+// file: database/user_clients.go
+package database
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/fiber-mongo/starter-kit/metrics" // Sesuaikan dengan path modulmu
+	"github.com/fiber-mongo/starter-kit/tracing" // Sesuaikan dengan path modulmu
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Client yang diganti/di-invalidate baru ditutup setelah jeda ini, supaya request yang masih memakainya selesai dulu
+const retiredClientGrace = time.Minute
+
+// Koneksi idle di pool database user ditutup setelah waktu ini
+const userPoolMaxIdle = 5 * time.Minute
+
+type userClient struct {
+	client *mongo.Client
+	key    string
+}
+
+var (
+	userClientsMu sync.Mutex
+	userClients   = map[string]*userClient{}
+)
+
+// UserClient mengembalikan client MongoDB untuk database sebuah proyek. Client (beserta connection pool-nya)
+// di-cache per proyek dan dipakai ulang oleh semua request; jika URI atau sertifikat proyek berubah, client lama diganti.
+// Jangan panggil Disconnect pada client yang dikembalikan.
+func UserClient(ctx context.Context, projectID string, conn ProjectConnection) (*mongo.Client, error) {
+	userClientsMu.Lock()
+	if cached, ok := userClients[projectID]; ok {
+		if cached.key == conn.key {
+			userClientsMu.Unlock()
+			return cached.client, nil
+		}
+		delete(userClients, projectID)
+		retireClient(cached.client)
+	}
+
+	// mongo.Connect tidak menunggu koneksi jaringan, jadi aman dipanggil selama lock dipegang
+	client, err := mongo.Connect(context.Background(), conn.ClientOptions().
+		SetMaxConnIdleTime(userPoolMaxIdle).
+		SetPoolMonitor(metrics.PoolMonitor(projectID)).
+		SetMonitor(tracing.CommandMonitor(projectID)))
+	if err != nil {
+		userClientsMu.Unlock()
+		return nil, err
+	}
+	userClients[projectID] = &userClient{client: client, key: conn.key}
+	metrics.SetProjectClients(len(userClients))
+	userClientsMu.Unlock()
+
+	// Pastikan client baru benar-benar bisa terhubung; jika tidak, jangan disimpan
+	if err := client.Ping(ctx, nil); err != nil {
+		userClientsMu.Lock()
+		if cached, ok := userClients[projectID]; ok && cached.client == client {
+			delete(userClients, projectID)
+			metrics.SetProjectClients(len(userClients))
+		}
+		userClientsMu.Unlock()
+		client.Disconnect(context.Background())
+		return nil, err
+	}
+	return client, nil
+}
+
+// InvalidateUserClient membuang client proyek dari cache (misalnya setelah detail koneksi diubah atau proyek dihapus)
+func InvalidateUserClient(projectID string) {
+	userClientsMu.Lock()
+	defer userClientsMu.Unlock()
+	if cached, ok := userClients[projectID]; ok {
+		delete(userClients, projectID)
+		metrics.SetProjectClients(len(userClients))
+		retireClient(cached.client)
+	}
+}
+
+// CloseUserClients menutup semua client database user (dipanggil saat server berhenti)
+func CloseUserClients(ctx context.Context) {
+	userClientsMu.Lock()
+	clients := userClients
+	userClients = map[string]*userClient{}
+	metrics.SetProjectClients(0)
+	userClientsMu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, cached := range clients {
+		wg.Add(1)
+		go func(client *mongo.Client) {
+			defer wg.Done()
+			client.Disconnect(ctx)
+		}(cached.client)
+	}
+	wg.Wait()
+}
+
+// Fungsi helper untuk menutup client lama setelah masa tenggang
+func retireClient(client *mongo.Client) {
+	time.AfterFunc(retiredClientGrace, func() {
+		client.Disconnect(context.Background())
+	})
+}
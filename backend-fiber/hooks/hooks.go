@@ -0,0 +1,306 @@
+// file: hooks/hooks.go
+package hooks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fiber-mongo/starter-kit/database" // Sesuaikan dengan nama modul Anda
+	"github.com/fiber-mongo/starter-kit/models"   // Sesuaikan dengan nama modul Anda
+
+	"github.com/expr-lang/expr"
+	"github.com/expr-lang/expr/ast"
+	"github.com/expr-lang/expr/vm"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	hooksCollectionName = "collection_hooks"
+
+	// Batas sandbox untuk setiap expression
+	maxExpressionLength = 2000
+	maxNodes            = 500
+	memoryBudget        = 100000
+	evaluationTimeout   = 100 * time.Millisecond
+	maxCachedPrograms   = 1000
+
+	// Variabel dan fungsi internal untuk menghentikan evaluasi saat batas waktu habis (lihat deadlinePatch)
+	contextVar    = "__ctx"
+	checkDeadline = "__checkDeadline"
+)
+
+var errTimeout = errors.New("expression timed out")
+
+// Error dari sebuah hook: penolakan oleh aksi "reject" atau kegagalan evaluasi expression
+type Error struct {
+	Hook     string
+	Message  string
+	Rejected bool
+}
+
+func (e *Error) Error() string {
+	if e.Rejected {
+		return e.Message
+	}
+	return fmt.Sprintf("hook '%s' failed: %s", e.Hook, e.Message)
+}
+
+// Data yang diproses oleh hook untuk satu penulisan dokumen
+type Input struct {
+	Event      string
+	Collection string
+	Document   bson.M   // Dokumen tersimpan yang menjadi dasar perubahan (nil untuk create)
+	Before     bson.M   // Dokumen sebelum penulisan, tersedia sebagai variabel "before"
+	Changes    bson.M   // Field yang ditulis; aksi "set" menambah/mengganti isinya
+	Unset      []string // Field yang dihapus oleh aksi "unset"
+}
+
+// Dokumen setelah perubahan diterapkan: document + changes - unset
+func (in *Input) doc() bson.M {
+	doc := bson.M{}
+	for k, v := range in.Document {
+		doc[k] = v
+	}
+	for k, v := range in.Changes {
+		doc[k] = v
+	}
+	for _, field := range in.Unset {
+		delete(doc, field)
+	}
+	return doc
+}
+
+func (in *Input) env(ctx context.Context) map[string]interface{} {
+	return map[string]interface{}{
+		contextVar:   ctx,
+		"doc":        in.doc(),
+		"changes":    in.Changes,
+		"before":     in.Before,
+		"event":      in.Event,
+		"collection": in.Collection,
+	}
+}
+
+// Contoh env untuk pengecekan nama variabel saat compile
+var sampleEnv = map[string]interface{}{
+	"doc": map[string]interface{}{}, "changes": map[string]interface{}{}, "before": map[string]interface{}{},
+	"event": "", "collection": "", contextVar: context.Background(),
+}
+
+// deadlinePatch menyisipkan pengecekan context di awal setiap predicate (body map, filter, all, reduce, ...).
+// Hanya predicate yang bisa berulang dalam jumlah besar, jadi evaluasi berhenti tidak lama setelah batas waktu habis.
+type deadlinePatch struct{}
+
+func (deadlinePatch) Visit(node *ast.Node) {
+	predicate, ok := (*node).(*ast.PredicateNode)
+	if !ok {
+		return
+	}
+	check := &ast.CallNode{
+		Callee:    &ast.IdentifierNode{Value: checkDeadline},
+		Arguments: []ast.Node{&ast.IdentifierNode{Value: contextVar}},
+	}
+	predicate.Node = &ast.SequenceNode{Nodes: []ast.Node{check, predicate.Node}}
+}
+
+var deadlineFunction = expr.Function(checkDeadline, func(params ...any) (any, error) {
+	if ctx, ok := params[0].(context.Context); ok && ctx.Err() != nil {
+		return nil, errTimeout
+	}
+	return nil, nil
+}, new(func(context.Context) any))
+
+type programKey struct {
+	code   string
+	asBool bool
+}
+
+var (
+	programsMu sync.Mutex
+	programs   = map[programKey]*vm.Program{}
+)
+
+// Fungsi helper untuk meng-compile expression. Hasil compile di-cache per kode (program aman dipakai bersamaan).
+func compile(code string, asBool bool) (*vm.Program, error) {
+	if len(code) > maxExpressionLength {
+		return nil, fmt.Errorf("expression is longer than %d characters", maxExpressionLength)
+	}
+	key := programKey{code, asBool}
+	programsMu.Lock()
+	program, ok := programs[key]
+	programsMu.Unlock()
+	if ok {
+		return program, nil
+	}
+
+	opts := []expr.Option{expr.Env(sampleEnv), expr.MaxNodes(maxNodes), deadlineFunction, expr.Patch(deadlinePatch{})}
+	if asBool {
+		opts = append(opts, expr.AsBool())
+	}
+	program, err := expr.Compile(code, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	programsMu.Lock()
+	if len(programs) >= maxCachedPrograms {
+		programs = map[programKey]*vm.Program{}
+	}
+	programs[key] = program
+	programsMu.Unlock()
+	return program, nil
+}
+
+// Validate memeriksa struktur hook dan meng-compile semua expression-nya
+func Validate(hook models.CollectionHook) error {
+	if hook.Name == "" {
+		return errors.New("name is required")
+	}
+	if hook.Trigger != models.HookBefore && hook.Trigger != models.HookAfter {
+		return errors.New("trigger must be 'before' or 'after'")
+	}
+	switch hook.Event {
+	case models.DocumentEventCreate, models.DocumentEventUpdate, models.DocumentEventDelete:
+	default:
+		return errors.New("event must be 'create', 'update' or 'delete'")
+	}
+	if len(hook.Actions) == 0 {
+		return errors.New("at least one action is required")
+	}
+	if hook.Condition != "" {
+		if _, err := compile(hook.Condition, true); err != nil {
+			return fmt.Errorf("invalid condition: %w", err)
+		}
+	}
+
+	for i, action := range hook.Actions {
+		switch action.Type {
+		case models.HookActionSet, models.HookActionUnset:
+			// Hook after-delete boleh mengubah dokumen yang di-soft delete (lihat DocumentScope.Delete)
+			if hook.Event == models.DocumentEventDelete && hook.Trigger == models.HookBefore {
+				return fmt.Errorf("action %d: '%s' is not allowed on before-delete hooks", i+1, action.Type)
+			}
+			if action.Field == "" || action.Field == "_id" || strings.ContainsAny(action.Field, ".$") {
+				return fmt.Errorf("action %d: invalid field '%s' (top-level fields only, not _id)", i+1, action.Field)
+			}
+			if action.Type == models.HookActionSet {
+				if _, err := compile(action.Expression, false); err != nil {
+					return fmt.Errorf("action %d: invalid expression: %w", i+1, err)
+				}
+			}
+		case models.HookActionReject:
+			if hook.Trigger != models.HookBefore {
+				return fmt.Errorf("action %d: reject is only allowed on before hooks", i+1)
+			}
+		default:
+			return fmt.Errorf("action %d: unknown type '%s' (expected set, unset or reject)", i+1, action.Type)
+		}
+	}
+	return nil
+}
+
+// Load mengambil hook aktif sebuah koleksi untuk satu event, terurut berdasarkan Order
+func Load(ctx context.Context, project models.Project, collection, event string) ([]models.CollectionHook, error) {
+	cursor, err := database.GetCollection(hooksCollectionName).Find(ctx,
+		bson.M{"projectId": project.ID, "collectionName": collection, "event": event, "active": true},
+		options.Find().SetSort(bson.D{{Key: "order", Value: 1}, {Key: "createdAt", Value: 1}}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	list := []models.CollectionHook{}
+	err = cursor.All(ctx, &list)
+	return list, err
+}
+
+// Filter memilih hook dengan trigger tertentu ("before" atau "after")
+func Filter(list []models.CollectionHook, trigger string) []models.CollectionHook {
+	filtered := []models.CollectionHook{}
+	for _, hook := range list {
+		if hook.Trigger == trigger {
+			filtered = append(filtered, hook)
+		}
+	}
+	return filtered
+}
+
+// Run menjalankan hook secara berurutan terhadap input. Aksi set/unset mengubah in.Changes dan in.Unset;
+// aksi reject menghentikan proses dengan *Error. Setiap expression dibatasi waktu dan memorinya.
+func Run(ctx context.Context, list []models.CollectionHook, in *Input) error {
+	for _, hook := range list {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if hook.Condition != "" {
+			matched, err := eval(ctx, hook.Condition, true, in.env)
+			if err != nil {
+				return &Error{Hook: hook.Name, Message: "condition: " + err.Error()}
+			}
+			if matched != true {
+				continue
+			}
+		}
+
+		for _, action := range hook.Actions {
+			switch action.Type {
+			case models.HookActionSet:
+				value, err := eval(ctx, action.Expression, false, in.env)
+				if err != nil {
+					return &Error{Hook: hook.Name, Message: fmt.Sprintf("set %s: %s", action.Field, err.Error())}
+				}
+				if in.Changes == nil {
+					in.Changes = bson.M{}
+				}
+				in.Changes[action.Field] = value
+				in.Unset = removeField(in.Unset, action.Field)
+			case models.HookActionUnset:
+				delete(in.Changes, action.Field)
+				if _, existed := in.Document[action.Field]; existed {
+					in.Unset = append(removeField(in.Unset, action.Field), action.Field)
+				}
+			case models.HookActionReject:
+				message := action.Message
+				if message == "" {
+					message = fmt.Sprintf("rejected by hook '%s'", hook.Name)
+				}
+				return &Error{Hook: hook.Name, Message: message, Rejected: true}
+			}
+		}
+	}
+	return nil
+}
+
+func removeField(fields []string, field string) []string {
+	kept := fields[:0]
+	for _, f := range fields {
+		if f != field {
+			kept = append(kept, f)
+		}
+	}
+	return kept
+}
+
+// Evaluasi satu expression dengan batas waktu dan memory budget. Evaluasi berjalan di goroutine pemanggil;
+// saat batas waktu habis, pengecekan yang disisipkan deadlinePatch menghentikan VM.
+func eval(ctx context.Context, code string, asBool bool, env func(context.Context) map[string]interface{}) (interface{}, error) {
+	program, err := compile(code, asBool)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, evaluationTimeout)
+	defer cancel()
+
+	machine := vm.VM{MemoryBudget: memoryBudget}
+	value, err := machine.Run(program, env(ctx))
+	if err != nil && ctx.Err() != nil {
+		return nil, errTimeout
+	}
+	return value, err
+}
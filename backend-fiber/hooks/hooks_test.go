@@ -0,0 +1,214 @@
+package hooks
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fiber-mongo/starter-kit/models" // Sesuaikan dengan path modulmu
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestEvalStopsWhenContextIsDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// Pengecekan deadline disisipkan di setiap predicate, jadi loop berhenti di iterasi pertama
+	_, err := eval(ctx, `all(1..1000, {# > 0})`, true, (&Input{}).env)
+	if !errors.Is(err, errTimeout) {
+		t.Fatalf("err = %v, want %v", err, errTimeout)
+	}
+}
+
+func TestEvalFinishesWithinTimeout(t *testing.T) {
+	start := time.Now()
+	value, err := eval(context.Background(), `all(1..1000, {# > 0})`, true, (&Input{}).env)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value != true {
+		t.Fatalf("value = %v, want true", value)
+	}
+	if elapsed := time.Since(start); elapsed > evaluationTimeout {
+		t.Fatalf("evaluation took %s", elapsed)
+	}
+}
+
+func TestCompileCachesPrograms(t *testing.T) {
+	first, err := compile(`doc.price > 2`, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := compile(`doc.price > 2`, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first != second {
+		t.Fatal("expected the compiled program to be reused")
+	}
+
+	// Kode yang sama tanpa AsBool di-compile dan di-cache terpisah
+	value, err := compile(`doc.price > 2`, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value == first {
+		t.Fatal("programs compiled with and without AsBool must be cached separately")
+	}
+}
+
+func TestEvalUsesEnvironment(t *testing.T) {
+	in := &Input{Event: "create", Changes: map[string]interface{}{"price": 10}}
+	value, err := eval(context.Background(), `changes.price * 2`, false, in.env)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value != 20 {
+		t.Fatalf("value = %v, want 20", value)
+	}
+}
+
+func TestRun(t *testing.T) {
+	set := func(field, expression string) models.HookAction {
+		return models.HookAction{Type: models.HookActionSet, Field: field, Expression: expression}
+	}
+	tests := []struct {
+		name      string
+		hooks     []models.CollectionHook
+		in        Input
+		changes   bson.M
+		unset     []string
+		rejection string
+	}{
+		{
+			name:    "set computes a field from the changes",
+			hooks:   []models.CollectionHook{{Name: "total", Actions: []models.HookAction{set("total", "changes.price * changes.qty")}}},
+			in:      Input{Changes: bson.M{"price": 2, "qty": 3}},
+			changes: bson.M{"price": 2, "qty": 3, "total": 6},
+		},
+		{
+			name:    "condition false skips the hook",
+			hooks:   []models.CollectionHook{{Name: "default", Condition: `"status" in changes`, Actions: []models.HookAction{set("status", `"draft"`)}}},
+			in:      Input{Changes: bson.M{"name": "a"}},
+			changes: bson.M{"name": "a"},
+		},
+		{
+			name: "later hooks see earlier changes",
+			hooks: []models.CollectionHook{
+				{Name: "first", Actions: []models.HookAction{set("slug", "lower(changes.name)")}},
+				{Name: "second", Condition: `changes.slug == "kopi"`, Actions: []models.HookAction{set("featured", "true")}},
+			},
+			in:      Input{Changes: bson.M{"name": "Kopi"}},
+			changes: bson.M{"name": "Kopi", "slug": "kopi", "featured": true},
+		},
+		{
+			name:    "unset removes a pending change and an existing field",
+			hooks:   []models.CollectionHook{{Name: "strip", Actions: []models.HookAction{{Type: models.HookActionUnset, Field: "secret"}}}},
+			in:      Input{Document: bson.M{"secret": "x"}, Changes: bson.M{"secret": "y", "name": "a"}},
+			changes: bson.M{"name": "a"},
+			unset:   []string{"secret"},
+		},
+		{
+			name:    "unset of a field the document does not have is a no-op",
+			hooks:   []models.CollectionHook{{Name: "strip", Actions: []models.HookAction{{Type: models.HookActionUnset, Field: "secret"}}}},
+			in:      Input{Document: bson.M{}, Changes: bson.M{"secret": "y"}},
+			changes: bson.M{},
+		},
+		{
+			name:      "reject stops with the configured message",
+			hooks:     []models.CollectionHook{{Name: "positive", Condition: "changes.price <= 0", Actions: []models.HookAction{{Type: models.HookActionReject, Message: "price must be positive"}}}},
+			in:        Input{Changes: bson.M{"price": 0}},
+			rejection: "price must be positive",
+		},
+		{
+			name:      "reject without a message names the hook",
+			hooks:     []models.CollectionHook{{Name: "closed", Actions: []models.HookAction{{Type: models.HookActionReject}}}},
+			in:        Input{},
+			rejection: "rejected by hook 'closed'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := tt.in
+			err := Run(context.Background(), tt.hooks, &in)
+			if tt.rejection != "" {
+				var hookErr *Error
+				if !errors.As(err, &hookErr) || !hookErr.Rejected || hookErr.Message != tt.rejection {
+					t.Fatalf("err = %v, want rejection %q", err, tt.rejection)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(in.Changes) != len(tt.changes) {
+				t.Fatalf("changes = %v, want %v", in.Changes, tt.changes)
+			}
+			for k, v := range tt.changes {
+				if in.Changes[k] != v {
+					t.Fatalf("changes[%s] = %v, want %v", k, in.Changes[k], v)
+				}
+			}
+			if strings.Join(in.Unset, ",") != strings.Join(tt.unset, ",") {
+				t.Fatalf("unset = %v, want %v", in.Unset, tt.unset)
+			}
+		})
+	}
+}
+
+func TestRunReportsEvaluationErrors(t *testing.T) {
+	hook := models.CollectionHook{Name: "broken", Condition: "changes.price > 0", Actions: []models.HookAction{{Type: models.HookActionReject}}}
+	err := Run(context.Background(), []models.CollectionHook{hook}, &Input{Changes: bson.M{"price": "free"}})
+	var hookErr *Error
+	if !errors.As(err, &hookErr) || hookErr.Rejected || !strings.HasPrefix(hookErr.Message, "condition:") {
+		t.Fatalf("err = %v, want a condition error that is not a rejection", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	valid := func(trigger, event string, actions ...models.HookAction) models.CollectionHook {
+		return models.CollectionHook{Name: "h", Trigger: trigger, Event: event, Actions: actions}
+	}
+	set := models.HookAction{Type: models.HookActionSet, Field: "total", Expression: "1 + 1"}
+	reject := models.HookAction{Type: models.HookActionReject}
+
+	tests := []struct {
+		name    string
+		hook    models.CollectionHook
+		wantErr string
+	}{
+		{"before create set", valid(models.HookBefore, models.DocumentEventCreate, set), ""},
+		{"before update reject", valid(models.HookBefore, models.DocumentEventUpdate, reject), ""},
+		{"before delete reject", valid(models.HookBefore, models.DocumentEventDelete, reject), ""},
+		{"after delete set", valid(models.HookAfter, models.DocumentEventDelete, set), ""},
+		{"missing name", models.CollectionHook{Trigger: models.HookBefore, Event: models.DocumentEventCreate, Actions: []models.HookAction{set}}, "name is required"},
+		{"unknown trigger", valid("during", models.DocumentEventCreate, set), "trigger must be"},
+		{"unknown event", valid(models.HookBefore, "read", set), "event must be"},
+		{"no actions", valid(models.HookBefore, models.DocumentEventCreate), "at least one action"},
+		{"set on before delete", valid(models.HookBefore, models.DocumentEventDelete, set), "not allowed on before-delete hooks"},
+		{"reject on after hook", valid(models.HookAfter, models.DocumentEventCreate, reject), "reject is only allowed on before hooks"},
+		{"set _id", valid(models.HookBefore, models.DocumentEventCreate, models.HookAction{Type: models.HookActionSet, Field: "_id", Expression: "1"}), "invalid field"},
+		{"nested field", valid(models.HookBefore, models.DocumentEventCreate, models.HookAction{Type: models.HookActionUnset, Field: "a.b"}), "invalid field"},
+		{"bad expression", valid(models.HookBefore, models.DocumentEventCreate, models.HookAction{Type: models.HookActionSet, Field: "x", Expression: "1 +"}), "invalid expression"},
+		{"unknown variable", valid(models.HookBefore, models.DocumentEventCreate, models.HookAction{Type: models.HookActionSet, Field: "x", Expression: "os.Exit(1)"}), "invalid expression"},
+		{"non-boolean condition", models.CollectionHook{Name: "h", Trigger: models.HookBefore, Event: models.DocumentEventCreate, Condition: `"yes"`, Actions: []models.HookAction{reject}}, "invalid condition"},
+		{"unknown action", valid(models.HookBefore, models.DocumentEventCreate, models.HookAction{Type: "notify"}), "unknown type"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.hook)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("err = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+// file: jobs/jobs.go
+package jobs
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/fiber-mongo/starter-kit/database" // Sesuaikan dengan nama modul Anda
+	"github.com/fiber-mongo/starter-kit/models"   // Sesuaikan dengan nama modul Anda
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Fungsi yang dijalankan oleh sebuah job. Panggil progress untuk melaporkan kemajuan.
+type Func func(ctx context.Context, progress func(processed, total int64)) (bson.M, error)
+
+const (
+	jobsCollectionName    = "jobs"
+	progressUpdateTimeout = 5 * time.Second
+)
+
+var (
+	wg                 sync.WaitGroup
+	baseCtx, cancelAll = context.WithCancel(context.Background())
+	errPanic           = errors.New("job panicked, see server logs")
+)
+
+// Start menyimpan job baru ke koleksi "jobs" lalu menjalankannya di goroutine terpisah.
+// Status job bisa dipantau lewat Get.
+func Start(ctx context.Context, projectID primitive.ObjectID, jobType string, params bson.M, fn Func) (models.Job, error) {
+	job := models.Job{
+		ID:        primitive.NewObjectID(),
+		ProjectID: projectID,
+		Type:      jobType,
+		Params:    params,
+		Status:    models.JobQueued,
+		CreatedAt: time.Now(),
+	}
+	if _, err := database.GetCollection(jobsCollectionName).InsertOne(ctx, job); err != nil {
+		return job, err
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		run(job.ID, fn)
+	}()
+	return job, nil
+}
+
+// Get mengambil satu job milik sebuah proyek
+func Get(ctx context.Context, projectID, jobID primitive.ObjectID) (models.Job, error) {
+	var job models.Job
+	err := database.GetCollection(jobsCollectionName).FindOne(ctx, bson.M{"_id": jobID, "projectId": projectID}).Decode(&job)
+	return job, err
+}
+
+// RecoverInterrupted menandai job yang masih "queued"/"running" sebagai gagal.
+// Dipanggil saat startup, karena job dari proses sebelumnya tidak mungkin masih berjalan.
+func RecoverInterrupted(ctx context.Context) error {
+	now := time.Now()
+	_, err := database.GetCollection(jobsCollectionName).UpdateMany(ctx,
+		bson.M{"status": bson.M{"$in": []string{models.JobQueued, models.JobRunning}}},
+		bson.M{"$set": bson.M{"status": models.JobFailed, "error": "interrupted by server restart", "finishedAt": now}},
+	)
+	return err
+}
+
+// Wait menunggu semua job yang sedang berjalan selesai. Jika ctx habis lebih dulu,
+// context semua job dibatalkan dan Wait mengembalikan ctx.Err().
+func Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		cancelAll()
+		<-done
+		return ctx.Err()
+	}
+}
+
+func run(jobID primitive.ObjectID, fn Func) {
+	jobs := database.GetCollection(jobsCollectionName)
+	setFields := func(fields bson.M) {
+		ctx, cancel := context.WithTimeout(context.Background(), progressUpdateTimeout)
+		defer cancel()
+		if _, err := jobs.UpdateOne(ctx, bson.M{"_id": jobID}, bson.M{"$set": fields}); err != nil {
+			slog.Error("jobs: failed to update job", "jobId", jobID.Hex(), "error", err)
+		}
+	}
+
+	startedAt := time.Now()
+	setFields(bson.M{"status": models.JobRunning, "startedAt": startedAt})
+
+	progress := func(processed, total int64) {
+		setFields(bson.M{"processed": processed, "total": total})
+	}
+
+	result, err := func() (result bson.M, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				slog.Error("jobs: job panicked", "jobId", jobID.Hex(), "panic", r)
+				err = errPanic
+			}
+		}()
+		return fn(baseCtx, progress)
+	}()
+
+	finishedAt := time.Now()
+	if err != nil {
+		setFields(bson.M{"status": models.JobFailed, "error": err.Error(), "finishedAt": finishedAt})
+		return
+	}
+	setFields(bson.M{"status": models.JobCompleted, "result": result, "finishedAt": finishedAt})
+}
@@ -0,0 +1,144 @@
+// file: logging/logging.go
+package logging
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Key Locals tempat middleware requestid Fiber menyimpan request ID
+const RequestIDLocalsKey = "requestid"
+
+type requestIDKey struct{}
+
+// Handler slog yang menambahkan request ID (dan trace ID jika ada span aktif) dari context ke setiap baris log
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if id := RequestIDFromContext(ctx); id != "" {
+		r.AddAttrs(slog.String("requestId", id))
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		r.AddAttrs(slog.String("traceId", sc.TraceID().String()))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
+
+// Setup memasang logger JSON ke stdout sebagai logger default (juga untuk package log standar).
+// level: "debug", "info" (default), "warn" atau "error".
+func Setup(level string) {
+	var l slog.Level
+	switch strings.ToLower(level) {
+	case "debug":
+		l = slog.LevelDebug
+	case "warn", "warning":
+		l = slog.LevelWarn
+	case "error":
+		l = slog.LevelError
+	default:
+		l = slog.LevelInfo
+	}
+	slog.SetDefault(slog.New(contextHandler{slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: l})}))
+}
+
+// WithRequestID menyimpan request ID di context supaya ikut tercatat di log yang memakai context tersebut
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext mengembalikan request ID dari context (kosong jika tidak ada)
+func RequestIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// RequestID mengembalikan request ID milik request Fiber ini
+func RequestID(c *fiber.Ctx) string {
+	id, _ := c.Locals(RequestIDLocalsKey).(string)
+	return id
+}
+
+// Middleware untuk access log terstruktur. Dipasang setelah requestid.New(): request ID disalin ke
+// UserContext, dicatat di setiap baris log, dan ditambahkan ke body respons error JSON.
+func Middleware(c *fiber.Ctx) error {
+	start := time.Now()
+	id := RequestID(c)
+	ctx := WithRequestID(c.UserContext(), id)
+	c.SetUserContext(ctx)
+
+	// Error dari handler langsung diubah menjadi respons di sini, supaya status dan body-nya bisa dicatat
+	if err := c.Next(); err != nil {
+		if handlerErr := c.App().ErrorHandler(c, err); handlerErr != nil {
+			_ = c.SendStatus(fiber.StatusInternalServerError)
+		}
+	}
+
+	status := c.Response().StatusCode()
+	if status >= fiber.StatusBadRequest {
+		attachRequestID(c, id)
+	}
+
+	level := slog.LevelInfo
+	switch {
+	case status >= fiber.StatusInternalServerError:
+		level = slog.LevelError
+	case status >= fiber.StatusBadRequest:
+		level = slog.LevelWarn
+	}
+	route := ""
+	if r := c.Route(); r != nil {
+		route = r.Path
+	}
+	slog.LogAttrs(ctx, level, "request",
+		slog.String("method", c.Method()),
+		slog.String("path", c.Path()),
+		slog.String("route", route),
+		slog.Int("status", status),
+		slog.Float64("latencyMs", float64(time.Since(start).Microseconds())/1000),
+		slog.String("ip", c.IP()),
+		slog.Int("bytesIn", len(c.Request().Body())),
+	)
+	return nil
+}
+
+// Fungsi helper untuk menambahkan requestId ke body respons error berbentuk {"error": ...}
+func attachRequestID(c *fiber.Ctx, id string) {
+	if id == "" || c.Response().IsBodyStream() ||
+		!strings.HasPrefix(string(c.Response().Header.ContentType()), fiber.MIMEApplicationJSON) {
+		return
+	}
+	var body map[string]interface{}
+	if err := json.Unmarshal(c.Response().Body(), &body); err != nil {
+		return
+	}
+	if _, ok := body["error"]; !ok {
+		return
+	}
+	if _, ok := body["requestId"]; ok {
+		return
+	}
+	body["requestId"] = id
+	if encoded, err := json.Marshal(body); err == nil {
+		c.Response().SetBody(encoded)
+	}
+}
@@ -2,32 +2,180 @@
 package main
 
 import (
-	"log"
+	"context"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
+	"github.com/fiber-mongo/starter-kit/apierror"
 	"github.com/fiber-mongo/starter-kit/config"
+	"github.com/fiber-mongo/starter-kit/controllers"
+	"github.com/fiber-mongo/starter-kit/corspolicy"
 	"github.com/fiber-mongo/starter-kit/database"
+	"github.com/fiber-mongo/starter-kit/jobs"
+	"github.com/fiber-mongo/starter-kit/logging"
+	"github.com/fiber-mongo/starter-kit/metrics"
+	"github.com/fiber-mongo/starter-kit/middleware"
 	"github.com/fiber-mongo/starter-kit/routes"
+	"github.com/fiber-mongo/starter-kit/tracing"
+	"github.com/fiber-mongo/starter-kit/usage"
+	"github.com/fiber-mongo/starter-kit/webhooks"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
-	"github.com/gofiber/fiber/v2/middleware/logger"
+	"github.com/gofiber/fiber/v2/middleware/requestid"
 )
 
 func main() {
-	app := fiber.New()
-	app.Use(logger.New())
-	// UBAH BAGIAN INI
+	// Konfigurasi dimuat sekali dari environment, .env (opsional) dan config.yaml (opsional)
+	cfg, err := config.Load()
+	if err != nil {
+		logging.Setup("")
+		slog.Error("Invalid configuration", "error", err)
+		os.Exit(1)
+	}
+
+	// Log JSON terstruktur; level diatur lewat LOG_LEVEL (debug, info, warn, error)
+	logging.Setup(cfg.LogLevel)
+
+	// Tracing OpenTelemetry; exporter diatur lewat OTEL_TRACES_EXPORTER (otlp, stdout, atau none)
+	if err := tracing.Setup(context.Background(), cfg.TracesExporter); err != nil {
+		slog.Error("Failed to set up tracing", "error", err)
+		os.Exit(1)
+	}
+
+	// Semua error (termasuk panic) dikirim dengan format {"error", "code", "fields", "requestId"}
+	app := fiber.New(fiber.Config{
+		ErrorHandler: apierror.Handler,
+		BodyLimit:    int(cfg.BodyLimit),
+		ReadTimeout:  cfg.ReadTimeout,
+		WriteTimeout: cfg.WriteTimeout,
+		IdleTimeout:  cfg.IdleTimeout,
+	})
+	// Request ID diambil dari header X-Request-ID atau dibuat baru, lalu dicatat di log dan respons error
+	app.Use(requestid.New())
+	app.Use(logging.Middleware)
+	app.Use(metrics.Middleware)
+	// Span per request; header traceparent dari client dipakai sebagai parent
+	app.Use(tracing.Middleware)
+	app.Use(apierror.Recover())
+	// CORS API manajemen (origin dari konfigurasi CORS_ALLOW_ORIGINS).
+	// Data API, GraphQL dan realtime memakai aturan CORS per proyek (middleware.ProjectCORS).
 	app.Use(cors.New(cors.Config{
-		AllowOrigins: "http://localhost:3000", // Izinkan frontend development server
-		AllowHeaders: "Origin, Content-Type, Accept, X-API-Key",
+		Next:          middleware.UsesProjectCORS,
+		AllowOrigins:  strings.Join(cfg.CORSOrigins, ", "), // Default: frontend development server
+		AllowHeaders:  "Origin, Content-Type, Accept, X-API-Key, X-User-ID, X-Request-ID, If-Match, If-None-Match, traceparent, tracestate",
+		ExposeHeaders: corspolicy.ExposeHeaders,
 	}))
 
 	// SAJIKAN FILE STATIS DARI FOLDER "public"
 	app.Static("/", "./public")
 
 	database.ConnectDB()
+
+	// Index unik (nomor versi, bucket statistik, kuota request, ...) dibuat sebelum server menerima request.
+	// Tanpa index tersebut nomor versi dan kuota tidak lagi dijamin unik, jadi server tidak dijalankan.
+	indexCtx, cancelIndexes := context.WithTimeout(context.Background(), cfg.DBTimeout)
+	if err := database.EnsureIndexes(indexCtx); err != nil {
+		cancelIndexes()
+		slog.Error("Failed to create database indexes", "error", err)
+		os.Exit(1)
+	}
+	cancelIndexes()
+
+	// Job yang masih "running" dari proses sebelumnya tidak akan pernah selesai
+	recoverCtx, cancel := context.WithTimeout(context.Background(), cfg.DBTimeout)
+	if err := jobs.RecoverInterrupted(recoverCtx); err != nil {
+		slog.Warn("Failed to recover interrupted jobs", "error", err)
+	}
+	cancel()
+
+	// Worker pengiriman webhook (antrian tersimpan di koleksi "webhook_deliveries")
+	webhooks.Start()
+
+	// Statistik pemakaian Data API ditulis berkala ke koleksi "usage_stats"; ukuran storage dicatat tiap jam
+	usage.Start(controllers.SampleStorage)
+
 	routes.SetupRoutes(app)
 
-	port := config.Env("PORT")
-	log.Fatal(app.Listen(":" + port))
+	// Server berjalan di goroutine; main menunggu SIGINT/SIGTERM lalu mematikan server dengan rapi
+	serverErr := make(chan error, 1)
+	go func() {
+		serverErr <- app.Listen(":" + cfg.Port)
+	}()
+
+	quit := make(chan os.Signal, 2)
+	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
+
+	exitCode := 0
+	select {
+	case sig := <-quit:
+		slog.Info("Shutting down", "signal", sig.String(), "timeout", cfg.ShutdownTimeout.String())
+		// Sinyal kedua menghentikan proses tanpa menunggu
+		go func() {
+			<-quit
+			slog.Warn("Forced shutdown")
+			os.Exit(1)
+		}()
+	case err := <-serverErr:
+		slog.Error("Server stopped", "error", err)
+		exitCode = 1
+	}
+
+	if !shutdown(app, cfg) {
+		exitCode = 1
+	}
+	os.Exit(exitCode)
+}
+
+// Fungsi helper untuk mematikan server: berhenti menerima request, menunggu request yang berjalan,
+// menyelesaikan job, webhook dan statistik yang tertunda, lalu menutup semua koneksi MongoDB.
+// Mengembalikan false jika ada langkah yang tidak selesai dalam batas waktu.
+func shutdown(app *fiber.App, cfg *config.Config) bool {
+	clean := true
+	deadline := time.Now().Add(cfg.ShutdownTimeout)
+
+	// Stream realtime tidak pernah selesai sendiri, jadi ditutup lebih dulu
+	controllers.CloseRealtimeStreams()
+	if err := app.ShutdownWithTimeout(time.Until(deadline)); err != nil {
+		slog.Warn("Failed to drain in-flight requests", "error", err)
+		clean = false
+	}
+
+	ctx, cancel := context.WithDeadline(context.Background(), deadline)
+	defer cancel()
+
+	// Job yang belum selesai saat batas waktu habis dibatalkan dan ditandai gagal saat startup berikutnya
+	if err := jobs.Wait(ctx); err != nil {
+		slog.Warn("Background jobs did not finish in time", "error", err)
+		clean = false
+	}
+	// Antrian webhook tersimpan di database; hanya pengiriman yang sedang berjalan yang ditunggu
+	if err := webhooks.Stop(ctx); err != nil {
+		slog.Warn("Webhook deliveries did not finish in time", "error", err)
+		clean = false
+	}
+	if err := usage.Stop(ctx); err != nil {
+		slog.Warn("Failed to flush usage statistics", "error", err)
+		clean = false
+	}
+
+	// Koneksi tetap ditutup walaupun batas waktu di atas sudah habis
+	closeCtx, closeCancel := context.WithTimeout(context.Background(), cfg.DBTimeout)
+	defer closeCancel()
+	database.CloseUserClients(closeCtx)
+	if err := database.DisconnectDB(closeCtx); err != nil {
+		slog.Warn("Failed to disconnect from MongoDB", "error", err)
+		clean = false
+	}
+	if err := tracing.Shutdown(closeCtx); err != nil {
+		slog.Warn("Failed to flush traces", "error", err)
+		clean = false
+	}
+
+	slog.Info("Server stopped", "clean", clean)
+	return clean
 }
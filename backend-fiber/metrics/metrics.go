@@ -0,0 +1,159 @@
+// file: metrics/metrics.go
+package metrics
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/fiber-mongo/starter-kit/apierror" // Sesuaikan dengan path modulmu
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.mongodb.org/mongo-driver/event"
+)
+
+// Nama pool untuk koneksi ke database platform; pool database user memakai ID proyek
+const PlatformPool = "platform"
+
+var (
+	httpRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "Number of HTTP requests by method, route and status code.",
+	}, []string{"method", "route", "status"})
+
+	httpDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "HTTP request latency by method and route.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route"})
+
+	uploadBytes = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "upload_bytes_total",
+		Help: "Total size of files uploaded through the Data API.",
+	})
+
+	uploadFiles = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "upload_files_total",
+		Help: "Number of files uploaded through the Data API.",
+	})
+
+	poolOpen = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "mongo_pool_connections_open",
+		Help: "Open MongoDB connections per pool (platform or project ID).",
+	}, []string{"pool"})
+
+	poolInUse = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "mongo_pool_connections_in_use",
+		Help: "MongoDB connections currently checked out per pool.",
+	}, []string{"pool"})
+
+	poolCheckoutFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "mongo_pool_checkout_failures_total",
+		Help: "Failed MongoDB connection checkouts per pool.",
+	}, []string{"pool"})
+
+	projectClients = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "mongo_project_clients",
+		Help: "Number of cached MongoDB clients for project databases.",
+	})
+)
+
+// Middleware untuk mencatat jumlah dan latency request HTTP. Label route memakai pola rute
+// (misalnya /api/v1/data/:projectId/:collectionName), bukan path asli, supaya jumlah series tetap kecil.
+func Middleware(c *fiber.Ctx) error {
+	start := time.Now()
+	err := c.Next()
+
+	status := c.Response().StatusCode()
+	if err != nil {
+		status = apierror.Status(err)
+	}
+	route := "unmatched"
+	if r := c.Route(); r != nil && r.Path != "/" && r.Path != "" {
+		route = r.Path
+	}
+
+	httpRequests.WithLabelValues(c.Method(), route, strconv.Itoa(status)).Inc()
+	httpDuration.WithLabelValues(c.Method(), route).Observe(time.Since(start).Seconds())
+	return err
+}
+
+// RecordUpload mencatat satu file yang diupload
+func RecordUpload(size int64) {
+	uploadFiles.Inc()
+	uploadBytes.Add(float64(size))
+}
+
+// SetProjectClients mengisi jumlah client database user yang sedang di-cache
+func SetProjectClients(n int) {
+	projectClients.Set(float64(n))
+}
+
+// Jumlah monitor (client) yang masih hidup per label pool. Saat kredensial proyek berganti, client lama
+// dan client baru sempat hidup bersamaan dengan label yang sama.
+var (
+	poolMonitorsMu sync.Mutex
+	poolMonitors   = map[string]int{}
+)
+
+// PoolMonitor membuat monitor connection pool driver MongoDB yang mengisi metrik pool dengan label pool.
+// Setiap monitor mengingat kontribusinya sendiri, sehingga penutupan satu client hanya mengurangi koneksinya
+// sendiri dan series baru dihapus setelah client terakhir dengan label tersebut ditutup.
+func PoolMonitor(pool string) *event.PoolMonitor {
+	poolMonitorsMu.Lock()
+	poolMonitors[pool]++
+	poolMonitorsMu.Unlock()
+
+	var (
+		mu          sync.Mutex
+		open, inUse int
+		closed      bool
+	)
+	return &event.PoolMonitor{
+		Event: func(e *event.PoolEvent) {
+			mu.Lock()
+			defer mu.Unlock()
+			// Kontribusi client yang sudah ditutup sudah dikeluarkan dari gauge
+			if closed {
+				return
+			}
+			switch e.Type {
+			case event.ConnectionCreated:
+				open++
+				poolOpen.WithLabelValues(pool).Inc()
+			case event.ConnectionClosed:
+				open--
+				poolOpen.WithLabelValues(pool).Dec()
+			case event.GetSucceeded:
+				inUse++
+				poolInUse.WithLabelValues(pool).Inc()
+			case event.ConnectionReturned:
+				inUse--
+				poolInUse.WithLabelValues(pool).Dec()
+			case event.GetFailed:
+				poolCheckoutFailures.WithLabelValues(pool).Inc()
+			case event.PoolClosedEvent:
+				closed = true
+				if pool == PlatformPool {
+					return
+				}
+				poolMonitorsMu.Lock()
+				defer poolMonitorsMu.Unlock()
+				poolMonitors[pool]--
+				if poolMonitors[pool] > 0 {
+					// Client lain dengan label yang sama masih hidup: keluarkan sisa koneksi client ini saja
+					poolOpen.WithLabelValues(pool).Sub(float64(open))
+					poolInUse.WithLabelValues(pool).Sub(float64(inUse))
+					return
+				}
+				// Client proyek yang sudah ditutup tidak perlu dilaporkan lagi
+				delete(poolMonitors, pool)
+				poolOpen.DeleteLabelValues(pool)
+				poolInUse.DeleteLabelValues(pool)
+				poolCheckoutFailures.DeleteLabelValues(pool)
+			}
+		},
+	}
+}
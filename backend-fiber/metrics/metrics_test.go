@@ -0,0 +1,44 @@
+// file: metrics/metrics_test.go
+package metrics
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"go.mongodb.org/mongo-driver/event"
+)
+
+func sendPoolEvents(monitor *event.PoolMonitor, types ...string) {
+	for _, t := range types {
+		monitor.Event(&event.PoolEvent{Type: t})
+	}
+}
+
+func TestPoolMonitorRetiredClientKeepsLiveSeries(t *testing.T) {
+	const pool = "project-rotated"
+	retired := PoolMonitor(pool)
+	live := PoolMonitor(pool)
+
+	sendPoolEvents(retired, event.ConnectionCreated, event.ConnectionCreated, event.GetSucceeded)
+	sendPoolEvents(live, event.ConnectionCreated, event.GetSucceeded)
+
+	// Client lama ditutup saat satu koneksinya masih dipinjam; event setelah itu diabaikan
+	sendPoolEvents(retired, event.ConnectionClosed, event.PoolClosedEvent, event.ConnectionReturned, event.ConnectionClosed)
+	if got := testutil.ToFloat64(poolOpen.WithLabelValues(pool)); got != 1 {
+		t.Errorf("open = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(poolInUse.WithLabelValues(pool)); got != 1 {
+		t.Errorf("in use = %v, want 1", got)
+	}
+
+	sendPoolEvents(live, event.ConnectionReturned)
+	if got := testutil.ToFloat64(poolInUse.WithLabelValues(pool)); got != 0 {
+		t.Errorf("in use after return = %v, want 0", got)
+	}
+
+	// Client terakhir ditutup: series dihapus
+	sendPoolEvents(live, event.ConnectionClosed, event.PoolClosedEvent)
+	if n := testutil.CollectAndCount(poolOpen, "mongo_pool_connections_open"); n != 0 {
+		t.Errorf("open series left after last close = %d, want 0", n)
+	}
+}
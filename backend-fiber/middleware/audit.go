@@ -0,0 +1,123 @@
+// file: middleware/audit.go
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/fiber-mongo/starter-kit/apierror" // Sesuaikan nama modul
+	"github.com/fiber-mongo/starter-kit/database" // Sesuaikan nama modul
+	"github.com/fiber-mongo/starter-kit/logging"  // Sesuaikan nama modul
+	"github.com/fiber-mongo/starter-kit/models"   // Sesuaikan nama modul
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Handler bisa mengisi Locals ini jika ID proyek/target baru diketahui di dalam handler (misalnya saat create)
+const (
+	AuditProjectIDKey = "auditProjectId"
+	AuditTargetIDKey  = "auditTargetId"
+)
+
+// Middleware untuk mencatat aksi manajemen ke audit log. Dipasang per rute, misalnya:
+// api.Delete("/projects/:id", middleware.Audit("project.delete"), controllers.DeleteProject)
+// Nama aksi "<tipe>.<kata kerja>" menentukan tipe target.
+// Actor diambil dari header X-User-ID tanpa verifikasi dan dicatat dengan actorVerified=false.
+func Audit(action string) fiber.Handler {
+	targetType := action
+	if i := strings.Index(action, "."); i > 0 {
+		targetType = action[:i]
+	}
+
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+		err := c.Next()
+
+		statusCode := c.Response().StatusCode()
+		if err != nil {
+			statusCode = apierror.Status(err)
+		}
+
+		entry := models.AuditLog{
+			Timestamp: start,
+			Actor:     c.Get("X-User-ID"), // Tidak diverifikasi; ActorVerified tetap false
+			Action:    action,
+			Target:    models.AuditTarget{Type: targetType, ID: auditTargetID(c, targetType)},
+			Request: models.AuditRequest{
+				Method:    c.Method(),
+				Path:      c.Path(),
+				IP:        c.IP(),
+				UserAgent: c.Get(fiber.HeaderUserAgent),
+				RequestID: logging.RequestID(c),
+			},
+			Outcome:    models.AuditSuccess,
+			StatusCode: statusCode,
+			DurationMs: time.Since(start).Milliseconds(),
+		}
+		if entry.Actor == "" {
+			entry.Actor = "anonymous"
+		}
+		if projectID, ok := auditProjectID(c); ok {
+			entry.ProjectID = &projectID
+		}
+		if statusCode >= fiber.StatusBadRequest {
+			entry.Outcome = models.AuditFailure
+			entry.Error = responseError(c, err)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if _, insertErr := database.GetCollection("audit_logs").InsertOne(ctx, entry); insertErr != nil {
+			slog.ErrorContext(c.UserContext(), "audit: failed to record entry", "action", action, "error", insertErr)
+		}
+
+		return err
+	}
+}
+
+func auditProjectID(c *fiber.Ctx) (primitive.ObjectID, bool) {
+	if id, ok := c.Locals(AuditProjectIDKey).(primitive.ObjectID); ok {
+		return id, true
+	}
+	id, err := primitive.ObjectIDFromHex(c.Params("id"))
+	return id, err == nil
+}
+
+// ID target: dari Locals, dari parameter rute sesuai tipenya, atau ID proyek
+func auditTargetID(c *fiber.Ctx, targetType string) string {
+	if id, ok := c.Locals(AuditTargetIDKey).(string); ok && id != "" {
+		return id
+	}
+	switch targetType {
+	case "collection":
+		return c.Params("collName")
+	case "webhook":
+		return c.Params("webhookId")
+	case "hook":
+		return c.Params("hookId")
+	}
+	if id, ok := auditProjectID(c); ok {
+		return id.Hex()
+	}
+	return ""
+}
+
+// Ambil pesan error dari respons JSON {"error": "..."} yang dikirim handler.
+// Error yang bukan *apierror.Error dicatat sebagai "Internal server error" (sama seperti yang diterima client),
+// supaya detail internal tidak tersimpan di audit log.
+func responseError(c *fiber.Ctx, err error) string {
+	if err != nil {
+		return apierror.From(err).Message
+	}
+	var body struct {
+		Error string `json:"error"`
+	}
+	if json.Unmarshal(c.Response().Body(), &body) == nil {
+		return body.Error
+	}
+	return ""
+}
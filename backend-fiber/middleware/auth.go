@@ -3,33 +3,39 @@ package middleware
 
 import (
 	"context"
-	"fmt"
+	"crypto/sha256"
+	"encoding/hex"
 	"time"
 
-	"github.com/fiber-mongo/starter-kit/database" // Sesuaikan nama modul
-	"github.com/fiber-mongo/starter-kit/models"   // Sesuaikan nama modul
+	"github.com/fiber-mongo/starter-kit/apierror" // Sesuaikan nama modul
+	"github.com/fiber-mongo/starter-kit/services" // Sesuaikan nama modul
 
 	"github.com/gofiber/fiber/v2"
-	"go.mongodb.org/mongo-driver/bson"
 )
 
 func AuthMiddleware(c *fiber.Ctx) error {
 	clientKey := c.Get("X-API-Key")
 
 	if clientKey == "" {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized: Missing API Key"})
+		return apierror.New(fiber.StatusUnauthorized, apierror.CodeUnauthorized, "Unauthorized: Missing API Key")
 	}
 
-	projectCollection := database.GetCollection("projects")
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
 	defer cancel()
 
-	var projectDoc models.Project
-	err := projectCollection.FindOne(ctx, bson.M{"apiKey": clientKey}).Decode(&projectDoc)
+	projectDoc, err := services.Projects().GetByAPIKey(ctx, clientKey)
 	if err != nil {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized: Invalid API Key"})
+		return err
 	}
 
+	// API Key hanya berlaku untuk proyeknya sendiri
+	if projectID := c.Params("projectId"); projectID != "" && projectID != projectDoc.ID.Hex() {
+		return apierror.New(fiber.StatusForbidden, apierror.CodeForbidden, "Forbidden: API Key does not belong to this project")
+	}
+
+	// Simpan proyek agar handler tidak perlu mencarinya lagi
+	c.Locals("project", projectDoc)
+
 	// PENGECEKAN BARU: Pastikan collection yang diakses sudah diaktifkan
 	collectionName := c.Params("collectionName")
 	
@@ -47,10 +53,35 @@ func AuthMiddleware(c *fiber.Ctx) error {
 	}
 	
 	if !isAllowed {
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
-			"error": fmt.Sprintf("Access to collection '%s' is not enabled. Please enable it in your project settings.", collectionName),
-		})
+		return apierror.Newf(fiber.StatusForbidden, apierror.CodeCollectionNotEnabled,
+			"Access to collection '%s' is not enabled. Please enable it in your project settings.", collectionName)
 	}
 
 	return c.Next()
-}
\ No newline at end of file
+}
+
+// Middleware untuk endpoint realtime: EventSource dan WebSocket di browser tidak bisa mengirim header custom,
+// jadi API Key boleh dikirim lewat query ?apiKey=... dan disalin ke header X-API-Key.
+// Parameter apiKey lalu dihapus dari URI request supaya tidak ikut tercatat di log atau trace.
+func APIKeyFromQuery(c *fiber.Ctx) error {
+	uri := c.Request().URI()
+	args := uri.QueryArgs()
+	if !args.Has("apiKey") {
+		return c.Next()
+	}
+	if c.Get("X-API-Key") == "" {
+		if apiKey := string(args.Peek("apiKey")); apiKey != "" {
+			c.Request().Header.Set("X-API-Key", apiKey)
+		}
+	}
+	args.Del("apiKey")
+	c.Request().Header.SetRequestURIBytes(uri.RequestURI())
+	return c.Next()
+}
+
+// APIKeyID mengembalikan potongan hash API Key dari request, dipakai sebagai identitas key
+// (rate limit, statistik, createdBy) tanpa menyimpan API Key-nya sendiri
+func APIKeyID(c *fiber.Ctx) string {
+	sum := sha256.Sum256([]byte(c.Get("X-API-Key")))
+	return hex.EncodeToString(sum[:6])
+}
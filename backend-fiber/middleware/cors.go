@@ -0,0 +1,92 @@
+// file: middleware/cors.go
+package middleware
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fiber-mongo/starter-kit/config"     // Sesuaikan nama modul
+	"github.com/fiber-mongo/starter-kit/corspolicy" // Sesuaikan nama modul
+	"github.com/fiber-mongo/starter-kit/models"     // Sesuaikan nama modul
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Prefix rute yang memakai CORS per proyek (Data API dengan API Key); rute lain memakai CORS manajemen
+var projectCORSPrefixes = []string{"/api/v1/data/", "/api/v1/graphql/", "/api/v1/realtime/"}
+
+// UsesProjectCORS dipakai sebagai Next pada CORS manajemen supaya rute Data API tidak ditangani dua kali
+func UsesProjectCORS(c *fiber.Ctx) bool {
+	for _, prefix := range projectCORSPrefixes {
+		if strings.HasPrefix(c.Path(), prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Middleware CORS per proyek untuk Data API. Dipasang sebelum AuthMiddleware karena preflight
+// (OPTIONS) dari browser tidak membawa API Key; proyek diambil dari parameter :projectId.
+// Origin dashboard (konfigurasi server) selalu diizinkan. Origin yang tidak diizinkan tidak
+// mendapat header CORS sehingga browser memblokir respons.
+func ProjectCORS(c *fiber.Ctx) error {
+	preflight := c.Method() == fiber.MethodOptions
+	origin := c.Get(fiber.HeaderOrigin)
+	if origin == "" {
+		if preflight {
+			return c.SendStatus(fiber.StatusNoContent)
+		}
+		return c.Next()
+	}
+	c.Vary(fiber.HeaderOrigin)
+
+	settings, allowed := resolveProjectCORS(c, origin)
+	if !allowed {
+		if preflight {
+			return c.SendStatus(fiber.StatusNoContent)
+		}
+		return c.Next()
+	}
+	c.Set(fiber.HeaderAccessControlAllowOrigin, origin)
+
+	if !preflight {
+		c.Set(fiber.HeaderAccessControlExposeHeaders, corspolicy.ExposeHeaders)
+		return c.Next()
+	}
+
+	c.Vary(fiber.HeaderAccessControlRequestMethod, fiber.HeaderAccessControlRequestHeaders)
+	if method := c.Get(fiber.HeaderAccessControlRequestMethod); method != "" && !corspolicy.AllowsMethod(settings, method) {
+		return c.SendStatus(fiber.StatusNoContent)
+	}
+	methods := append([]string{fiber.MethodOptions}, settings.AllowMethods...)
+	c.Set(fiber.HeaderAccessControlAllowMethods, strings.Join(methods, ", "))
+	c.Set(fiber.HeaderAccessControlAllowHeaders, strings.Join(settings.AllowHeaders, ", "))
+	if settings.MaxAge > 0 {
+		c.Set(fiber.HeaderAccessControlMaxAge, strconv.Itoa(settings.MaxAge))
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// Fungsi helper untuk membaca settings proyek dan mengecek origin.
+// Jika settings tidak bisa dibaca, origin tidak diizinkan (fail closed).
+func resolveProjectCORS(c *fiber.Ctx, origin string) (models.CORSSettings, bool) {
+	projectID, err := primitive.ObjectIDFromHex(c.Params("projectId"))
+	if err != nil {
+		return models.CORSSettings{}, false
+	}
+
+	ctx, cancel := context.WithTimeout(c.UserContext(), 5*time.Second)
+	defer cancel()
+
+	settings, err := corspolicy.Load(ctx, projectID)
+	if err != nil {
+		slog.ErrorContext(c.UserContext(), "cors: failed to load settings", "projectId", projectID.Hex(), "error", err)
+		return settings, false
+	}
+	allowed := corspolicy.AllowsOrigin(config.Get().CORSOrigins, origin) || corspolicy.AllowsOrigin(settings.AllowOrigins, origin)
+	return settings, allowed
+}
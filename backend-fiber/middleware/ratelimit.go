@@ -0,0 +1,94 @@
+// file: middleware/ratelimit.go
+package middleware
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/fiber-mongo/starter-kit/apierror"  // Sesuaikan nama modul
+	"github.com/fiber-mongo/starter-kit/models"    // Sesuaikan nama modul
+	"github.com/fiber-mongo/starter-kit/ratelimit" // Sesuaikan nama modul
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Locals tempat RateLimit menyimpan settings proyek, dipakai handler untuk mengecek kuota storage
+const RateLimitSettingsKey = "rateLimitSettings"
+
+// Middleware rate limit dan kuota untuk Data API. Dipasang setelah AuthMiddleware (butuh Locals "project").
+// Setiap request mengambil satu token dari bucket API Key dan bucket proyek, lalu menambah counter
+// kuota request bulanan (tersimpan di database platform). Jika salah satu habis, request ditolak dengan 429.
+func RateLimit(c *fiber.Ctx) error {
+	project, ok := c.Locals("project").(models.Project)
+	if !ok {
+		return c.Next()
+	}
+
+	ctx, cancel := context.WithTimeout(c.UserContext(), 5*time.Second)
+	defer cancel()
+
+	// Jika settings atau store bermasalah, request tetap dilayani (fail open)
+	settings, err := ratelimit.LoadSettings(ctx, project.ID)
+	if err != nil {
+		slog.ErrorContext(c.UserContext(), "ratelimit: failed to load settings", "projectId", project.ID.Hex(), "error", err)
+		return c.Next()
+	}
+	c.Locals(RateLimitSettingsKey, settings)
+
+	store := ratelimit.DefaultStore()
+	now := time.Now()
+
+	keyDecision, err := store.Allow(ctx, ratelimit.APIKeyKey(APIKeyID(c)), ratelimit.KeyLimit(settings), now)
+	if err != nil {
+		slog.ErrorContext(c.UserContext(), "ratelimit: store error", "error", err)
+		return c.Next()
+	}
+	projectDecision := ratelimit.Decision{Allowed: true}
+	if keyDecision.Allowed {
+		projectDecision, err = store.Allow(ctx, ratelimit.ProjectKey(project.ID), ratelimit.ProjectLimit(settings), now)
+		if err != nil {
+			slog.ErrorContext(c.UserContext(), "ratelimit: store error", "error", err)
+			return c.Next()
+		}
+	}
+
+	// Header RateLimit-* menunjukkan bucket yang paling ketat
+	decision := keyDecision
+	if !projectDecision.Allowed || (projectDecision.Limit > 0 && (decision.Limit == 0 || projectDecision.Remaining < decision.Remaining)) {
+		decision = projectDecision
+	}
+	if decision.Limit > 0 {
+		c.Set("RateLimit-Limit", strconv.Itoa(decision.Limit))
+		c.Set("RateLimit-Remaining", strconv.Itoa(decision.Remaining))
+		c.Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(decision.Reset)))
+	}
+	if !decision.Allowed {
+		c.Set(fiber.HeaderRetryAfter, strconv.Itoa(ceilSeconds(decision.RetryAfter)))
+		return apierror.New(fiber.StatusTooManyRequests, apierror.CodeRateLimited, "Rate limit exceeded, please retry later")
+	}
+
+	if settings.MonthlyRequests > 0 {
+		used, allowed, err := ratelimit.ConsumeRequest(ctx, project.ID, settings.MonthlyRequests, now)
+		if err != nil {
+			slog.ErrorContext(c.UserContext(), "ratelimit: quota error", "error", err)
+			return c.Next()
+		}
+		c.Set("X-Quota-Limit", strconv.FormatInt(settings.MonthlyRequests, 10))
+		c.Set("X-Quota-Remaining", strconv.FormatInt(max(settings.MonthlyRequests-used, 0), 10))
+		if !allowed {
+			_, periodEnd := ratelimit.QuotaPeriod(now)
+			c.Set(fiber.HeaderRetryAfter, strconv.Itoa(ceilSeconds(periodEnd.Sub(now))))
+			return apierror.New(fiber.StatusTooManyRequests, apierror.CodeQuotaExceeded, "Monthly request quota exceeded")
+		}
+	}
+
+	return c.Next()
+}
+
+// Fungsi helper untuk membulatkan durasi ke atas dalam detik (untuk header Retry-After/RateLimit-Reset)
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
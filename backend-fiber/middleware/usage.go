@@ -0,0 +1,51 @@
+// file: middleware/usage.go
+package middleware
+
+import (
+	"time"
+
+	"github.com/fiber-mongo/starter-kit/apierror" // Sesuaikan nama modul
+	"github.com/fiber-mongo/starter-kit/models"   // Sesuaikan nama modul
+	"github.com/fiber-mongo/starter-kit/usage"    // Sesuaikan nama modul
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Middleware untuk mencatat statistik pemakaian Data API (jumlah request, error, latency, byte).
+// Dipasang setelah AuthMiddleware (butuh Locals "project") dan sebelum RateLimit supaya request
+// yang ditolak dengan 429 ikut tercatat.
+func Usage(c *fiber.Ctx) error {
+	project, ok := c.Locals("project").(models.Project)
+	if !ok {
+		return c.Next()
+	}
+
+	start := time.Now()
+	err := c.Next()
+
+	statusCode := c.Response().StatusCode()
+	if err != nil {
+		statusCode = apierror.Status(err)
+	}
+
+	// Body stream (SSE) tidak boleh dibaca di sini karena akan menunggu stream selesai
+	bytesOut := int64(c.Response().Header.ContentLength())
+	if !c.Response().IsBodyStream() {
+		bytesOut = int64(len(c.Response().Body()))
+	}
+	if bytesOut < 0 {
+		bytesOut = 0
+	}
+
+	usage.Record(usage.Sample{
+		ProjectID:  project.ID,
+		Collection: c.Params("collectionName"),
+		APIKeyID:   APIKeyID(c),
+		Status:     statusCode,
+		Latency:    time.Since(start),
+		BytesIn:    int64(len(c.Request().Body())),
+		BytesOut:   bytesOut,
+		Time:       start,
+	})
+	return err
+}
@@ -0,0 +1,47 @@
+// file: models/audit_model.go
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Hasil sebuah aksi yang diaudit
+const (
+	AuditSuccess = "success"
+	AuditFailure = "failure"
+)
+
+// Struct untuk satu entri audit log aksi manajemen (disimpan di koleksi "audit_logs", hanya ditambah, tidak pernah diubah).
+// Actor TIDAK diverifikasi: platform belum punya otentikasi user, jadi nilainya adalah klaim client lewat header
+// X-User-ID dan bisa diisi apa saja. ActorVerified selalu false sampai ada otentikasi; jangan jadikan Actor bukti pelaku.
+type AuditLog struct {
+	ID            primitive.ObjectID  `json:"id" bson:"_id,omitempty"`
+	Timestamp     time.Time           `json:"timestamp" bson:"timestamp"`
+	Actor         string              `json:"actor" bson:"actor"`                 // Header X-User-ID apa adanya, atau "anonymous"
+	ActorVerified bool                `json:"actorVerified" bson:"actorVerified"` // Selalu false (lihat komentar di atas)
+	Action        string              `json:"action" bson:"action"`               // Misalnya "project.create", "collection.delete"
+	ProjectID     *primitive.ObjectID `json:"projectId,omitempty" bson:"projectId,omitempty"`
+	Target        AuditTarget         `json:"target" bson:"target"`
+	Request       AuditRequest        `json:"request" bson:"request"`
+	Outcome       string              `json:"outcome" bson:"outcome"` // "success" atau "failure"
+	StatusCode    int                 `json:"statusCode" bson:"statusCode"`
+	Error         string              `json:"error,omitempty" bson:"error,omitempty"`
+	DurationMs    int64               `json:"durationMs" bson:"durationMs"`
+}
+
+// Objek yang dikenai aksi
+type AuditTarget struct {
+	Type string `json:"type" bson:"type"` // "project", "collection", "webhook", "hook"
+	ID   string `json:"id,omitempty" bson:"id,omitempty"`
+}
+
+// Metadata request HTTP (body tidak disimpan karena bisa berisi password database)
+type AuditRequest struct {
+	Method    string `json:"method" bson:"method"`
+	Path      string `json:"path" bson:"path"`
+	IP        string `json:"ip" bson:"ip"`
+	UserAgent string `json:"userAgent,omitempty" bson:"userAgent,omitempty"`
+	RequestID string `json:"requestId,omitempty" bson:"requestId,omitempty"`
+}
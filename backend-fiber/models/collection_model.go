@@ -0,0 +1,60 @@
+// file: models/collection_model.go
+package models
+
+import "go.mongodb.org/mongo-driver/bson"
+
+// Struct untuk informasi satu koleksi di database user (dipakai oleh ListCollections)
+type CollectionInfo struct {
+	Name            string  `json:"name"`
+	Type            string  `json:"type"` // "collection", "view" atau "timeseries"
+	Active          bool    `json:"active"`
+	DocumentCount   int64   `json:"documentCount"`
+	DataSize        int64   `json:"dataSize"`
+	StorageSize     int64   `json:"storageSize"`
+	AvgDocumentSize float64 `json:"avgDocumentSize"`
+	IndexCount      int64   `json:"indexCount"`
+	TotalIndexSize  int64   `json:"totalIndexSize"`
+
+	// Opsi koleksi
+	Schema           bson.M `json:"schema"` // Isi $jsonSchema dari validator, nil jika tidak ada
+	ValidationLevel  string `json:"validationLevel,omitempty"`
+	ValidationAction string `json:"validationAction,omitempty"`
+	Capped           bool   `json:"capped"`
+	CappedSize       int64  `json:"cappedSize,omitempty"`
+	CappedMax        int64  `json:"cappedMax,omitempty"`
+	Timeseries       bson.M `json:"timeseries,omitempty"`
+}
+
+// Struct untuk satu field dalam format sederhana schema builder (name/type/required)
+type SchemaField struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Required bool   `json:"required"`
+}
+
+// Struct respons untuk GET /projects/{id}/collections/{collName}/schema
+type CollectionSchema struct {
+	CollectionName   string        `json:"collectionName"`
+	Schema           bson.M        `json:"schema"`
+	Fields           []SchemaField `json:"fields"`
+	Editable         bool          `json:"editable"` // false jika schema tidak bisa diedit sebagai daftar field sederhana
+	ValidationLevel  string        `json:"validationLevel"`
+	ValidationAction string        `json:"validationAction"`
+}
+
+// Struct untuk statistik satu field hasil inferensi schema
+type InferredField struct {
+	Path      string             `json:"path"`      // Notasi titik, "[]" untuk elemen array (misal "tags[]", "alamat.kota")
+	Count     int                `json:"count"`     // Berapa kali field muncul di sampel
+	Frequency float64            `json:"frequency"` // Persentase kemunculan relatif terhadap induknya
+	Types     map[string]float64 `json:"types"`     // Persentase tiap bsonType
+}
+
+// Struct respons untuk GET /projects/{id}/collections/{collName}/schema/infer
+type InferredSchema struct {
+	CollectionName   string          `json:"collectionName"`
+	SampleSize       int             `json:"sampleSize"`
+	SampledDocuments int             `json:"sampledDocuments"`
+	Schema           bson.M          `json:"schema"`
+	Fields           []InferredField `json:"fields"`
+}
@@ -0,0 +1,50 @@
+// file: models/collection_settings_model.go
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Nama field yang dikelola otomatis di dokumen user
+const (
+	FieldCreatedAt = "createdAt"
+	FieldUpdatedAt = "updatedAt"
+	FieldCreatedBy = "createdBy"
+	FieldDeletedAt = "deletedAt"
+	FieldDeletedBy = "deletedBy"
+)
+
+// Struct untuk opsi per koleksi (disimpan di koleksi "collection_settings").
+// Koleksi tanpa dokumen settings memakai nilai default (semua opsi mati).
+type CollectionSettings struct {
+	ID             primitive.ObjectID `json:"-" bson:"_id,omitempty"`
+	ProjectID      primitive.ObjectID `json:"projectId" bson:"projectId"`
+	CollectionName string             `json:"collectionName" bson:"collectionName"`
+	Timestamps     bool               `json:"timestamps" bson:"timestamps"` // Isi createdAt/updatedAt/createdBy otomatis
+	SoftDelete     bool               `json:"softDelete" bson:"softDelete"` // DELETE hanya mengisi deletedAt
+	Revisions      bool               `json:"revisions" bson:"revisions"`   // Simpan riwayat revisi di koleksi "<nama>__revisions"
+	UpdatedAt      time.Time          `json:"updatedAt" bson:"updatedAt"`
+}
+
+// Struct untuk menerima input PUT settings (field yang tidak dikirim tidak diubah)
+type CollectionSettingsInput struct {
+	Timestamps *bool `json:"timestamps"`
+	SoftDelete *bool `json:"softDelete"`
+	Revisions  *bool `json:"revisions"`
+}
+
+// Identitas pembuat perubahan pada Data API: API Key yang dipakai dan (opsional) ID user dari header X-User-ID
+type Actor struct {
+	UserID   string `json:"userId,omitempty" bson:"userId,omitempty"`
+	APIKeyID string `json:"apiKeyId" bson:"apiKeyId"` // Potongan hash API Key, bukan API Key-nya
+}
+
+// String dipakai untuk field createdBy/deletedBy
+func (a Actor) String() string {
+	if a.UserID != "" {
+		return a.UserID
+	}
+	return "apikey:" + a.APIKeyID
+}
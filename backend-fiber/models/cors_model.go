@@ -0,0 +1,29 @@
+// file: models/cors_model.go
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Struct untuk aturan CORS Data API per proyek (disimpan di koleksi "cors_settings").
+// Origin dashboard dari konfigurasi server selalu diizinkan; proyek tanpa dokumen ini
+// tidak mengizinkan origin lain.
+type CORSSettings struct {
+	ID           primitive.ObjectID `json:"-" bson:"_id,omitempty"`
+	ProjectID    primitive.ObjectID `json:"projectId" bson:"projectId"`
+	AllowOrigins []string           `json:"allowOrigins" bson:"allowOrigins"` // "https://app.example.com", "https://*.example.com" atau "*"
+	AllowMethods []string           `json:"allowMethods" bson:"allowMethods"`
+	AllowHeaders []string           `json:"allowHeaders" bson:"allowHeaders"`
+	MaxAge       int                `json:"maxAge" bson:"maxAge"` // Lama (detik) browser boleh menyimpan hasil preflight
+	UpdatedAt    time.Time          `json:"updatedAt" bson:"updatedAt"`
+}
+
+// Struct untuk menerima input PUT CORS (field yang tidak dikirim tidak diubah)
+type CORSSettingsInput struct {
+	AllowOrigins *[]string `json:"allowOrigins"`
+	AllowMethods *[]string `json:"allowMethods"`
+	AllowHeaders *[]string `json:"allowHeaders"`
+	MaxAge       *int      `json:"maxAge"`
+}
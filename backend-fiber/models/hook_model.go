@@ -0,0 +1,57 @@
+// file: models/hook_model.go
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Kapan hook dijalankan relatif terhadap penulisan dokumen
+const (
+	HookBefore = "before"
+	HookAfter  = "after"
+)
+
+// Jenis aksi hook
+const (
+	HookActionSet    = "set"    // Isi field dengan hasil expression
+	HookActionUnset  = "unset"  // Hapus field
+	HookActionReject = "reject" // Batalkan penulisan dengan pesan error (hanya hook "before")
+)
+
+// Struct untuk hook/trigger sebuah koleksi (disimpan di koleksi "collection_hooks").
+// Condition dan Expression memakai bahasa expression expr-lang (https://expr-lang.org), dengan variabel:
+// doc (dokumen setelah perubahan), changes (field yang ditulis), before (dokumen lama), event dan collection.
+type CollectionHook struct {
+	ID             primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	ProjectID      primitive.ObjectID `json:"projectId" bson:"projectId"`
+	CollectionName string             `json:"collectionName" bson:"collectionName"`
+	Name           string             `json:"name" bson:"name"`
+	Trigger        string             `json:"trigger" bson:"trigger"` // "before" atau "after"
+	Event          string             `json:"event" bson:"event"`     // "create", "update" atau "delete"
+	Condition      string             `json:"condition,omitempty" bson:"condition,omitempty"`
+	Actions        []HookAction       `json:"actions" bson:"actions"`
+	Order          int                `json:"order" bson:"order"` // Hook dijalankan dari Order terkecil
+	Active         bool               `json:"active" bson:"active"`
+	CreatedAt      time.Time          `json:"createdAt" bson:"createdAt"`
+}
+
+// Satu aksi yang dijalankan jika Condition terpenuhi
+type HookAction struct {
+	Type       string `json:"type" bson:"type"`
+	Field      string `json:"field,omitempty" bson:"field,omitempty"`
+	Expression string `json:"expression,omitempty" bson:"expression,omitempty"` // Untuk "set"
+	Message    string `json:"message,omitempty" bson:"message,omitempty"`       // Untuk "reject"
+}
+
+// Struct untuk menerima input create/update hook
+type HookInput struct {
+	Name      string       `json:"name"`
+	Trigger   string       `json:"trigger"`
+	Event     string       `json:"event"`
+	Condition string       `json:"condition"`
+	Actions   []HookAction `json:"actions"`
+	Order     int          `json:"order"`
+	Active    *bool        `json:"active"`
+}
@@ -0,0 +1,33 @@
+// file: models/job_model.go
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Status sebuah background job
+const (
+	JobQueued    = "queued"
+	JobRunning   = "running"
+	JobCompleted = "completed"
+	JobFailed    = "failed"
+)
+
+// Struct untuk background job (disimpan di koleksi "jobs")
+type Job struct {
+	ID         primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	ProjectID  primitive.ObjectID `json:"projectId" bson:"projectId"`
+	Type       string             `json:"type" bson:"type"`
+	Params     bson.M             `json:"params,omitempty" bson:"params,omitempty"`
+	Status     string             `json:"status" bson:"status"`
+	Total      int64              `json:"total" bson:"total"`
+	Processed  int64              `json:"processed" bson:"processed"`
+	Result     bson.M             `json:"result,omitempty" bson:"result,omitempty"`
+	Error      string             `json:"error,omitempty" bson:"error,omitempty"`
+	CreatedAt  time.Time          `json:"createdAt" bson:"createdAt"`
+	StartedAt  *time.Time         `json:"startedAt,omitempty" bson:"startedAt,omitempty"`
+	FinishedAt *time.Time         `json:"finishedAt,omitempty" bson:"finishedAt,omitempty"`
+}
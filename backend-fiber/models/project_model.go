@@ -2,6 +2,7 @@
 package models
 
 import (
+	"encoding/json"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -21,23 +22,60 @@ type Project struct {
 	DBHost     string `json:"dbHost" bson:"dbHost"`
 	DBPort     string `json:"dbPort" bson:"dbPort"`
 	DBUser     string `json:"dbUser" bson:"dbUser"`
-	DBPassword string `json:"-" bson:"dbPassword"` // Jangan pernah kirim password kembali ke client
+	DBPassword string `json:"-" bson:"dbPassword"` // Terenkripsi (proyek lama: teks biasa); jangan pernah kirim ke client
 	DBName     string `json:"dbName" bson:"dbName"`
 	AuthSource string `json:"authSource" bson:"authSource"`
+
+	// Opsi koneksi lanjutan. Jika ConnectionString diisi, field host/port/user/password di atas tidak dipakai.
+	ConnectionString string   `json:"-" bson:"connectionString,omitempty"`        // Terenkripsi, karena berisi kredensial
+	DBHosts          []string `json:"dbHosts,omitempty" bson:"dbHosts,omitempty"` // Host tambahan (host:port) untuk replica set
+	ReplicaSet       string   `json:"replicaSet,omitempty" bson:"replicaSet,omitempty"`
+	ReadPreference   string   `json:"readPreference,omitempty" bson:"readPreference,omitempty"`
+	AuthMechanism    string   `json:"authMechanism,omitempty" bson:"authMechanism,omitempty"`
+	TLS              bool     `json:"tls" bson:"tls,omitempty"`
+	TLSCACert        string   `json:"-" bson:"tlsCACert,omitempty"`     // PEM CA, terenkripsi
+	TLSClientCert    string   `json:"-" bson:"tlsClientCert,omitempty"` // PEM sertifikat + private key client, terenkripsi
+}
+
+// MarshalJSON menambahkan penanda apakah connection string dan sertifikat sudah tersimpan,
+// tanpa pernah mengirim isinya ke client
+func (p Project) MarshalJSON() ([]byte, error) {
+	type plain Project
+	return json.Marshal(struct {
+		plain
+		HasConnectionString bool `json:"hasConnectionString"`
+		HasTLSCACert        bool `json:"hasTlsCACert"`
+		HasTLSClientCert    bool `json:"hasTlsClientCert"`
+	}{plain(p), p.ConnectionString != "", p.TLSCACert != "", p.TLSClientCert != ""})
 }
 
-// Struct baru untuk menerima password dari request body
+// Struct baru untuk menerima password dari request body.
+// Tag form dipakai saat sertifikat diunggah sebagai file (multipart/form-data).
 type ProjectInput struct {
-	Name       string `json:"name"`
-	DBHost     string `json:"dbHost"`
-	DBPort     string `json:"dbPort"`
-	DBUser     string `json:"dbUser"`
-	DBPassword string `json:"dbPassword"`
-	DBName     string `json:"dbName"`
-	AuthSource string `json:"authSource"`
+	Name       string `json:"name" form:"name"`
+	DBHost     string `json:"dbHost" form:"dbHost"`
+	DBPort     string `json:"dbPort" form:"dbPort"`
+	DBUser     string `json:"dbUser" form:"dbUser"`
+	DBPassword string `json:"dbPassword" form:"dbPassword"`
+	DBName     string `json:"dbName" form:"dbName"`
+	AuthSource string `json:"authSource" form:"authSource"`
+
+	ConnectionString string   `json:"connectionString" form:"connectionString"` // mongodb:// atau mongodb+srv://
+	DBHosts          []string `json:"dbHosts" form:"dbHosts"`
+	ReplicaSet       string   `json:"replicaSet" form:"replicaSet"`
+	ReadPreference   string   `json:"readPreference" form:"readPreference"`
+	AuthMechanism    string   `json:"authMechanism" form:"authMechanism"`
+	TLS              bool     `json:"tls" form:"tls"`
+	TLSCACert        string   `json:"tlsCACert" form:"tlsCACert"`         // PEM
+	TLSClientCert    string   `json:"tlsClientCert" form:"tlsClientCert"` // PEM sertifikat + private key
+
+	// Hanya untuk pembaruan koneksi: hapus sertifikat TLS yang tersimpan
+	RemoveTLSCertificates bool `json:"removeTlsCertificates" form:"removeTlsCertificates"`
 }
 
 type CreateCollectionInput struct {
-	CollectionName string `json:"collectionName"`
-	Schema         bson.M `json:"schema"`
+	CollectionName   string `json:"collectionName"`
+	Schema           bson.M `json:"schema"`
+	ValidationLevel  string `json:"validationLevel"`  // "off", "strict" (default) atau "moderate"
+	ValidationAction string `json:"validationAction"` // "error" (default) atau "warn"
 }
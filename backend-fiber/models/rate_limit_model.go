@@ -0,0 +1,33 @@
+// file: models/rate_limit_model.go
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Struct untuk batas request dan kuota per proyek (disimpan di koleksi "rate_limits").
+// Proyek tanpa dokumen ini memakai nilai default dari package ratelimit.
+// Rate 0 berarti tanpa batas; kuota 0 berarti tanpa kuota.
+type RateLimitSettings struct {
+	ID              primitive.ObjectID `json:"-" bson:"_id,omitempty"`
+	ProjectID       primitive.ObjectID `json:"projectId" bson:"projectId"`
+	ProjectRate     float64            `json:"projectRate" bson:"projectRate"`         // Request per detik untuk seluruh proyek
+	ProjectBurst    int                `json:"projectBurst" bson:"projectBurst"`       // Lonjakan maksimal untuk seluruh proyek
+	KeyRate         float64            `json:"keyRate" bson:"keyRate"`                 // Request per detik per API Key
+	KeyBurst        int                `json:"keyBurst" bson:"keyBurst"`               // Lonjakan maksimal per API Key
+	MonthlyRequests int64              `json:"monthlyRequests" bson:"monthlyRequests"` // Kuota request per bulan kalender (UTC)
+	StorageBytes    int64              `json:"storageBytes" bson:"storageBytes"`       // Kuota ukuran data di database user
+	UpdatedAt       time.Time          `json:"updatedAt" bson:"updatedAt"`
+}
+
+// Struct untuk menerima input PUT rate limit (field yang tidak dikirim tidak diubah)
+type RateLimitSettingsInput struct {
+	ProjectRate     *float64 `json:"projectRate"`
+	ProjectBurst    *int     `json:"projectBurst"`
+	KeyRate         *float64 `json:"keyRate"`
+	KeyBurst        *int     `json:"keyBurst"`
+	MonthlyRequests *int64   `json:"monthlyRequests"`
+	StorageBytes    *int64   `json:"storageBytes"`
+}
@@ -0,0 +1,46 @@
+// file: models/revision_model.go
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Akhiran nama koleksi bayangan yang menyimpan revisi dokumen, misalnya "products__revisions"
+const RevisionCollectionSuffix = "__revisions"
+
+// Operasi yang menghasilkan sebuah revisi. "baseline" adalah isi dokumen sebelum perubahan pertama yang tercatat.
+const (
+	RevisionBaseline = "baseline"
+	RevisionRestore  = "restore"
+	RevisionPurge    = "purge"
+)
+
+// Struct untuk satu revisi dokumen: isi dokumen setelah perubahan, siapa, kapan, dan lewat API Key mana
+type DocumentRevision struct {
+	ID         primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	DocumentID interface{}        `json:"documentId" bson:"documentId"`
+	Revision   int                `json:"revision" bson:"revision"`
+	Operation  string             `json:"operation" bson:"operation"` // "baseline", "create", "update", "delete", "restore" atau "purge"
+	Snapshot   bson.M             `json:"snapshot" bson:"snapshot"`
+	Actor      Actor              `json:"actor" bson:"actor"`
+	CreatedAt  time.Time          `json:"createdAt" bson:"createdAt"`
+}
+
+// Satu perbedaan field antara dua revisi (path memakai notasi titik)
+type FieldChange struct {
+	Path   string      `json:"path"`
+	Change string      `json:"change"` // "added", "removed" atau "changed"
+	From   interface{} `json:"from,omitempty"`
+	To     interface{} `json:"to,omitempty"`
+}
+
+// Hasil perbandingan dua revisi
+type RevisionDiff struct {
+	DocumentID interface{}   `json:"documentId"`
+	From       int           `json:"from"`
+	To         int           `json:"to"`
+	Changes    []FieldChange `json:"changes"`
+}
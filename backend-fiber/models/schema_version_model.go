@@ -0,0 +1,63 @@
+// file: models/schema_version_model.go
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Status sebuah versi schema
+const (
+	SchemaVersionPending = "pending" // Menunggu job migrasi selesai
+	SchemaVersionApplied = "applied" // Validator sudah diterapkan ke koleksi
+	SchemaVersionFailed  = "failed"  // Migrasi atau collMod gagal
+)
+
+// Struct untuk satu versi schema validator sebuah koleksi (disimpan di koleksi "schema_versions")
+type SchemaVersion struct {
+	ID               primitive.ObjectID  `json:"id" bson:"_id,omitempty"`
+	ProjectID        primitive.ObjectID  `json:"projectId" bson:"projectId"`
+	CollectionName   string              `json:"collectionName" bson:"collectionName"`
+	Version          int                 `json:"version" bson:"version"`
+	Schema           bson.M              `json:"schema" bson:"schema"`
+	ValidationLevel  string              `json:"validationLevel" bson:"validationLevel"`
+	ValidationAction string              `json:"validationAction" bson:"validationAction"`
+	Migrations       []MigrationStep     `json:"migrations" bson:"migrations"`
+	Status           string              `json:"status" bson:"status"`
+	JobID            *primitive.ObjectID `json:"jobId,omitempty" bson:"jobId,omitempty"`
+	Error            string              `json:"error,omitempty" bson:"error,omitempty"`
+	CreatedAt        time.Time           `json:"createdAt" bson:"createdAt"`
+	AppliedAt        *time.Time          `json:"appliedAt,omitempty" bson:"appliedAt,omitempty"`
+}
+
+// Struct untuk satu langkah migrasi data sebelum validator baru diterapkan.
+//
+//	{"op": "rename",  "field": "nama", "to": "name"}
+//	{"op": "default", "field": "stok", "value": 0}
+//	{"op": "convert", "field": "harga", "to": "double"}
+//	{"op": "remove",  "field": "legacyField"}
+type MigrationStep struct {
+	Op    string      `json:"op" bson:"op"`
+	Field string      `json:"field" bson:"field"`
+	To    string      `json:"to,omitempty" bson:"to,omitempty"`
+	Value interface{} `json:"value,omitempty" bson:"value,omitempty"`
+}
+
+// Struct untuk payload PUT /projects/{id}/collections/{collName}
+type UpdateCollectionInput struct {
+	Schema           bson.M          `json:"schema"`
+	ValidationLevel  string          `json:"validationLevel"`
+	ValidationAction string          `json:"validationAction"`
+	Migrations       []MigrationStep `json:"migrations"`
+	DryRun           bool            `json:"dryRun"`
+}
+
+// Struct hasil dry-run perubahan schema
+type SchemaDryRunReport struct {
+	TotalDocuments     int64         `json:"totalDocuments"`
+	ViolatingDocuments int64         `json:"violatingDocuments"`
+	SampleViolations   []interface{} `json:"sampleViolations"` // _id dari beberapa dokumen yang melanggar
+	MigrationSteps     int           `json:"migrationSteps"`
+}
@@ -0,0 +1,56 @@
+// file: models/usage_model.go
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Granularitas bucket statistik pemakaian
+const (
+	UsageHourly = "hour"
+	UsageDaily  = "day"
+)
+
+// Batas atas (ms) setiap bucket histogram latency. Request yang lebih lambat masuk bucket "inf".
+var UsageLatencyBoundsMs = []int64{5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000}
+
+// Struct untuk statistik pemakaian Data API dalam satu bucket waktu (disimpan di koleksi "usage_stats").
+// Satu dokumen per proyek/koleksi/API Key/granularitas/bucket. Sampel ukuran storage disimpan di dokumen
+// dengan apiKeyId kosong.
+type UsageStats struct {
+	ID             primitive.ObjectID `json:"-" bson:"_id,omitempty"`
+	ProjectID      primitive.ObjectID `json:"projectId" bson:"projectId"`
+	CollectionName string             `json:"collectionName" bson:"collectionName"` // Kosong untuk GraphQL
+	APIKeyID       string             `json:"apiKeyId" bson:"apiKeyId"`
+	Granularity    string             `json:"granularity" bson:"granularity"`
+	Bucket         time.Time          `json:"bucket" bson:"bucket"` // Awal jam/hari (UTC)
+	Requests       int64              `json:"requests" bson:"requests"`
+	ClientErrors   int64              `json:"clientErrors" bson:"clientErrors"` // Status 4xx
+	ServerErrors   int64              `json:"serverErrors" bson:"serverErrors"` // Status 5xx
+	BytesIn        int64              `json:"bytesIn" bson:"bytesIn"`
+	BytesOut       int64              `json:"bytesOut" bson:"bytesOut"`
+	LatencyMsSum   float64            `json:"latencyMsSum" bson:"latencyMsSum"`
+	Latency        map[string]int64   `json:"latency" bson:"latency"` // Key "le_<ms>" atau "inf"
+	StorageBytes   int64              `json:"storageBytes" bson:"storageBytes"`
+	UpdatedAt      time.Time          `json:"updatedAt" bson:"updatedAt"`
+}
+
+// Satu titik pada deret waktu yang dikirim ke dashboard
+type UsagePoint struct {
+	Bucket       *time.Time       `json:"bucket,omitempty"` // Kosong untuk total
+	Key          string           `json:"key,omitempty"`    // Nilai groupBy (nama koleksi atau API Key ID)
+	Requests     int64            `json:"requests"`
+	ClientErrors int64            `json:"clientErrors"`
+	ServerErrors int64            `json:"serverErrors"`
+	ErrorRate    float64          `json:"errorRate"`
+	BytesIn      int64            `json:"bytesIn"`
+	BytesOut     int64            `json:"bytesOut"`
+	AvgLatencyMs float64          `json:"avgLatencyMs"`
+	P50LatencyMs int64            `json:"p50LatencyMs"`
+	P95LatencyMs int64            `json:"p95LatencyMs"`
+	P99LatencyMs int64            `json:"p99LatencyMs"`
+	Latency      map[string]int64 `json:"latency"`
+	StorageBytes int64            `json:"storageBytes"`
+}
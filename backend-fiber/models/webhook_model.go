@@ -0,0 +1,69 @@
+// file: models/webhook_model.go
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Jenis event dokumen (dipakai oleh webhook dan hook koleksi)
+const (
+	DocumentEventCreate = "create"
+	DocumentEventUpdate = "update"
+	DocumentEventDelete = "delete"
+)
+
+// Status pengiriman webhook
+const (
+	DeliveryPending    = "pending"
+	DeliveryDelivering = "delivering"
+	DeliverySucceeded  = "succeeded"
+	DeliveryFailed     = "failed"
+)
+
+// Struct untuk langganan webhook sebuah proyek (disimpan di koleksi "webhooks")
+type Webhook struct {
+	ID          primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	ProjectID   primitive.ObjectID `json:"projectId" bson:"projectId"`
+	URL         string             `json:"url" bson:"url"`
+	Collections []string           `json:"collections" bson:"collections"` // Kosong = semua koleksi
+	Events      []string           `json:"events" bson:"events"`           // "create", "update", "delete"
+	Secret      string             `json:"secret,omitempty" bson:"secret"` // Hanya dikirim ke client saat webhook dibuat
+	Active      bool               `json:"active" bson:"active"`
+	CreatedAt   time.Time          `json:"createdAt" bson:"createdAt"`
+}
+
+// Struct untuk menerima input create/update webhook
+type WebhookInput struct {
+	URL         string   `json:"url"`
+	Collections []string `json:"collections"`
+	Events      []string `json:"events"`
+	Secret      string   `json:"secret"` // Opsional; dibuat otomatis jika kosong
+	Active      *bool    `json:"active"`
+}
+
+// Satu percobaan pengiriman webhook
+type DeliveryAttempt struct {
+	At         time.Time `json:"at" bson:"at"`
+	StatusCode int       `json:"statusCode,omitempty" bson:"statusCode,omitempty"`
+	Error      string    `json:"error,omitempty" bson:"error,omitempty"`
+	DurationMs int64     `json:"durationMs" bson:"durationMs"`
+}
+
+// Struct untuk antrian pengiriman webhook (disimpan di koleksi "webhook_deliveries")
+type WebhookDelivery struct {
+	ID            primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	WebhookID     primitive.ObjectID `json:"webhookId" bson:"webhookId"`
+	ProjectID     primitive.ObjectID `json:"projectId" bson:"projectId"`
+	Event         string             `json:"event" bson:"event"`
+	Collection    string             `json:"collection" bson:"collection"`
+	Payload       string             `json:"payload" bson:"payload"` // Body JSON persis seperti yang dikirim (dan ditandatangani)
+	Status        string             `json:"status" bson:"status"`
+	Attempts      int                `json:"attempts" bson:"attempts"`
+	NextAttemptAt time.Time          `json:"nextAttemptAt" bson:"nextAttemptAt"`
+	LockedUntil   *time.Time         `json:"-" bson:"lockedUntil,omitempty"`
+	Logs          []DeliveryAttempt  `json:"logs" bson:"logs"`
+	CreatedAt     time.Time          `json:"createdAt" bson:"createdAt"`
+	DeliveredAt   *time.Time         `json:"deliveredAt,omitempty" bson:"deliveredAt,omitempty"`
+}
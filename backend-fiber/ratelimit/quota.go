@@ -0,0 +1,61 @@
+// file: ratelimit/quota.go
+package ratelimit
+
+import (
+	"context"
+	"time"
+
+	"github.com/fiber-mongo/starter-kit/database" // Sesuaikan dengan nama modul Anda
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Counter kuota request bulanan disimpan di database platform (bukan di memori proses),
+// supaya tidak hilang saat restart dan sama untuk semua instance server.
+// Dokumen: {projectId, period, count, expiresAt}; unik per (projectId, period), dihapus TTL index setelah expiresAt.
+const QuotaCollectionName = "request_quotas"
+
+// ConsumeRequest menambah counter kuota bulanan proyek sebesar 1 jika masih di bawah limit.
+// Request yang ditolak tidak ikut dihitung. Mengembalikan total pemakaian periode ini.
+func ConsumeRequest(ctx context.Context, projectID primitive.ObjectID, limit int64, now time.Time) (used int64, allowed bool, err error) {
+	period, periodEnd := QuotaPeriod(now)
+	coll := database.GetCollection(QuotaCollectionName)
+
+	// Filter count < limit membuat penambahan atomik. Jika counter sudah penuh, upsert mencoba membuat
+	// dokumen baru dan ditolak index unik (duplicate key) -> kuota habis.
+	// Duplicate key juga bisa terjadi saat dua request pertama di periode baru bersamaan, jadi dicoba sekali lagi.
+	for attempt := 0; attempt < 2; attempt++ {
+		var counter struct {
+			Count int64 `bson:"count"`
+		}
+		err = coll.FindOneAndUpdate(ctx,
+			bson.M{"projectId": projectID, "period": period, "count": bson.M{"$lt": limit}},
+			bson.M{"$inc": bson.M{"count": 1}, "$setOnInsert": bson.M{"expiresAt": periodEnd}},
+			options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+		).Decode(&counter)
+		if err == nil {
+			return counter.Count, true, nil
+		}
+		if !mongo.IsDuplicateKeyError(err) {
+			return 0, false, err
+		}
+	}
+
+	used, err = MonthlyUsage(ctx, projectID, period)
+	return used, false, err
+}
+
+// MonthlyUsage membaca jumlah request proyek pada periode kuota (misalnya "2025-01")
+func MonthlyUsage(ctx context.Context, projectID primitive.ObjectID, period string) (int64, error) {
+	var counter struct {
+		Count int64 `bson:"count"`
+	}
+	err := database.GetCollection(QuotaCollectionName).FindOne(ctx, bson.M{"projectId": projectID, "period": period}).Decode(&counter)
+	if err == mongo.ErrNoDocuments {
+		return 0, nil
+	}
+	return counter.Count, err
+}
@@ -0,0 +1,134 @@
+// file: ratelimit/settings.go
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/fiber-mongo/starter-kit/database" // Sesuaikan dengan nama modul Anda
+	"github.com/fiber-mongo/starter-kit/models"   // Sesuaikan dengan nama modul Anda
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	settingsCollectionName = "rate_limits"
+
+	// Settings dan ukuran storage di-cache supaya tidak perlu query tambahan di setiap request
+	settingsCacheTTL = 30 * time.Second
+	storageCacheTTL  = time.Minute
+)
+
+// Batas default untuk proyek yang belum mengatur rate limit sendiri
+const (
+	DefaultProjectRate  = 50
+	DefaultProjectBurst = 100
+	DefaultKeyRate      = 20
+	DefaultKeyBurst     = 40
+)
+
+type cachedSettings struct {
+	settings  models.RateLimitSettings
+	expiresAt time.Time
+}
+
+type cachedStorage struct {
+	bytes     int64
+	expiresAt time.Time
+}
+
+var (
+	cacheMu       sync.Mutex
+	settingsCache = map[primitive.ObjectID]cachedSettings{}
+	storageCache  = map[primitive.ObjectID]cachedStorage{}
+)
+
+// DefaultSettings mengembalikan settings untuk proyek yang belum punya dokumen di koleksi "rate_limits"
+func DefaultSettings(projectID primitive.ObjectID) models.RateLimitSettings {
+	return models.RateLimitSettings{
+		ProjectID:    projectID,
+		ProjectRate:  DefaultProjectRate,
+		ProjectBurst: DefaultProjectBurst,
+		KeyRate:      DefaultKeyRate,
+		KeyBurst:     DefaultKeyBurst,
+	}
+}
+
+// ProjectLimit dan KeyLimit mengubah settings menjadi Limit token bucket
+func ProjectLimit(s models.RateLimitSettings) Limit {
+	return Limit{Rate: s.ProjectRate, Burst: s.ProjectBurst}
+}
+
+func KeyLimit(s models.RateLimitSettings) Limit {
+	return Limit{Rate: s.KeyRate, Burst: s.KeyBurst}
+}
+
+// LoadSettings membaca settings rate limit sebuah proyek (dengan cache singkat)
+func LoadSettings(ctx context.Context, projectID primitive.ObjectID) (models.RateLimitSettings, error) {
+	now := time.Now()
+	cacheMu.Lock()
+	cached, ok := settingsCache[projectID]
+	cacheMu.Unlock()
+	if ok && now.Before(cached.expiresAt) {
+		return cached.settings, nil
+	}
+
+	settings := DefaultSettings(projectID)
+	err := database.GetCollection(settingsCollectionName).FindOne(ctx, bson.M{"projectId": projectID}).Decode(&settings)
+	if err != nil && err != mongo.ErrNoDocuments {
+		return settings, err
+	}
+
+	cacheMu.Lock()
+	settingsCache[projectID] = cachedSettings{settings: settings, expiresAt: now.Add(settingsCacheTTL)}
+	cacheMu.Unlock()
+	return settings, nil
+}
+
+// Invalidate membuang cache settings dan ukuran storage sebuah proyek (dipanggil setelah settings diubah/dihapus)
+func Invalidate(projectID primitive.ObjectID) {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+	delete(settingsCache, projectID)
+	delete(storageCache, projectID)
+}
+
+// StorageUsage mengembalikan ukuran data proyek dari cache, atau memanggil measure jika cache sudah kedaluwarsa
+func StorageUsage(ctx context.Context, projectID primitive.ObjectID, measure func(ctx context.Context) (int64, error)) (int64, error) {
+	now := time.Now()
+	cacheMu.Lock()
+	cached, ok := storageCache[projectID]
+	cacheMu.Unlock()
+	if ok && now.Before(cached.expiresAt) {
+		return cached.bytes, nil
+	}
+
+	bytes, err := measure(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	cacheMu.Lock()
+	storageCache[projectID] = cachedStorage{bytes: bytes, expiresAt: now.Add(storageCacheTTL)}
+	cacheMu.Unlock()
+	return bytes, nil
+}
+
+// QuotaPeriod mengembalikan periode kuota bulanan (misalnya "2025-01") dan waktu periode tersebut berakhir
+func QuotaPeriod(now time.Time) (string, time.Time) {
+	now = now.UTC()
+	start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return start.Format("2006-01"), start.AddDate(0, 1, 0)
+}
+
+// Key untuk bucket di Store
+func ProjectKey(projectID primitive.ObjectID) string {
+	return "project:" + projectID.Hex()
+}
+
+func APIKeyKey(apiKeyID string) string {
+	return "key:" + apiKeyID
+}
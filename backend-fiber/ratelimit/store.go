@@ -0,0 +1,133 @@
+// file: ratelimit/store.go
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// Limit token bucket: Rate token per detik dengan kapasitas Burst. Rate <= 0 berarti tanpa batas.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// Unlimited menandakan limit yang tidak pernah menolak request
+func (l Limit) Unlimited() bool {
+	return l.Rate <= 0
+}
+
+// Hasil pengecekan satu bucket
+type Decision struct {
+	Allowed    bool
+	Limit      int           // Kapasitas bucket (Burst)
+	Remaining  int           // Token yang tersisa setelah request ini
+	Reset      time.Duration // Waktu sampai bucket penuh kembali
+	RetryAfter time.Duration // Waktu sampai 1 token tersedia (hanya jika ditolak)
+}
+
+// Store menyimpan state token bucket rate limit. Implementasi default ada di memori (per proses);
+// untuk beberapa instance server, pasang store bersama (misalnya Redis) lewat SetStore.
+// Kuota request bulanan tidak disimpan di sini (lihat ConsumeRequest).
+type Store interface {
+	// Allow mengambil satu token dari bucket key
+	Allow(ctx context.Context, key string, limit Limit, now time.Time) (Decision, error)
+}
+
+var (
+	storeMu      sync.RWMutex
+	defaultStore Store = NewMemoryStore()
+)
+
+// SetStore mengganti store yang dipakai oleh middleware rate limit
+func SetStore(s Store) {
+	storeMu.Lock()
+	defer storeMu.Unlock()
+	defaultStore = s
+}
+
+// DefaultStore mengembalikan store yang sedang dipakai
+func DefaultStore() Store {
+	storeMu.RLock()
+	defer storeMu.RUnlock()
+	return defaultStore
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+	fullAt time.Time // Setelah waktu ini bucket pasti penuh lagi, jadi aman dibuang
+}
+
+// MemoryStore adalah Store di memori proses dengan algoritma token bucket
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+// Interval minimal antar pembersihan bucket yang tidak diperlukan lagi
+const sweepInterval = time.Minute
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets: make(map[string]*bucket),
+	}
+}
+
+func (s *MemoryStore) Allow(ctx context.Context, key string, limit Limit, now time.Time) (Decision, error) {
+	if limit.Unlimited() {
+		return Decision{Allowed: true}, nil
+	}
+	burst := limit.Burst
+	if burst < 1 {
+		burst = int(math.Ceil(limit.Rate))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sweep(now)
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(burst), last: now}
+		s.buckets[key] = b
+	}
+	// Isi ulang token sesuai waktu yang berlalu sejak request terakhir
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens = math.Min(float64(burst), b.tokens+elapsed*limit.Rate)
+		b.last = now
+	}
+
+	d := Decision{Limit: burst}
+	if b.tokens >= 1 {
+		b.tokens--
+		d.Allowed = true
+	} else {
+		d.RetryAfter = secondsToDuration((1 - b.tokens) / limit.Rate)
+	}
+	d.Remaining = int(b.tokens)
+	d.Reset = secondsToDuration((float64(burst) - b.tokens) / limit.Rate)
+	b.fullAt = now.Add(d.Reset)
+	return d, nil
+}
+
+// Fungsi helper untuk membuang bucket yang sudah penuh kembali.
+// Dipanggil dengan s.mu terkunci.
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+	s.lastSweep = now
+	for key, b := range s.buckets {
+		if now.After(b.fullAt) {
+			delete(s.buckets, key)
+		}
+	}
+}
+
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second))
+}
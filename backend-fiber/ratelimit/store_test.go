@@ -0,0 +1,105 @@
+// file: ratelimit/store_test.go
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryStoreAllow(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	type request struct {
+		at            time.Duration // Waktu sejak start
+		wantAllowed   bool
+		wantRemaining int
+		wantRetry     time.Duration // Hanya dicek jika request ditolak
+	}
+	cases := []struct {
+		name     string
+		limit    Limit
+		requests []request
+	}{
+		{
+			name:  "burst then reject",
+			limit: Limit{Rate: 1, Burst: 3},
+			requests: []request{
+				{0, true, 2, 0},
+				{0, true, 1, 0},
+				{0, true, 0, 0},
+				{0, false, 0, time.Second},
+			},
+		},
+		{
+			name:  "refill over time",
+			limit: Limit{Rate: 2, Burst: 2},
+			requests: []request{
+				{0, true, 1, 0},
+				{0, true, 0, 0},
+				{0, false, 0, 500 * time.Millisecond},
+				{250 * time.Millisecond, false, 0, 250 * time.Millisecond},
+				{500 * time.Millisecond, true, 0, 0},
+				{10 * time.Second, true, 1, 0}, // Tidak melebihi Burst walaupun lama tidak dipakai
+			},
+		},
+		{
+			name:  "burst defaults to rate",
+			limit: Limit{Rate: 2},
+			requests: []request{
+				{0, true, 1, 0},
+				{0, true, 0, 0},
+				{0, false, 0, 500 * time.Millisecond},
+			},
+		},
+		{
+			name:  "unlimited",
+			limit: Limit{Rate: 0, Burst: 1},
+			requests: []request{
+				{0, true, 0, 0},
+				{0, true, 0, 0},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			store := NewMemoryStore()
+			for i, r := range tc.requests {
+				d, err := store.Allow(context.Background(), "k", tc.limit, start.Add(r.at))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if d.Allowed != r.wantAllowed || d.Remaining != r.wantRemaining {
+					t.Fatalf("request %d: allowed=%v remaining=%d, want allowed=%v remaining=%d", i, d.Allowed, d.Remaining, r.wantAllowed, r.wantRemaining)
+				}
+				if !d.Allowed && d.RetryAfter != r.wantRetry {
+					t.Fatalf("request %d: RetryAfter = %v, want %v", i, d.RetryAfter, r.wantRetry)
+				}
+			}
+		})
+	}
+}
+
+func TestMemoryStoreKeysAreIndependent(t *testing.T) {
+	store := NewMemoryStore()
+	now := time.Now()
+	limit := Limit{Rate: 1, Burst: 1}
+
+	if d, _ := store.Allow(context.Background(), "a", limit, now); !d.Allowed {
+		t.Fatal("first request for a should be allowed")
+	}
+	if d, _ := store.Allow(context.Background(), "a", limit, now); d.Allowed {
+		t.Fatal("second request for a should be rejected")
+	}
+	if d, _ := store.Allow(context.Background(), "b", limit, now); !d.Allowed {
+		t.Fatal("bucket b must not share tokens with a")
+	}
+}
+
+func TestQuotaPeriod(t *testing.T) {
+	period, end := QuotaPeriod(time.Date(2025, 12, 31, 23, 0, 0, 0, time.FixedZone("WIB", 7*3600)))
+	if period != "2025-12" || !end.Equal(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("QuotaPeriod = %s, %v", period, end)
+	}
+}
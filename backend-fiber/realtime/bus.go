@@ -0,0 +1,152 @@
+// file: realtime/bus.go
+package realtime
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	busTokenPrefix      = "bus:"
+	subscriptionBuffer  = 64
+	defaultHistoryLimit = 256
+)
+
+var (
+	// ErrInvalidResumeToken dikembalikan jika token bus tidak bisa dibaca
+	ErrInvalidResumeToken = errors.New("invalid resume token")
+	// ErrResumeTokenExpired dikembalikan jika event setelah token sudah tidak ada di history
+	// (lebih lama dari historyLimit event terakhir, atau berasal dari proses server sebelumnya)
+	ErrResumeTokenExpired = errors.New("resume token expired")
+)
+
+// Subscription menerima event dari satu topik (proyek + koleksi).
+// Channel C ditutup jika subscriber terlalu lambat atau Unsubscribe dipanggil;
+// client bisa melanjutkan dengan ID event terakhir yang diterima.
+type Subscription struct {
+	C     chan Event
+	topic string
+	once  sync.Once
+}
+
+// Bus adalah event bus di dalam proses, dipakai saat change stream MongoDB tidak tersedia
+// (misalnya server standalone tanpa replica set). Event diisi oleh handler penulisan /data.
+type Bus struct {
+	mu           sync.Mutex
+	seq          uint64
+	historyLimit int
+	subs         map[string]map[*Subscription]struct{}
+	history      map[string][]Event
+	trimmed      map[string]uint64 // Nomor urut event terakhir yang sudah dibuang dari history per topik
+}
+
+// NewBus membuat event bus yang menyimpan historyLimit event terakhir per topik untuk resume
+func NewBus(historyLimit int) *Bus {
+	return &Bus{
+		historyLimit: historyLimit,
+		subs:         map[string]map[*Subscription]struct{}{},
+		history:      map[string][]Event{},
+		trimmed:      map[string]uint64{},
+	}
+}
+
+// Bus default yang dipakai aplikasi
+var DefaultBus = NewBus(defaultHistoryLimit)
+
+// Publish mengirim event ke bus default
+func Publish(e Event) {
+	DefaultBus.Publish(e)
+}
+
+func topicKey(projectID, collection string) string {
+	return projectID + "/" + collection
+}
+
+// IsBusToken memeriksa apakah token resume berasal dari event bus (bukan change stream)
+func IsBusToken(token string) bool {
+	return strings.HasPrefix(token, busTokenPrefix)
+}
+
+// Publish memberi nomor urut pada event, menyimpannya di history, lalu mengirimkannya ke semua subscriber topik
+func (b *Bus) Publish(e Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.seq++
+	e.ID = busTokenPrefix + strconv.FormatUint(b.seq, 10)
+	key := topicKey(e.ProjectID, e.Collection)
+
+	history := append(b.history[key], e)
+	if len(history) > b.historyLimit {
+		drop := len(history) - b.historyLimit
+		b.trimmed[key] = busSeq(history[drop-1].ID)
+		history = history[drop:]
+	}
+	b.history[key] = history
+
+	for sub := range b.subs[key] {
+		select {
+		case sub.C <- e:
+		default:
+			// Subscriber terlalu lambat: putuskan, client harus resume dengan ID terakhir
+			b.removeLocked(sub)
+		}
+	}
+}
+
+// Subscribe mendaftarkan subscriber baru. Jika afterToken diisi, event setelah token tersebut dikembalikan
+// sebagai "missed" untuk dikirim lebih dulu. Token yang event setelahnya sudah tidak lengkap di history
+// menghasilkan ErrResumeTokenExpired supaya client tahu ada event yang terlewat.
+func (b *Bus) Subscribe(projectID, collection, afterToken string) (*Subscription, []Event, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	key := topicKey(projectID, collection)
+	var missed []Event
+	if afterToken != "" {
+		after, err := strconv.ParseUint(strings.TrimPrefix(afterToken, busTokenPrefix), 10, 64)
+		if !IsBusToken(afterToken) || err != nil {
+			return nil, nil, ErrInvalidResumeToken
+		}
+		if after > b.seq || after < b.trimmed[key] {
+			return nil, nil, ErrResumeTokenExpired
+		}
+		for _, e := range b.history[key] {
+			if busSeq(e.ID) > after {
+				missed = append(missed, e)
+			}
+		}
+	}
+
+	sub := &Subscription{C: make(chan Event, subscriptionBuffer), topic: key}
+	if b.subs[key] == nil {
+		b.subs[key] = map[*Subscription]struct{}{}
+	}
+	b.subs[key][sub] = struct{}{}
+	return sub, missed, nil
+}
+
+// Fungsi helper untuk membaca nomor urut dari ID event bus
+func busSeq(id string) uint64 {
+	seq, _ := strconv.ParseUint(strings.TrimPrefix(id, busTokenPrefix), 10, 64)
+	return seq
+}
+
+// Unsubscribe melepas subscriber dan menutup channel-nya
+func (b *Bus) Unsubscribe(sub *Subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.removeLocked(sub)
+}
+
+func (b *Bus) removeLocked(sub *Subscription) {
+	if subs, ok := b.subs[sub.topic]; ok {
+		delete(subs, sub)
+		if len(subs) == 0 {
+			delete(b.subs, sub.topic)
+		}
+	}
+	sub.once.Do(func() { close(sub.C) })
+}
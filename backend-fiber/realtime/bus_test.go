@@ -0,0 +1,45 @@
+package realtime
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestBusResume(t *testing.T) {
+	bus := NewBus(3)
+	for i := 0; i < 5; i++ {
+		bus.Publish(Event{ProjectID: "p", Collection: "a"})
+		bus.Publish(Event{ProjectID: "p", Collection: "b"})
+	}
+	// Topik "a" menyimpan bus:5, bus:7, bus:9; bus:3 sudah dibuang
+
+	tests := []struct {
+		name    string
+		token   string
+		missed  int
+		wantErr error
+	}{
+		{"no token", "", 0, nil},
+		{"latest event", "bus:9", 0, nil},
+		{"inside history", "bus:5", 2, nil},
+		{"last trimmed event", "bus:3", 3, nil},
+		{"older than history", "bus:1", 0, ErrResumeTokenExpired},
+		{"from a previous process", "bus:99", 0, ErrResumeTokenExpired},
+		{"malformed", "bus:x", 0, ErrInvalidResumeToken},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sub, missed, err := bus.Subscribe("p", "a", tt.token)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			defer bus.Unsubscribe(sub)
+			if len(missed) != tt.missed {
+				t.Fatalf("missed %d events, want %d", len(missed), tt.missed)
+			}
+		})
+	}
+}
@@ -0,0 +1,26 @@
+// file: realtime/event.go
+package realtime
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Jenis operasi pada event perubahan dokumen (sama dengan operationType di change stream MongoDB)
+const (
+	OperationInsert = "insert"
+	OperationUpdate = "update"
+	OperationDelete = "delete"
+)
+
+// Event perubahan satu dokumen di koleksi user
+type Event struct {
+	ID            string      `json:"id"` // Token untuk melanjutkan stream (resume token)
+	ProjectID     string      `json:"projectId"`
+	Collection    string      `json:"collection"`
+	OperationType string      `json:"operationType"`
+	DocumentID    interface{} `json:"documentId"`
+	FullDocument  bson.M      `json:"fullDocument,omitempty"` // Kosong untuk operasi delete
+	Timestamp     time.Time   `json:"timestamp"`
+}
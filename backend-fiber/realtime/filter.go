@@ -0,0 +1,219 @@
+// file: realtime/filter.go
+package realtime
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Operator filter yang didukung, baik oleh change stream maupun event bus
+var supportedOperators = map[string]bool{
+	"$eq": true, "$ne": true, "$gt": true, "$gte": true, "$lt": true, "$lte": true,
+	"$in": true, "$nin": true, "$exists": true,
+}
+
+// ValidateFilter memastikan filter hanya memakai bentuk yang bisa dievaluasi oleh Match:
+// {field: value} atau {field: {$op: value}} dengan operator pada supportedOperators.
+func ValidateFilter(filter bson.M) error {
+	for field, cond := range filter {
+		if field == "" || strings.HasPrefix(field, "$") {
+			return fmt.Errorf("unsupported filter key '%s'", field)
+		}
+		ops, ok := asMap(cond)
+		if !ok || !isOperatorMap(ops) {
+			continue
+		}
+		for op, value := range ops {
+			if !supportedOperators[op] {
+				return fmt.Errorf("unsupported filter operator '%s'", op)
+			}
+			if (op == "$in" || op == "$nin") && asList(value) == nil {
+				return errors.New(op + " requires an array")
+			}
+		}
+	}
+	return nil
+}
+
+func isOperatorMap(m map[string]interface{}) bool {
+	for k := range m {
+		if strings.HasPrefix(k, "$") {
+			return true
+		}
+	}
+	return false
+}
+
+// PrefixFilter menambahkan prefix ke semua nama field, misalnya "fullDocument." untuk $match di change stream
+func PrefixFilter(filter bson.M, prefix string) bson.M {
+	prefixed := bson.M{}
+	for field, cond := range filter {
+		prefixed[prefix+field] = cond
+	}
+	return prefixed
+}
+
+// Match mengevaluasi filter terhadap dokumen (dipakai oleh event bus). Field bisa memakai notasi titik.
+func Match(filter bson.M, doc bson.M) bool {
+	for field, cond := range filter {
+		value, exists := lookup(doc, field)
+
+		ops, isOps := asMap(cond)
+		if !isOps || !isOperatorMap(ops) {
+			if !exists || !equal(value, cond) {
+				return false
+			}
+			continue
+		}
+
+		for op, arg := range ops {
+			if !matchOperator(op, arg, value, exists) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func matchOperator(op string, arg, value interface{}, exists bool) bool {
+	switch op {
+	case "$eq":
+		return exists && equal(value, arg)
+	case "$ne":
+		return !exists || !equal(value, arg)
+	case "$gt", "$gte", "$lt", "$lte":
+		if !exists {
+			return false
+		}
+		cmp, ok := compare(value, arg)
+		if !ok {
+			return false
+		}
+		switch op {
+		case "$gt":
+			return cmp > 0
+		case "$gte":
+			return cmp >= 0
+		case "$lt":
+			return cmp < 0
+		default:
+			return cmp <= 0
+		}
+	case "$in", "$nin":
+		found := false
+		if exists {
+			for _, candidate := range asList(arg) {
+				if equal(value, candidate) {
+					found = true
+					break
+				}
+			}
+		}
+		if op == "$in" {
+			return found
+		}
+		return !found
+	case "$exists":
+		want, _ := arg.(bool)
+		return exists == want
+	}
+	return false
+}
+
+func lookup(doc bson.M, path string) (interface{}, bool) {
+	var current interface{} = doc
+	for _, part := range strings.Split(path, ".") {
+		m, ok := asMap(current)
+		if !ok {
+			return nil, false
+		}
+		current, ok = m[part]
+		if !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+func asMap(v interface{}) (map[string]interface{}, bool) {
+	switch m := v.(type) {
+	case bson.M:
+		return m, true
+	case map[string]interface{}:
+		return m, true
+	case bson.D:
+		return m.Map(), true
+	}
+	return nil, false
+}
+
+func asList(v interface{}) []interface{} {
+	switch l := v.(type) {
+	case bson.A:
+		return l
+	case []interface{}:
+		return l
+	}
+	return nil
+}
+
+// Angka dari JSON (float64) dan dari BSON (int32/int64/double) dibandingkan sebagai float64
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	}
+	return 0, false
+}
+
+func equal(a, b interface{}) bool {
+	if fa, ok := toFloat(a); ok {
+		fb, ok := toFloat(b)
+		return ok && fa == fb
+	}
+	// Nilai array cocok jika salah satu elemennya sama (seperti query MongoDB)
+	if list := asList(a); list != nil {
+		if _, bIsList := b.([]interface{}); !bIsList {
+			for _, item := range list {
+				if equal(item, b) {
+					return true
+				}
+			}
+		}
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func compare(a, b interface{}) (int, bool) {
+	if fa, ok := toFloat(a); ok {
+		fb, ok := toFloat(b)
+		if !ok {
+			return 0, false
+		}
+		switch {
+		case fa < fb:
+			return -1, true
+		case fa > fb:
+			return 1, true
+		}
+		return 0, true
+	}
+	sa, okA := a.(string)
+	sb, okB := b.(string)
+	if okA && okB {
+		return strings.Compare(sa, sb), true
+	}
+	return 0, false
+}
@@ -0,0 +1,205 @@
+// file: repository/memory.go
+package repository
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/fiber-mongo/starter-kit/models" // Sesuaikan dengan path modulmu
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MemoryProjects menyimpan proyek di memori proses. Dipakai untuk unit test handler tanpa MongoDB.
+type MemoryProjects struct {
+	mu       sync.Mutex
+	projects []models.Project
+	settings []models.CollectionSettings
+	hooks    []models.CollectionHook
+}
+
+func NewMemoryProjects() *MemoryProjects {
+	return &MemoryProjects{}
+}
+
+func (r *MemoryProjects) List(ctx context.Context) ([]models.Project, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]models.Project{}, r.projects...), nil
+}
+
+func (r *MemoryProjects) Get(ctx context.Context, id primitive.ObjectID) (models.Project, error) {
+	return r.find(func(p models.Project) bool { return p.ID == id })
+}
+
+func (r *MemoryProjects) GetByAPIKey(ctx context.Context, apiKey string) (models.Project, error) {
+	return r.find(func(p models.Project) bool { return p.ApiKey == apiKey })
+}
+
+func (r *MemoryProjects) find(match func(models.Project) bool) (models.Project, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, p := range r.projects {
+		if match(p) {
+			return p, nil
+		}
+	}
+	return models.Project{}, ErrNotFound
+}
+
+func (r *MemoryProjects) Create(ctx context.Context, project *models.Project) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if project.ID.IsZero() {
+		project.ID = primitive.NewObjectID()
+	}
+	r.projects = append(r.projects, *project)
+	return nil
+}
+
+func (r *MemoryProjects) SetActiveCollections(ctx context.Context, id primitive.ObjectID, collections []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.projects {
+		if r.projects[i].ID == id {
+			r.projects[i].ActiveCollections = collections
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+func (r *MemoryProjects) UpdateConnection(ctx context.Context, project models.Project) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.projects {
+		if r.projects[i].ID == project.ID {
+			// API Key, koleksi aktif dan waktu pembuatan tidak ikut diubah
+			project.ApiKey = r.projects[i].ApiKey
+			project.ActiveCollections = r.projects[i].ActiveCollections
+			project.CreatedAt = r.projects[i].CreatedAt
+			r.projects[i] = project
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+func (r *MemoryProjects) Delete(ctx context.Context, id primitive.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, p := range r.projects {
+		if p.ID != id {
+			continue
+		}
+		r.projects = append(r.projects[:i], r.projects[i+1:]...)
+
+		settings := r.settings[:0]
+		for _, s := range r.settings {
+			if s.ProjectID != id {
+				settings = append(settings, s)
+			}
+		}
+		r.settings = settings
+
+		hooks := r.hooks[:0]
+		for _, h := range r.hooks {
+			if h.ProjectID != id {
+				hooks = append(hooks, h)
+			}
+		}
+		r.hooks = hooks
+		return nil
+	}
+	return ErrNotFound
+}
+
+func (r *MemoryProjects) CollectionSettings(ctx context.Context, projectID primitive.ObjectID, collectionNames []string) (map[string]models.CollectionSettings, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	settings := defaultCollectionSettings(projectID, collectionNames)
+	for _, s := range r.settings {
+		if _, wanted := settings[s.CollectionName]; wanted && s.ProjectID == projectID {
+			settings[s.CollectionName] = s
+		}
+	}
+	return settings, nil
+}
+
+func (r *MemoryProjects) CollectionHooks(ctx context.Context, project models.Project, collection, event string) ([]models.CollectionHook, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	list := []models.CollectionHook{}
+	for _, h := range r.hooks {
+		if h.ProjectID == project.ID && h.CollectionName == collection && h.Event == event && h.Active {
+			list = append(list, h)
+		}
+	}
+	sort.SliceStable(list, func(i, j int) bool {
+		if list[i].Order != list[j].Order {
+			return list[i].Order < list[j].Order
+		}
+		return list[i].CreatedAt.Before(list[j].CreatedAt)
+	})
+	return list, nil
+}
+
+// SaveCollectionSettings menyimpan (atau mengganti) settings sebuah koleksi
+func (r *MemoryProjects) SaveCollectionSettings(settings models.CollectionSettings) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, s := range r.settings {
+		if s.ProjectID == settings.ProjectID && s.CollectionName == settings.CollectionName {
+			r.settings[i] = settings
+			return
+		}
+	}
+	r.settings = append(r.settings, settings)
+}
+
+// AddCollectionHook menambahkan hook koleksi
+func (r *MemoryProjects) AddCollectionHook(hook models.CollectionHook) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if hook.ID.IsZero() {
+		hook.ID = primitive.NewObjectID()
+	}
+	r.hooks = append(r.hooks, hook)
+}
+
+// MemoryDocuments menyimpan koleksi database user di memori proses, satu set koleksi per proyek
+type MemoryDocuments struct {
+	mu          sync.Mutex
+	collections map[string]*memoryCollection
+}
+
+func NewMemoryDocuments() *MemoryDocuments {
+	return &MemoryDocuments{collections: map[string]*memoryCollection{}}
+}
+
+func (r *MemoryDocuments) Collection(ctx context.Context, project models.Project, name string) (Collection, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := project.ID.Hex() + "/" + name
+	coll, ok := r.collections[key]
+	if !ok {
+		coll = &memoryCollection{name: name}
+		r.collections[key] = coll
+	}
+	return coll, nil
+}
+
+func (r *MemoryDocuments) DataSize(ctx context.Context, project models.Project) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	prefix := project.ID.Hex() + "/"
+	var size int64
+	for key, coll := range r.collections {
+		if strings.HasPrefix(key, prefix) {
+			size += coll.size()
+		}
+	}
+	return size, nil
+}
@@ -0,0 +1,637 @@
+// file: repository/memory_collection.go
+package repository
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/fiber-mongo/starter-kit/realtime" // Sesuaikan dengan path modulmu
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Koleksi di memori untuk unit test. Hanya mendukung bagian query MongoDB yang dipakai operasi dokumen:
+// filter per field (lihat realtime.Match), $and/$or, $expr {$eq: ["$$ROOT", {$literal: ...}]} dari If-Match,
+// update $set/$unset di level atas, sort, skip, limit dan upsert pada ReplaceOne. Projection diabaikan.
+type memoryCollection struct {
+	mu   sync.Mutex
+	name string
+	docs []bson.Raw // Urutan penyimpanan = urutan natural hasil query
+}
+
+func (c *memoryCollection) Name() string {
+	return c.name
+}
+
+func (c *memoryCollection) size() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var size int64
+	for _, doc := range c.docs {
+		size += int64(len(doc))
+	}
+	return size
+}
+
+func (c *memoryCollection) Find(ctx context.Context, filter interface{}, opts ...*options.FindOptions) (*mongo.Cursor, error) {
+	var sortSpec interface{}
+	var skip, limit int64
+	for _, o := range opts {
+		if o == nil {
+			continue
+		}
+		if o.Sort != nil {
+			sortSpec = o.Sort
+		}
+		if o.Skip != nil {
+			skip = *o.Skip
+		}
+		if o.Limit != nil {
+			limit = *o.Limit
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	matched, err := c.query(filter, sortSpec)
+	if err != nil {
+		return nil, err
+	}
+	if skip > int64(len(matched)) {
+		skip = int64(len(matched))
+	}
+	matched = matched[skip:]
+	if limit > 0 && limit < int64(len(matched)) {
+		matched = matched[:limit]
+	}
+
+	docs := make([]interface{}, 0, len(matched))
+	for _, i := range matched {
+		docs = append(docs, c.docs[i])
+	}
+	return mongo.NewCursorFromDocuments(docs, nil, nil)
+}
+
+func (c *memoryCollection) FindOne(ctx context.Context, filter interface{}, opts ...*options.FindOneOptions) *mongo.SingleResult {
+	var sortSpec interface{}
+	var skip int64
+	for _, o := range opts {
+		if o == nil {
+			continue
+		}
+		if o.Sort != nil {
+			sortSpec = o.Sort
+		}
+		if o.Skip != nil {
+			skip = *o.Skip
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	matched, err := c.query(filter, sortSpec)
+	if err != nil {
+		return mongo.NewSingleResultFromDocument(bson.D{}, err, nil)
+	}
+	if skip >= int64(len(matched)) {
+		return noDocument()
+	}
+	return mongo.NewSingleResultFromDocument(c.docs[matched[skip]], nil, nil)
+}
+
+func (c *memoryCollection) CountDocuments(ctx context.Context, filter interface{}, opts ...*options.CountOptions) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	matched, err := c.query(filter, nil)
+	return int64(len(matched)), err
+}
+
+func (c *memoryCollection) InsertOne(ctx context.Context, document interface{}, opts ...*options.InsertOneOptions) (*mongo.InsertOneResult, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	id, err := c.insert(document, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &mongo.InsertOneResult{InsertedID: id}, nil
+}
+
+func (c *memoryCollection) InsertMany(ctx context.Context, documents []interface{}, opts ...*options.InsertManyOptions) (*mongo.InsertManyResult, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	result := &mongo.InsertManyResult{}
+	for _, document := range documents {
+		id, err := c.insert(document, nil)
+		if err != nil {
+			return result, err
+		}
+		result.InsertedIDs = append(result.InsertedIDs, id)
+	}
+	return result, nil
+}
+
+func (c *memoryCollection) FindOneAndUpdate(ctx context.Context, filter, update interface{}, opts ...*options.FindOneAndUpdateOptions) *mongo.SingleResult {
+	var sortSpec interface{}
+	returnAfter := false
+	for _, o := range opts {
+		if o == nil {
+			continue
+		}
+		if o.Sort != nil {
+			sortSpec = o.Sort
+		}
+		if o.ReturnDocument != nil {
+			returnAfter = *o.ReturnDocument == options.After
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	matched, err := c.query(filter, sortSpec)
+	if err != nil {
+		return mongo.NewSingleResultFromDocument(bson.D{}, err, nil)
+	}
+	if len(matched) == 0 {
+		return noDocument()
+	}
+
+	i := matched[0]
+	before := c.docs[i]
+	after, err := applyUpdate(before, update)
+	if err != nil {
+		return mongo.NewSingleResultFromDocument(bson.D{}, err, nil)
+	}
+	c.docs[i] = after
+	if returnAfter {
+		return mongo.NewSingleResultFromDocument(after, nil, nil)
+	}
+	return mongo.NewSingleResultFromDocument(before, nil, nil)
+}
+
+func (c *memoryCollection) FindOneAndDelete(ctx context.Context, filter interface{}, opts ...*options.FindOneAndDeleteOptions) *mongo.SingleResult {
+	var sortSpec interface{}
+	for _, o := range opts {
+		if o != nil && o.Sort != nil {
+			sortSpec = o.Sort
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	matched, err := c.query(filter, sortSpec)
+	if err != nil {
+		return mongo.NewSingleResultFromDocument(bson.D{}, err, nil)
+	}
+	if len(matched) == 0 {
+		return noDocument()
+	}
+	deleted := c.docs[matched[0]]
+	c.remove(matched[0])
+	return mongo.NewSingleResultFromDocument(deleted, nil, nil)
+}
+
+func (c *memoryCollection) ReplaceOne(ctx context.Context, filter, replacement interface{}, opts ...*options.ReplaceOptions) (*mongo.UpdateResult, error) {
+	upsert := false
+	for _, o := range opts {
+		if o != nil && o.Upsert != nil {
+			upsert = *o.Upsert
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	matched, err := c.query(filter, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(matched) == 0 {
+		if !upsert {
+			return &mongo.UpdateResult{}, nil
+		}
+		// Seperti MongoDB, _id dari filter dipakai jika dokumen pengganti tidak punya _id
+		var filterID interface{}
+		if f, err := toM(filter); err == nil {
+			filterID = f["_id"]
+		}
+		id, err := c.insert(replacement, filterID)
+		if err != nil {
+			return nil, err
+		}
+		return &mongo.UpdateResult{UpsertedCount: 1, UpsertedID: id}, nil
+	}
+
+	i := matched[0]
+	doc, err := toD(replacement)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := bson.Marshal(withID(doc, c.docs[i].Lookup("_id")))
+	if err != nil {
+		return nil, err
+	}
+	c.docs[i] = raw
+	return &mongo.UpdateResult{MatchedCount: 1, ModifiedCount: 1}, nil
+}
+
+func (c *memoryCollection) DeleteOne(ctx context.Context, filter interface{}, opts ...*options.DeleteOptions) (*mongo.DeleteResult, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	matched, err := c.query(filter, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(matched) == 0 {
+		return &mongo.DeleteResult{}, nil
+	}
+	c.remove(matched[0])
+	return &mongo.DeleteResult{DeletedCount: 1}, nil
+}
+
+// Fungsi helper untuk mencari index dokumen yang cocok dengan filter, terurut sesuai sortSpec (nil = urutan natural)
+func (c *memoryCollection) query(filter, sortSpec interface{}) ([]int, error) {
+	f, err := toM(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	matched := []int{}
+	decoded := map[int]bson.M{}
+	for i, raw := range c.docs {
+		var doc bson.M
+		if err := bson.Unmarshal(raw, &doc); err != nil {
+			return nil, err
+		}
+		if matchFilter(f, doc, raw) {
+			matched = append(matched, i)
+			decoded[i] = doc
+		}
+	}
+
+	if sortSpec != nil {
+		keys, err := toD(sortSpec)
+		if err != nil {
+			return nil, err
+		}
+		sort.SliceStable(matched, func(a, b int) bool {
+			for _, key := range keys {
+				cmp := compareValues(lookupPath(decoded[matched[a]], key.Key), lookupPath(decoded[matched[b]], key.Key))
+				if cmp != 0 {
+					return (cmp < 0) == (direction(key.Value) > 0)
+				}
+			}
+			return false
+		})
+	}
+	return matched, nil
+}
+
+// Fungsi helper untuk menyimpan dokumen baru. _id dibuat otomatis (atau diambil dari defaultID) jika tidak ada.
+func (c *memoryCollection) insert(document, defaultID interface{}) (interface{}, error) {
+	doc, err := toD(document)
+	if err != nil {
+		return nil, err
+	}
+
+	var id interface{}
+	for _, e := range doc {
+		if e.Key == "_id" {
+			id = e.Value
+		}
+	}
+	if id == nil {
+		id = defaultID
+	}
+	if id == nil {
+		id = primitive.NewObjectID()
+	}
+	idValue, err := rawValue(id)
+	if err != nil {
+		return nil, err
+	}
+	for _, existing := range c.docs {
+		if existing.Lookup("_id").Equal(idValue) {
+			return nil, mongo.WriteException{WriteErrors: mongo.WriteErrors{{
+				Code:    11000,
+				Message: fmt.Sprintf("E11000 duplicate key error collection: %s index: _id_", c.name),
+			}}}
+		}
+	}
+
+	raw, err := bson.Marshal(withID(doc, idValue))
+	if err != nil {
+		return nil, err
+	}
+	c.docs = append(c.docs, raw)
+	return id, nil
+}
+
+func (c *memoryCollection) remove(i int) {
+	c.docs = append(c.docs[:i], c.docs[i+1:]...)
+}
+
+func noDocument() *mongo.SingleResult {
+	return mongo.NewSingleResultFromDocument(bson.D{}, mongo.ErrNoDocuments, nil)
+}
+
+// Fungsi helper untuk mengevaluasi filter terhadap satu dokumen
+func matchFilter(filter bson.M, doc bson.M, raw bson.Raw) bool {
+	plain := bson.M{}
+	for key, value := range filter {
+		switch key {
+		case "$and", "$or":
+			anyMatched, allMatched := false, true
+			for _, item := range toList(value) {
+				sub, err := toM(item)
+				ok := err == nil && matchFilter(sub, doc, raw)
+				anyMatched = anyMatched || ok
+				allMatched = allMatched && ok
+			}
+			if (key == "$and" && !allMatched) || (key == "$or" && !anyMatched) {
+				return false
+			}
+		case "$expr":
+			if !matchExpr(value, raw) {
+				return false
+			}
+		default:
+			plain[key] = value
+		}
+	}
+	return realtime.Match(plain, doc)
+}
+
+// Hanya ekspresi {$eq: ["$$ROOT", {$literal: snapshot}]} yang didukung: dokumen harus persis sama dengan snapshot
+func matchExpr(expr interface{}, raw bson.Raw) bool {
+	m, err := toM(expr)
+	if err != nil {
+		return false
+	}
+	args := toList(m["$eq"])
+	if len(args) != 2 || args[0] != "$$ROOT" {
+		return false
+	}
+	literal, err := toM(args[1])
+	if err != nil {
+		return false
+	}
+	snapshot, ok := literal["$literal"].(bson.Raw)
+	return ok && bytes.Equal(raw, snapshot)
+}
+
+// Fungsi helper untuk menjalankan update $set/$unset/$inc. Urutan field yang sudah ada tetap dipertahankan.
+func applyUpdate(raw bson.Raw, update interface{}) (bson.Raw, error) {
+	u, err := toM(update)
+	if err != nil {
+		return nil, err
+	}
+	for op := range u {
+		if op != "$set" && op != "$unset" && op != "$inc" {
+			return nil, fmt.Errorf("memory collection: update operator %s is not supported", op)
+		}
+	}
+
+	var doc bson.D
+	if err := bson.Unmarshal(raw, &doc); err != nil {
+		return nil, err
+	}
+
+	if set, ok := u["$set"]; ok {
+		fields, err := toM(set)
+		if err != nil {
+			return nil, err
+		}
+		for _, key := range sortedKeys(fields) {
+			replaced := false
+			for i := range doc {
+				if doc[i].Key == key {
+					doc[i].Value = fields[key]
+					replaced = true
+				}
+			}
+			if !replaced {
+				doc = append(doc, bson.E{Key: key, Value: fields[key]})
+			}
+		}
+	}
+	if inc, ok := u["$inc"]; ok {
+		fields, err := toM(inc)
+		if err != nil {
+			return nil, err
+		}
+		for _, key := range sortedKeys(fields) {
+			replaced := false
+			for i := range doc {
+				if doc[i].Key == key {
+					if doc[i].Value, err = addNumbers(doc[i].Value, fields[key]); err != nil {
+						return nil, err
+					}
+					replaced = true
+				}
+			}
+			if !replaced {
+				value, err := addNumbers(int32(0), fields[key])
+				if err != nil {
+					return nil, err
+				}
+				doc = append(doc, bson.E{Key: key, Value: value})
+			}
+		}
+	}
+	if unset, ok := u["$unset"]; ok {
+		fields, err := toM(unset)
+		if err != nil {
+			return nil, err
+		}
+		kept := bson.D{}
+		for _, e := range doc {
+			if _, removed := fields[e.Key]; !removed {
+				kept = append(kept, e)
+			}
+		}
+		doc = kept
+	}
+	return bson.Marshal(doc)
+}
+
+// Fungsi helper untuk menaruh _id sebagai field pertama (seperti yang dilakukan MongoDB)
+func withID(doc bson.D, id bson.RawValue) bson.D {
+	out := bson.D{{Key: "_id", Value: id}}
+	for _, e := range doc {
+		if e.Key != "_id" {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+func rawValue(v interface{}) (bson.RawValue, error) {
+	raw, err := bson.Marshal(bson.D{{Key: "v", Value: v}})
+	if err != nil {
+		return bson.RawValue{}, err
+	}
+	return bson.Raw(raw).Lookup("v"), nil
+}
+
+func toM(v interface{}) (bson.M, error) {
+	switch m := v.(type) {
+	case nil:
+		return bson.M{}, nil
+	case bson.M:
+		return m, nil
+	case map[string]interface{}:
+		return bson.M(m), nil
+	}
+	raw, err := bson.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var m bson.M
+	err = bson.Unmarshal(raw, &m)
+	return m, err
+}
+
+func toD(v interface{}) (bson.D, error) {
+	if d, ok := v.(bson.D); ok {
+		return d, nil
+	}
+	if m, ok := v.(bson.M); ok {
+		d := bson.D{}
+		for _, key := range sortedKeys(m) {
+			d = append(d, bson.E{Key: key, Value: m[key]})
+		}
+		return d, nil
+	}
+	raw, err := bson.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var d bson.D
+	err = bson.Unmarshal(raw, &d)
+	return d, err
+}
+
+func toList(v interface{}) []interface{} {
+	switch l := v.(type) {
+	case bson.A:
+		return l
+	case []interface{}:
+		return l
+	case []bson.M:
+		list := make([]interface{}, 0, len(l))
+		for _, item := range l {
+			list = append(list, item)
+		}
+		return list
+	}
+	return nil
+}
+
+func sortedKeys(m bson.M) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func lookupPath(doc bson.M, path string) interface{} {
+	var current interface{} = doc
+	for _, part := range strings.Split(path, ".") {
+		m, ok := current.(bson.M)
+		if !ok {
+			return nil
+		}
+		current = m[part]
+	}
+	return current
+}
+
+func direction(v interface{}) int {
+	switch n := v.(type) {
+	case int:
+		return n
+	case int32:
+		return int(n)
+	case int64:
+		return int(n)
+	case float64:
+		return int(n)
+	}
+	return 1
+}
+
+// Fungsi helper untuk membandingkan dua nilai saat sort; nilai kosong (field tidak ada) selalu paling kecil
+func compareValues(a, b interface{}) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return -1
+	case b == nil:
+		return 1
+	}
+	if fa, ok := number(a); ok {
+		if fb, ok := number(b); ok {
+			switch {
+			case fa < fb:
+				return -1
+			case fa > fb:
+				return 1
+			}
+			return 0
+		}
+	}
+	switch x := a.(type) {
+	case string:
+		if y, ok := b.(string); ok {
+			return strings.Compare(x, y)
+		}
+	case primitive.DateTime:
+		if y, ok := b.(primitive.DateTime); ok {
+			return compareValues(int64(x), int64(y))
+		}
+	case primitive.ObjectID:
+		if y, ok := b.(primitive.ObjectID); ok {
+			return bytes.Compare(x[:], y[:])
+		}
+	}
+	return 0
+}
+
+// Fungsi helper untuk $inc: hasil tetap bilangan bulat jika kedua nilai bilangan bulat
+func addNumbers(a, b interface{}) (interface{}, error) {
+	x, okA := number(a)
+	y, okB := number(b)
+	if !okA || !okB {
+		return nil, fmt.Errorf("memory collection: cannot $inc non-numeric value %v", a)
+	}
+	_, floatA := a.(float64)
+	_, floatB := b.(float64)
+	if floatA || floatB {
+		return x + y, nil
+	}
+	return int64(x) + int64(y), nil
+}
+
+func number(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
@@ -0,0 +1,148 @@
+// file: repository/memory_collection_test.go
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/fiber-mongo/starter-kit/models" // Sesuaikan dengan path modulmu
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func newTestCollection(t *testing.T, docs ...bson.M) Collection {
+	t.Helper()
+	coll, err := NewMemoryDocuments().Collection(context.Background(), models.Project{ID: primitive.NewObjectID()}, "items")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, doc := range docs {
+		if _, err := coll.InsertOne(context.Background(), doc); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return coll
+}
+
+func names(t *testing.T, cursor *mongo.Cursor) []string {
+	t.Helper()
+	var docs []bson.M
+	if err := cursor.All(context.Background(), &docs); err != nil {
+		t.Fatal(err)
+	}
+	result := []string{}
+	for _, doc := range docs {
+		result = append(result, doc["name"].(string))
+	}
+	return result
+}
+
+func TestMemoryFindFilterSortLimit(t *testing.T) {
+	ctx := context.Background()
+	coll := newTestCollection(t,
+		bson.M{"name": "a", "price": 30},
+		bson.M{"name": "b", "price": 10, "deletedAt": "x"},
+		bson.M{"name": "c", "price": 20},
+		bson.M{"name": "d", "price": 40},
+	)
+
+	cursor, err := coll.Find(ctx, bson.M{"deletedAt": bson.M{"$exists": false}, "price": bson.M{"$gte": 20}},
+		options.Find().SetSort(bson.D{{Key: "price", Value: -1}}).SetSkip(1).SetLimit(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := names(t, cursor); len(got) != 2 || got[0] != "a" || got[1] != "c" {
+		t.Fatalf("got %v, want [a c]", got)
+	}
+
+	cursor, _ = coll.Find(ctx, bson.M{"$or": bson.A{bson.M{"name": "a"}, bson.M{"price": 10}}})
+	if got := names(t, cursor); len(got) != 2 || got[0] != "a" || got[1] != "b" {
+		t.Fatalf("$or: got %v, want [a b]", got)
+	}
+
+	count, _ := coll.CountDocuments(ctx, bson.M{"$and": bson.A{bson.M{"price": bson.M{"$lt": 35}}, bson.M{"name": bson.M{"$ne": "c"}}}})
+	if count != 2 {
+		t.Fatalf("$and count = %d, want 2", count)
+	}
+}
+
+func TestMemoryInsertDuplicateID(t *testing.T) {
+	id := primitive.NewObjectID()
+	coll := newTestCollection(t, bson.M{"_id": id, "name": "a"})
+	_, err := coll.InsertOne(context.Background(), bson.M{"_id": id, "name": "b"})
+	if !mongo.IsDuplicateKeyError(err) {
+		t.Fatalf("err = %v, want duplicate key error", err)
+	}
+}
+
+func TestMemoryFindOneAndUpdateKeepsFieldOrder(t *testing.T) {
+	ctx := context.Background()
+	coll := newTestCollection(t, bson.M{"name": "a"})
+
+	var before, after bson.D
+	err := coll.FindOneAndUpdate(ctx, bson.M{"name": "a"}, bson.M{"$set": bson.M{"price": 5}}).Decode(&before)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = coll.FindOneAndUpdate(ctx, bson.M{"name": "a"},
+		bson.M{"$set": bson.M{"name": "z"}, "$unset": bson.M{"price": ""}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&after)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(before) != 2 || before[1].Key != "name" {
+		t.Fatalf("before = %v, want the document before the first update", before)
+	}
+	if len(after) != 2 || after[0].Key != "_id" || after[1].Value != "z" {
+		t.Fatalf("after = %v, want [_id name=z]", after)
+	}
+
+	err = coll.FindOneAndUpdate(ctx, bson.M{"name": "missing"}, bson.M{"$set": bson.M{"x": 1}}).Err()
+	if err != mongo.ErrNoDocuments {
+		t.Fatalf("err = %v, want ErrNoDocuments", err)
+	}
+}
+
+func TestMemoryExprRootLiteral(t *testing.T) {
+	ctx := context.Background()
+	coll := newTestCollection(t, bson.M{"name": "a"})
+
+	var snapshot bson.Raw
+	if err := coll.FindOne(ctx, bson.M{"name": "a"}).Decode(&snapshot); err != nil {
+		t.Fatal(err)
+	}
+	guarded := bson.M{"name": "a", "$expr": bson.M{"$eq": bson.A{"$$ROOT", bson.M{"$literal": snapshot}}}}
+
+	if n, _ := coll.CountDocuments(ctx, guarded); n != 1 {
+		t.Fatalf("unchanged document should match its snapshot")
+	}
+	coll.FindOneAndUpdate(ctx, bson.M{"name": "a"}, bson.M{"$set": bson.M{"price": 1}})
+	if n, _ := coll.CountDocuments(ctx, guarded); n != 0 {
+		t.Fatalf("changed document must not match the old snapshot")
+	}
+}
+
+func TestMemoryReplaceUpsert(t *testing.T) {
+	ctx := context.Background()
+	coll := newTestCollection(t)
+	id := primitive.NewObjectID()
+
+	result, err := coll.ReplaceOne(ctx, bson.M{"_id": id}, bson.M{"name": "a"}, options.Replace().SetUpsert(true))
+	if err != nil || result.UpsertedID != id {
+		t.Fatalf("upsert = %+v, %v; want document inserted with the _id from the filter", result, err)
+	}
+	result, err = coll.ReplaceOne(ctx, bson.M{"_id": id}, bson.M{"name": "b"})
+	if err != nil || result.MatchedCount != 1 {
+		t.Fatalf("replace = %+v, %v", result, err)
+	}
+
+	var doc bson.M
+	if err := coll.FindOne(ctx, bson.M{"_id": id}).Decode(&doc); err != nil || doc["name"] != "b" {
+		t.Fatalf("doc = %v, %v; want name b", doc, err)
+	}
+}
@@ -0,0 +1,197 @@
+// file: repository/mongo.go
+package repository
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/fiber-mongo/starter-kit/database" // Sesuaikan dengan path modulmu
+	"github.com/fiber-mongo/starter-kit/hooks"    // Sesuaikan dengan path modulmu
+	"github.com/fiber-mongo/starter-kit/models"   // Sesuaikan dengan path modulmu
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Koleksi platform berisi data milik proyek yang ikut dihapus bersama proyeknya
+var projectOwnedCollections = []string{
+	"schema_versions",
+	"jobs",
+	"webhooks",
+	"webhook_deliveries",
+	"collection_hooks",
+	"collection_settings",
+	"rate_limits",
+	"request_quotas",
+	"cors_settings",
+	"usage_stats",
+}
+
+// MongoProjects menyimpan proyek di database platform (database.DB)
+type MongoProjects struct{}
+
+func NewMongoProjects() *MongoProjects {
+	return &MongoProjects{}
+}
+
+func (MongoProjects) List(ctx context.Context) ([]models.Project, error) {
+	cursor, err := database.GetCollection("projects").Find(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	projects := []models.Project{}
+	if err := cursor.All(ctx, &projects); err != nil {
+		return nil, err
+	}
+	return projects, nil
+}
+
+func (r MongoProjects) Get(ctx context.Context, id primitive.ObjectID) (models.Project, error) {
+	return r.findOne(ctx, bson.M{"_id": id})
+}
+
+func (r MongoProjects) GetByAPIKey(ctx context.Context, apiKey string) (models.Project, error) {
+	return r.findOne(ctx, bson.M{"apiKey": apiKey})
+}
+
+func (MongoProjects) findOne(ctx context.Context, filter bson.M) (models.Project, error) {
+	var project models.Project
+	err := database.GetCollection("projects").FindOne(ctx, filter).Decode(&project)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return project, ErrNotFound
+	}
+	return project, err
+}
+
+func (MongoProjects) Create(ctx context.Context, project *models.Project) error {
+	result, err := database.GetCollection("projects").InsertOne(ctx, project)
+	if err != nil {
+		return err
+	}
+	if id, ok := result.InsertedID.(primitive.ObjectID); ok {
+		project.ID = id
+	}
+	return nil
+}
+
+func (MongoProjects) SetActiveCollections(ctx context.Context, id primitive.ObjectID, collections []string) error {
+	result, err := database.GetCollection("projects").UpdateOne(ctx, bson.M{"_id": id},
+		bson.M{"$set": bson.M{"activeCollections": collections}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (MongoProjects) UpdateConnection(ctx context.Context, project models.Project) error {
+	result, err := database.GetCollection("projects").UpdateOne(ctx, bson.M{"_id": project.ID},
+		bson.M{"$set": bson.M{
+			"name":             project.Name,
+			"dbHost":           project.DBHost,
+			"dbPort":           project.DBPort,
+			"dbUser":           project.DBUser,
+			"dbPassword":       project.DBPassword,
+			"dbName":           project.DBName,
+			"authSource":       project.AuthSource,
+			"connectionString": project.ConnectionString,
+			"dbHosts":          project.DBHosts,
+			"replicaSet":       project.ReplicaSet,
+			"readPreference":   project.ReadPreference,
+			"authMechanism":    project.AuthMechanism,
+			"tls":              project.TLS,
+			"tlsCACert":        project.TLSCACert,
+			"tlsClientCert":    project.TLSClientCert,
+		}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (MongoProjects) Delete(ctx context.Context, id primitive.ObjectID) error {
+	result, err := database.GetCollection("projects").DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return ErrNotFound
+	}
+
+	// Proyek sudah terhapus; sisa data yang gagal dibersihkan hanya dicatat di log
+	for _, name := range projectOwnedCollections {
+		if _, err := database.GetCollection(name).DeleteMany(ctx, bson.M{"projectId": id}); err != nil {
+			slog.WarnContext(ctx, "projects: failed to clean up project data", "projectId", id.Hex(), "collection", name, "error", err)
+		}
+	}
+	return nil
+}
+
+func (MongoProjects) CollectionSettings(ctx context.Context, projectID primitive.ObjectID, collectionNames []string) (map[string]models.CollectionSettings, error) {
+	settings := defaultCollectionSettings(projectID, collectionNames)
+	if len(collectionNames) == 0 {
+		return settings, nil
+	}
+
+	cursor, err := database.GetCollection("collection_settings").Find(ctx,
+		bson.M{"projectId": projectID, "collectionName": bson.M{"$in": collectionNames}})
+	if err != nil {
+		return nil, err
+	}
+	var stored []models.CollectionSettings
+	if err := cursor.All(ctx, &stored); err != nil {
+		return nil, err
+	}
+	for _, s := range stored {
+		settings[s.CollectionName] = s
+	}
+	return settings, nil
+}
+
+func (MongoProjects) CollectionHooks(ctx context.Context, project models.Project, collection, event string) ([]models.CollectionHook, error) {
+	return hooks.Load(ctx, project, collection, event)
+}
+
+func defaultCollectionSettings(projectID primitive.ObjectID, collectionNames []string) map[string]models.CollectionSettings {
+	settings := make(map[string]models.CollectionSettings, len(collectionNames))
+	for _, name := range collectionNames {
+		settings[name] = models.CollectionSettings{ProjectID: projectID, CollectionName: name}
+	}
+	return settings
+}
+
+// MongoDocuments membuka koleksi di database user lewat client yang di-cache per proyek
+type MongoDocuments struct{}
+
+func NewMongoDocuments() *MongoDocuments {
+	return &MongoDocuments{}
+}
+
+func (MongoDocuments) Collection(ctx context.Context, project models.Project, name string) (Collection, error) {
+	client, err := database.ProjectClient(ctx, project)
+	if err != nil {
+		return nil, err
+	}
+	return client.Database(project.DBName).Collection(name), nil
+}
+
+func (MongoDocuments) DataSize(ctx context.Context, project models.Project) (int64, error) {
+	client, err := database.ProjectClient(ctx, project)
+	if err != nil {
+		return 0, err
+	}
+	var stats struct {
+		DataSize float64 `bson:"dataSize"`
+	}
+	err = client.Database(project.DBName).RunCommand(ctx, bson.M{"dbStats": 1}).Decode(&stats)
+	return int64(stats.DataSize), err
+}
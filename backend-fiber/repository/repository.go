@@ -0,0 +1,57 @@
+// file: repository/repository.go
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/fiber-mongo/starter-kit/models" // Sesuaikan dengan path modulmu
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrNotFound dikembalikan repository jika data yang dicari tidak ada
+var ErrNotFound = errors.New("not found")
+
+// ProjectRepository menyimpan proyek beserta konfigurasi per koleksi di database platform
+type ProjectRepository interface {
+	List(ctx context.Context) ([]models.Project, error)
+	Get(ctx context.Context, id primitive.ObjectID) (models.Project, error)
+	GetByAPIKey(ctx context.Context, apiKey string) (models.Project, error)
+	// Create menyimpan proyek baru dan mengisi project.ID
+	Create(ctx context.Context, project *models.Project) error
+	SetActiveCollections(ctx context.Context, id primitive.ObjectID, collections []string) error
+	// UpdateConnection menyimpan nama dan detail koneksi database user proyek (field lain tidak diubah)
+	UpdateConnection(ctx context.Context, project models.Project) error
+	// Delete menghapus proyek beserta semua data platform miliknya (webhook, hook, settings, statistik, ...)
+	Delete(ctx context.Context, id primitive.ObjectID) error
+
+	// CollectionSettings membaca settings beberapa koleksi sekaligus. Koleksi tanpa settings mendapat nilai default.
+	CollectionSettings(ctx context.Context, projectID primitive.ObjectID, collectionNames []string) (map[string]models.CollectionSettings, error)
+	// CollectionHooks mengambil hook aktif sebuah koleksi untuk satu event, terurut berdasarkan Order
+	CollectionHooks(ctx context.Context, project models.Project, collection, event string) ([]models.CollectionHook, error)
+}
+
+// Collection adalah bagian dari *mongo.Collection yang dipakai operasi dokumen Data API dan GraphQL.
+// *mongo.Collection memenuhi interface ini secara langsung; implementasi memori ada di NewMemoryDocuments.
+type Collection interface {
+	Name() string
+	Find(ctx context.Context, filter interface{}, opts ...*options.FindOptions) (*mongo.Cursor, error)
+	FindOne(ctx context.Context, filter interface{}, opts ...*options.FindOneOptions) *mongo.SingleResult
+	CountDocuments(ctx context.Context, filter interface{}, opts ...*options.CountOptions) (int64, error)
+	InsertOne(ctx context.Context, document interface{}, opts ...*options.InsertOneOptions) (*mongo.InsertOneResult, error)
+	InsertMany(ctx context.Context, documents []interface{}, opts ...*options.InsertManyOptions) (*mongo.InsertManyResult, error)
+	FindOneAndUpdate(ctx context.Context, filter, update interface{}, opts ...*options.FindOneAndUpdateOptions) *mongo.SingleResult
+	FindOneAndDelete(ctx context.Context, filter interface{}, opts ...*options.FindOneAndDeleteOptions) *mongo.SingleResult
+	ReplaceOne(ctx context.Context, filter, replacement interface{}, opts ...*options.ReplaceOptions) (*mongo.UpdateResult, error)
+	DeleteOne(ctx context.Context, filter interface{}, opts ...*options.DeleteOptions) (*mongo.DeleteResult, error)
+}
+
+// DocumentRepository membuka koleksi di database user sebuah proyek
+type DocumentRepository interface {
+	Collection(ctx context.Context, project models.Project, name string) (Collection, error)
+	// DataSize mengembalikan ukuran data database user dalam byte (untuk kuota storage)
+	DataSize(ctx context.Context, project models.Project) (int64, error)
+}
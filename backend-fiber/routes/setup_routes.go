@@ -5,32 +5,116 @@ import (
 	"github.com/fiber-mongo/starter-kit/controllers" // Sesuaikan nama modul
 	"github.com/fiber-mongo/starter-kit/middleware"  // Sesuaikan nama modul
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
+// Middleware berisi middleware yang menulis ke database platform. Test bisa menggantinya
+// dengan stub lewat SetupRoutesWith supaya rute yang diuji sama dengan rute produksi.
+type Middleware struct {
+	Audit     func(action string) fiber.Handler
+	Usage     fiber.Handler
+	RateLimit fiber.Handler
+}
+
+// DefaultMiddleware mengembalikan middleware yang dipakai server
+func DefaultMiddleware() Middleware {
+	return Middleware{
+		Audit:     middleware.Audit,
+		Usage:     middleware.Usage,
+		RateLimit: middleware.RateLimit,
+	}
+}
+
 func SetupRoutes(app *fiber.App) {
+	SetupRoutesWith(app, DefaultMiddleware())
+}
+
+// SetupRoutesWith mendaftarkan semua rute dengan middleware yang diberikan
+func SetupRoutesWith(app *fiber.App, mw Middleware) {
+	// --- Health check dan metrik Prometheus (di luar /api/v1) ---
+	app.Get("/healthz", controllers.Healthz)
+	app.Get("/readyz", controllers.Readyz)
+	app.Get("/metrics", adaptor.HTTPHandler(promhttp.Handler()))
+
 	api := app.Group("/api/v1")
 
 	// --- Rute Manajemen Platform ---
-	api.Post("/projects", controllers.CreateProject)
+	api.Post("/projects", mw.Audit("project.create"), controllers.CreateProject)
+	api.Post("/projects/test-connection", controllers.TestConnection)
 	api.Get("/projects", controllers.GetAllProjects)
 	api.Get("/projects/:id", controllers.GetOneProject)
-	api.Delete("/projects/:id", controllers.DeleteProject)
-	api.Put("/projects/:id", controllers.UpdateProject)
+	api.Delete("/projects/:id", mw.Audit("project.delete"), controllers.DeleteProject)
+	api.Put("/projects/:id", mw.Audit("project.update"), controllers.UpdateProject)
+	api.Post("/projects/:id/connection/test", mw.Audit("project.connection.test"), controllers.TestProjectConnection)
+	api.Put("/projects/:id/connection", mw.Audit("project.connection.update"), controllers.UpdateProjectConnection)
+	api.Get("/projects/:id/openapi.json", controllers.GetProjectOpenAPI)
+	api.Get("/projects/:id/docs", controllers.GetProjectAPIDocs)
 	api.Get("/projects/:id/collections", controllers.ListCollections)
-	api.Post("/projects/:id/collections", controllers.CreateUserCollection)
-	api.Put("/projects/:id/collections/:collName", controllers.UpdateCollection)
-	api.Delete("/projects/:id/collections/:collName", controllers.DeleteCollection)
+	api.Post("/projects/:id/collections", mw.Audit("collection.create"), controllers.CreateUserCollection)
+	api.Get("/projects/:id/collections/:collName/schema", controllers.GetCollectionSchema)
+	api.Get("/projects/:id/collections/:collName/schema/versions", controllers.GetSchemaVersions)
+	api.Get("/projects/:id/collections/:collName/schema/infer", controllers.InferCollectionSchema)
+	api.Put("/projects/:id/collections/:collName", mw.Audit("collection.update"), controllers.UpdateCollection)
+	api.Delete("/projects/:id/collections/:collName", mw.Audit("collection.delete"), controllers.DeleteCollection)
+	api.Get("/projects/:id/collections/:collName/settings", controllers.GetCollectionSettings)
+	api.Put("/projects/:id/collections/:collName/settings", mw.Audit("collection.settings.update"), controllers.UpdateCollectionSettings)
+	api.Get("/projects/:id/collections/:collName/hooks", controllers.GetCollectionHooks)
+	api.Post("/projects/:id/collections/:collName/hooks", mw.Audit("hook.create"), controllers.CreateCollectionHook)
+	api.Put("/projects/:id/collections/:collName/hooks/:hookId", mw.Audit("hook.update"), controllers.UpdateCollectionHook)
+	api.Delete("/projects/:id/collections/:collName/hooks/:hookId", mw.Audit("hook.delete"), controllers.DeleteCollectionHook)
+	api.Get("/projects/:id/usage", controllers.GetProjectUsage)
+	api.Get("/projects/:id/rate-limits", controllers.GetRateLimits)
+	api.Put("/projects/:id/rate-limits", mw.Audit("ratelimit.update"), controllers.UpdateRateLimits)
+	api.Get("/projects/:id/cors", controllers.GetProjectCORS)
+	api.Put("/projects/:id/cors", mw.Audit("cors.update"), controllers.UpdateProjectCORS)
+	api.Get("/projects/:id/jobs/:jobId", controllers.GetJob)
+	api.Post("/projects/:id/webhooks", mw.Audit("webhook.create"), controllers.CreateWebhook)
+	api.Get("/projects/:id/webhooks", controllers.GetWebhooks)
+	api.Put("/projects/:id/webhooks/:webhookId", mw.Audit("webhook.update"), controllers.UpdateWebhook)
+	api.Delete("/projects/:id/webhooks/:webhookId", mw.Audit("webhook.delete"), controllers.DeleteWebhook)
+	api.Get("/projects/:id/webhooks/:webhookId/deliveries", controllers.GetWebhookDeliveries)
+	api.Post("/projects/:id/webhooks/:webhookId/deliveries/:deliveryId/redeliver", mw.Audit("webhook.redeliver"), controllers.RedeliverWebhook)
+
+	// Audit log aksi manajemen (hanya bisa dibaca)
+	api.Get("/audit-logs", controllers.GetAuditLogs)
+	api.Get("/audit-logs/export", controllers.ExportAuditLogs)
 
 	// Rute untuk menyajikan file (tidak perlu otentikasi)
 	api.Get("/files/:projectId/:collectionName/:docId/:fieldName", controllers.GetFile)
 
 	// --- Rute API Dinamis untuk Data User (Perlu Otentikasi) ---
+	// Middleware dipasang per rute (bukan dataRoutes.Use) karena middleware grup tidak
+	// menerima parameter :projectId/:collectionName yang diperiksa oleh AuthMiddleware.
+	// Usage dan RateLimit dipasang setelah AuthMiddleware karena membutuhkan proyek dari API Key;
+	// Usage sebelum RateLimit supaya request yang ditolak (429) ikut tercatat.
+	// ProjectCORS dipasang paling awal (sebelum AuthMiddleware) dan juga menjawab preflight OPTIONS,
+	// karena browser tidak mengirim API Key saat preflight.
 	dataRoutes := api.Group("/data")
-	dataRoutes.Use(middleware.AuthMiddleware)
 
-	dataRoutes.Get("/:projectId/:collectionName", controllers.GetAllDocuments)
-	dataRoutes.Get("/:projectId/:collectionName/:docId", controllers.GetOneDocument)
-	dataRoutes.Post("/:projectId/:collectionName", controllers.CreateDocument)
-	dataRoutes.Put("/:projectId/:collectionName/:docId", controllers.UpdateDocument)
-	dataRoutes.Delete("/:projectId/:collectionName/:docId", controllers.DeleteDocument)
+	dataRoutes.Options("/:projectId/*", middleware.ProjectCORS)
+
+	dataRoutes.Get("/:projectId/:collectionName", middleware.ProjectCORS, middleware.AuthMiddleware, mw.Usage, mw.RateLimit, controllers.GetAllDocuments)
+	dataRoutes.Get("/:projectId/:collectionName/:docId", middleware.ProjectCORS, middleware.AuthMiddleware, mw.Usage, mw.RateLimit, controllers.GetOneDocument)
+	dataRoutes.Post("/:projectId/:collectionName", middleware.ProjectCORS, middleware.AuthMiddleware, mw.Usage, mw.RateLimit, controllers.CreateDocument)
+	dataRoutes.Put("/:projectId/:collectionName/:docId", middleware.ProjectCORS, middleware.AuthMiddleware, mw.Usage, mw.RateLimit, controllers.UpdateDocument)
+	dataRoutes.Delete("/:projectId/:collectionName/:docId", middleware.ProjectCORS, middleware.AuthMiddleware, mw.Usage, mw.RateLimit, controllers.DeleteDocument)
+	dataRoutes.Post("/:projectId/:collectionName/:docId/restore", middleware.ProjectCORS, middleware.AuthMiddleware, mw.Usage, mw.RateLimit, controllers.RestoreDocument)
+	dataRoutes.Delete("/:projectId/:collectionName/:docId/purge", middleware.ProjectCORS, middleware.AuthMiddleware, mw.Usage, mw.RateLimit, controllers.PurgeDocument)
+	dataRoutes.Get("/:projectId/:collectionName/:docId/revisions", middleware.ProjectCORS, middleware.AuthMiddleware, mw.Usage, mw.RateLimit, controllers.GetDocumentRevisions)
+	dataRoutes.Get("/:projectId/:collectionName/:docId/revisions/diff", middleware.ProjectCORS, middleware.AuthMiddleware, mw.Usage, mw.RateLimit, controllers.DiffDocumentRevisions)
+	dataRoutes.Post("/:projectId/:collectionName/:docId/revisions/:revision/restore", middleware.ProjectCORS, middleware.AuthMiddleware, mw.Usage, mw.RateLimit, controllers.RestoreDocumentRevision)
+
+	// --- GraphQL API untuk Data User (API Key yang sama dengan /data) ---
+	api.Options("/graphql/:projectId", middleware.ProjectCORS)
+	api.Get("/graphql/:projectId", middleware.ProjectCORS, middleware.AuthMiddleware, mw.Usage, mw.RateLimit, controllers.GraphQL)
+	api.Post("/graphql/:projectId", middleware.ProjectCORS, middleware.AuthMiddleware, mw.Usage, mw.RateLimit, controllers.GraphQL)
+
+	// --- Realtime: langganan perubahan koleksi (API Key lewat header X-API-Key atau query ?apiKey=) ---
+	realtimeRoutes := api.Group("/realtime")
+
+	realtimeRoutes.Options("/:projectId/*", middleware.ProjectCORS)
+
+	realtimeRoutes.Get("/:projectId/:collectionName/sse", middleware.ProjectCORS, middleware.APIKeyFromQuery, middleware.AuthMiddleware, mw.Usage, mw.RateLimit, controllers.SubscribeSSE)
+	realtimeRoutes.Get("/:projectId/:collectionName/ws", controllers.RequireWebSocketUpgrade, middleware.APIKeyFromQuery, middleware.AuthMiddleware, mw.Usage, mw.RateLimit, controllers.SubscribeWebSocket)
 }
@@ -0,0 +1,73 @@
+// file: secrets/secrets.go
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"strings"
+
+	"github.com/fiber-mongo/starter-kit/config" // Sesuaikan dengan path modulmu
+)
+
+// Prefix versi format ciphertext, supaya format/kunci bisa diganti tanpa merusak data lama
+const prefix = "v1:"
+
+var (
+	ErrNoKey     = errors.New("ENCRYPTION_KEY is not configured")
+	ErrMalformed = errors.New("encrypted value is malformed")
+)
+
+// Encrypt mengenkripsi data dengan AES-256-GCM memakai ENCRYPTION_KEY.
+// Hasilnya berupa teks "v1:<base64(nonce + ciphertext)>" yang aman disimpan di MongoDB.
+func Encrypt(plaintext []byte) (string, error) {
+	aead, err := newAEAD()
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := aead.Seal(nonce, nonce, plaintext, nil)
+	return prefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt membuka nilai hasil Encrypt
+func Decrypt(value string) ([]byte, error) {
+	encoded, ok := strings.CutPrefix(value, prefix)
+	if !ok {
+		return nil, ErrMalformed
+	}
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrMalformed
+	}
+
+	aead, err := newAEAD()
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return nil, ErrMalformed
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	return aead.Open(nil, nonce, ciphertext, nil)
+}
+
+func newAEAD() (cipher.AEAD, error) {
+	key, err := config.Get().EncryptionKeyBytes()
+	if err != nil {
+		return nil, err
+	}
+	if key == nil {
+		return nil, ErrNoKey
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,52 @@
+// file: secrets/secrets_test.go
+package secrets
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	"github.com/fiber-mongo/starter-kit/config" // Sesuaikan dengan path modulmu
+)
+
+func useKey(t *testing.T, b byte) {
+	t.Helper()
+	cfg := *config.Default()
+	cfg.EncryptionKey = base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{b}, 32))
+	config.Set(&cfg)
+	t.Cleanup(func() { config.Set(nil) })
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	useKey(t, 1)
+	first, err := Encrypt([]byte("mongodb://u:p@h/d"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, _ := Encrypt([]byte("mongodb://u:p@h/d"))
+	if first == second {
+		t.Fatal("encrypting twice must use a fresh nonce")
+	}
+
+	plain, err := Decrypt(first)
+	if err != nil || string(plain) != "mongodb://u:p@h/d" {
+		t.Fatalf("Decrypt = %q, %v", plain, err)
+	}
+
+	useKey(t, 2)
+	if _, err := Decrypt(first); err == nil {
+		t.Fatal("decrypting with another key must fail")
+	}
+	if _, err := Decrypt("plaintext"); !errors.Is(err, ErrMalformed) {
+		t.Fatalf("err = %v, want ErrMalformed", err)
+	}
+}
+
+func TestEncryptWithoutKey(t *testing.T) {
+	config.Set(config.Default())
+	t.Cleanup(func() { config.Set(nil) })
+	if _, err := Encrypt([]byte("x")); !errors.Is(err, ErrNoKey) {
+		t.Fatalf("err = %v, want ErrNoKey", err)
+	}
+}
@@ -0,0 +1,398 @@
+// file: services/document_ops.go
+package services
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/fiber-mongo/starter-kit/apierror"   // Sesuaikan dengan path modulmu
+	"github.com/fiber-mongo/starter-kit/hooks"      // Sesuaikan dengan path modulmu
+	"github.com/fiber-mongo/starter-kit/models"     // Sesuaikan dengan path modulmu
+	"github.com/fiber-mongo/starter-kit/realtime"   // Sesuaikan dengan path modulmu
+	"github.com/fiber-mongo/starter-kit/repository" // Sesuaikan dengan path modulmu
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// DocumentScope adalah target operasi dokumen: satu koleksi user milik satu proyek (lihat DocumentService.Open).
+// Semua penulisan dokumen (REST API dan GraphQL) lewat method di file ini supaya perilakunya sama.
+type DocumentScope struct {
+	Project    models.Project
+	Collection repository.Collection
+	Settings   models.CollectionSettings // Timestamps, soft delete dan revisi
+	Actor      models.Actor
+	// Batas ukuran data proyek dalam byte (0 = tanpa batas). Insert dan Update ditolak jika sudah terlampaui.
+	StorageLimit int64
+
+	revisions repository.Collection
+	service   *DocumentService
+}
+
+// DocumentFilter mengembalikan filter satu dokumen; dokumen yang sudah di-soft delete dianggap tidak ada
+func (s *DocumentScope) DocumentFilter(id interface{}) bson.M {
+	return s.LiveFilter(bson.M{"_id": id})
+}
+
+// LiveFilter menyembunyikan dokumen yang sudah di-soft delete dari sebuah filter
+func (s *DocumentScope) LiveFilter(filter bson.M) bson.M {
+	if !s.Settings.SoftDelete {
+		return filter
+	}
+	live := bson.M{models.FieldDeletedAt: bson.M{"$exists": false}}
+	if len(filter) == 0 {
+		return live
+	}
+	if _, exists := filter[models.FieldDeletedAt]; exists {
+		return bson.M{"$and": bson.A{filter, live}}
+	}
+	merged := bson.M{}
+	for k, v := range filter {
+		merged[k] = v
+	}
+	merged[models.FieldDeletedAt] = live[models.FieldDeletedAt]
+	return merged
+}
+
+// Field yang dikelola server dan tidak boleh ditulis langsung oleh client
+func (s *DocumentScope) protectedFields() []string {
+	fields := []string{}
+	if s.Settings.Timestamps {
+		fields = append(fields, models.FieldCreatedAt, models.FieldUpdatedAt, models.FieldCreatedBy)
+	}
+	if s.Settings.SoftDelete {
+		fields = append(fields, models.FieldDeletedAt, models.FieldDeletedBy)
+	}
+	return fields
+}
+
+// Fungsi helper yang dipanggil setelah penulisan berhasil
+func (s *DocumentScope) changed(ctx context.Context, operationType string, documentID interface{}, before, after bson.M) {
+	s.service.events.DocumentChanged(ctx, s.Project, s.Collection.Name(), operationType, documentID, before, after)
+}
+
+// Fungsi helper untuk menolak penulisan saat ukuran data proyek sudah mencapai StorageLimit
+func (s *DocumentScope) checkStorage(ctx context.Context) error {
+	if s.service.StorageQuotaExceeded(ctx, s.Project, s.StorageLimit) {
+		return apierror.New(http.StatusInsufficientStorage, apierror.CodeStorageQuotaExceeded, "Storage quota exceeded")
+	}
+	return nil
+}
+
+// Fungsi helper untuk mengambil hook aktif koleksi ini untuk satu event
+func (s *DocumentScope) loadHooks(ctx context.Context, event string) ([]models.CollectionHook, error) {
+	return s.service.projects.CollectionHooks(ctx, s.Project, s.Collection.Name(), event)
+}
+
+// Fungsi helper untuk menjalankan hook "after" dan menyimpan perubahan yang dihasilkannya.
+// Penulisan utama sudah berhasil, jadi kegagalan hook hanya dicatat di log. Mengembalikan dokumen terbaru.
+// after nil berarti dokumen sudah dihapus permanen: hook membaca isi terakhirnya (before), perubahannya tidak disimpan.
+func (s *DocumentScope) runAfterHooks(ctx context.Context, list []models.CollectionHook, event string, before, after bson.M) bson.M {
+	afterHooks := hooks.Filter(list, models.HookAfter)
+	document := after
+	if document == nil {
+		document = before
+	}
+	if len(afterHooks) == 0 || document == nil {
+		return after
+	}
+
+	in := &hooks.Input{Event: event, Collection: s.Collection.Name(), Document: document, Before: before, Changes: bson.M{}}
+	if err := hooks.Run(ctx, afterHooks, in); err != nil {
+		slog.WarnContext(ctx, "hooks: after hook failed", "event", event, "projectId", s.Project.ID.Hex(), "collection", s.Collection.Name(), "error", err)
+		return after
+	}
+	update := hookUpdate(in.Changes, in.Unset)
+	if len(update) == 0 {
+		return after
+	}
+	if after == nil {
+		slog.WarnContext(ctx, "hooks: after hook changes ignored, document was deleted permanently", "event", event, "projectId", s.Project.ID.Hex(), "collection", s.Collection.Name())
+		return after
+	}
+
+	var updated bson.M
+	err := s.Collection.FindOneAndUpdate(ctx, bson.M{"_id": after["_id"]}, update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&updated)
+	if err != nil {
+		slog.ErrorContext(ctx, "hooks: failed to save after hook changes", "event", event, "projectId", s.Project.ID.Hex(), "collection", s.Collection.Name(), "error", err)
+		return after
+	}
+	return updated
+}
+
+// Fungsi helper untuk menyusun dokumen update ($set/$unset) dari hasil hook
+func hookUpdate(set bson.M, unset []string) bson.M {
+	update := bson.M{}
+	if len(set) > 0 {
+		update["$set"] = set
+	}
+	if len(unset) > 0 {
+		fields := bson.M{}
+		for _, field := range unset {
+			fields[field] = ""
+		}
+		update["$unset"] = fields
+	}
+	return update
+}
+
+// Insert menyimpan dokumen baru. Mengembalikan _id dokumen yang dibuat.
+// Hook "before" bisa mengubah atau menolak dokumen (*hooks.Error).
+func (s *DocumentScope) Insert(ctx context.Context, doc bson.M) (interface{}, error) {
+	if err := s.checkStorage(ctx); err != nil {
+		return nil, err
+	}
+	list, err := s.loadHooks(ctx, models.DocumentEventCreate)
+	if err != nil {
+		return nil, err
+	}
+	in := &hooks.Input{Event: models.DocumentEventCreate, Collection: s.Collection.Name(), Changes: doc}
+	if err := hooks.Run(ctx, hooks.Filter(list, models.HookBefore), in); err != nil {
+		return nil, err
+	}
+	doc = in.Changes
+
+	for _, field := range s.protectedFields() {
+		delete(doc, field)
+	}
+	if s.Settings.Timestamps {
+		now := time.Now()
+		doc[models.FieldCreatedAt] = now
+		doc[models.FieldUpdatedAt] = now
+		doc[models.FieldCreatedBy] = s.Actor.String()
+	}
+
+	result, err := s.Collection.InsertOne(ctx, doc)
+	if err != nil {
+		return nil, err
+	}
+
+	created := bson.M{"_id": result.InsertedID}
+	for k, v := range doc {
+		created[k] = v
+	}
+	created = s.runAfterHooks(ctx, list, models.DocumentEventCreate, nil, created)
+	s.recordRevision(ctx, models.DocumentEventCreate, result.InsertedID, nil, created)
+	s.changed(ctx, realtime.OperationInsert, result.InsertedID, nil, created)
+	return result.InsertedID, nil
+}
+
+// Fungsi helper untuk membaca dokumen yang akan ditulis dan memeriksa If-Match (ifMatch kosong = tanpa syarat).
+// Mengembalikan isi dokumen saat ini (jika dibaca) dan filter untuk penulisan; dengan If-Match, filter hanya cocok
+// selama dokumen belum diubah request lain. Mengembalikan ErrPreconditionFailed jika ETag tidak cocok.
+func (s *DocumentScope) loadForWrite(ctx context.Context, id primitive.ObjectID, ifMatch string, needCurrent bool) (bson.M, bson.M, error) {
+	filter := s.DocumentFilter(id)
+	if ifMatch == "" && !needCurrent {
+		return nil, filter, nil
+	}
+
+	var raw bson.Raw
+	if err := s.Collection.FindOne(ctx, filter).Decode(&raw); err != nil {
+		return nil, nil, err
+	}
+	if ifMatch != "" {
+		if !ETagMatches(ifMatch, DocumentETag(raw)) {
+			return nil, nil, ErrPreconditionFailed
+		}
+		filter = unchangedFilter(filter, raw)
+	}
+
+	var current bson.M
+	if err := bson.Unmarshal(raw, &current); err != nil {
+		return nil, nil, err
+	}
+	return current, filter, nil
+}
+
+// Update meng-update sebagian field dokumen ($set). Mengembalikan false jika dokumen tidak ditemukan.
+// Hook "before" bisa mengubah field yang ditulis, menghapus field, atau menolak update (*hooks.Error).
+// Jika ifMatch diisi, update hanya dijalankan bila ETag dokumen cocok (ErrPreconditionFailed jika tidak).
+func (s *DocumentScope) Update(ctx context.Context, id primitive.ObjectID, set bson.M, ifMatch string) (bool, error) {
+	if err := s.checkStorage(ctx); err != nil {
+		return false, err
+	}
+	list, err := s.loadHooks(ctx, models.DocumentEventUpdate)
+	if err != nil {
+		return false, err
+	}
+	beforeHooks := hooks.Filter(list, models.HookBefore)
+
+	current, filter, err := s.loadForWrite(ctx, id, ifMatch, len(beforeHooks) > 0)
+	if err == mongo.ErrNoDocuments {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	var unset []string
+	if len(beforeHooks) > 0 {
+		in := &hooks.Input{Event: models.DocumentEventUpdate, Collection: s.Collection.Name(), Document: current, Before: current, Changes: set}
+		if err := hooks.Run(ctx, beforeHooks, in); err != nil {
+			return false, err
+		}
+		set, unset = in.Changes, in.Unset
+	}
+
+	for _, field := range s.protectedFields() {
+		delete(set, field)
+		unset = removeString(unset, field)
+	}
+	if s.Settings.Timestamps {
+		if set == nil {
+			set = bson.M{}
+		}
+		set[models.FieldUpdatedAt] = time.Now()
+	}
+
+	update := hookUpdate(set, unset)
+	if len(update) == 0 {
+		// Tidak ada yang ditulis ($set kosong ditolak MongoDB sebelum 5.0): cukup pastikan dokumen
+		// (dan ETag-nya, jika ifMatch diisi) masih cocok. Tanpa perubahan, revisi dan event tidak dicatat.
+		err := s.Collection.FindOne(ctx, filter).Err()
+		if err == mongo.ErrNoDocuments {
+			if ifMatch != "" {
+				return false, ErrPreconditionFailed
+			}
+			return false, nil
+		}
+		return err == nil, err
+	}
+
+	var before bson.M
+	err = s.Collection.FindOneAndUpdate(ctx, filter, update,
+		options.FindOneAndUpdate().SetReturnDocument(options.Before),
+	).Decode(&before)
+	if err == mongo.ErrNoDocuments {
+		if ifMatch != "" {
+			return false, ErrPreconditionFailed // Diubah/dihapus request lain setelah dibaca
+		}
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	// Update sudah tersimpan; jika pembacaan ulang gagal, event tetap dikirim dengan "after" kosong
+	var after bson.M
+	s.Collection.FindOne(ctx, bson.M{"_id": id}).Decode(&after)
+	after = s.runAfterHooks(ctx, list, models.DocumentEventUpdate, before, after)
+	s.recordRevision(ctx, models.DocumentEventUpdate, id, before, after)
+	s.changed(ctx, realtime.OperationUpdate, id, before, after)
+	return true, nil
+}
+
+// Delete menghapus dokumen. Mengembalikan false jika dokumen tidak ditemukan.
+// Jika soft delete aktif, dokumen hanya ditandai dengan deletedAt. Hook "before" bisa menolak penghapusan (*hooks.Error);
+// hook "after" membaca dokumen yang dihapus dan (hanya dengan soft delete) bisa mengubahnya.
+// Jika ifMatch diisi, dokumen hanya dihapus bila ETag-nya cocok (ErrPreconditionFailed jika tidak).
+func (s *DocumentScope) Delete(ctx context.Context, id primitive.ObjectID, ifMatch string) (bool, error) {
+	list, err := s.loadHooks(ctx, models.DocumentEventDelete)
+	if err != nil {
+		return false, err
+	}
+	beforeHooks := hooks.Filter(list, models.HookBefore)
+
+	current, filter, err := s.loadForWrite(ctx, id, ifMatch, len(beforeHooks) > 0)
+	if err == mongo.ErrNoDocuments {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if len(beforeHooks) > 0 {
+		in := &hooks.Input{Event: models.DocumentEventDelete, Collection: s.Collection.Name(), Document: current, Before: current}
+		if err := hooks.Run(ctx, beforeHooks, in); err != nil {
+			return false, err
+		}
+	}
+
+	var before bson.M
+	if s.Settings.SoftDelete {
+		err = s.Collection.FindOneAndUpdate(ctx, filter, bson.M{"$set": bson.M{
+			models.FieldDeletedAt: time.Now(),
+			models.FieldDeletedBy: s.Actor.String(),
+		}}).Decode(&before)
+	} else {
+		err = s.Collection.FindOneAndDelete(ctx, filter).Decode(&before)
+	}
+	if err == mongo.ErrNoDocuments {
+		if ifMatch != "" {
+			return false, ErrPreconditionFailed
+		}
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	var after bson.M
+	if s.Settings.SoftDelete {
+		// Dokumen masih ada (dengan deletedAt); jika pembacaan ulang gagal, hook memakai isi sebelum dihapus
+		s.Collection.FindOne(ctx, bson.M{"_id": id}).Decode(&after)
+	}
+	s.runAfterHooks(ctx, list, models.DocumentEventDelete, before, after)
+
+	s.recordRevision(ctx, models.DocumentEventDelete, id, nil, before)
+	s.changed(ctx, realtime.OperationDelete, id, before, nil)
+	return true, nil
+}
+
+// Restore memulihkan dokumen yang sudah di-soft delete. Mengembalikan false jika tidak ada dokumen terhapus dengan id tersebut.
+func (s *DocumentScope) Restore(ctx context.Context, id primitive.ObjectID) (bool, error) {
+	update := bson.M{"$unset": bson.M{models.FieldDeletedAt: "", models.FieldDeletedBy: ""}}
+	if s.Settings.Timestamps {
+		update["$set"] = bson.M{models.FieldUpdatedAt: time.Now()}
+	}
+
+	var before bson.M
+	err := s.Collection.FindOneAndUpdate(ctx,
+		bson.M{"_id": id, models.FieldDeletedAt: bson.M{"$exists": true}}, update,
+	).Decode(&before)
+	if err == mongo.ErrNoDocuments {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	var after bson.M
+	s.Collection.FindOne(ctx, bson.M{"_id": id}).Decode(&after)
+	s.recordRevision(ctx, models.RevisionRestore, id, before, after)
+	s.changed(ctx, realtime.OperationUpdate, id, before, after)
+	return true, nil
+}
+
+// Purge menghapus permanen dokumen yang sudah di-soft delete. Hook delete sudah dijalankan saat soft delete,
+// jadi tidak dijalankan lagi; revisi "purge" dan event delete tetap dicatat seperti Delete.
+func (s *DocumentScope) Purge(ctx context.Context, id primitive.ObjectID) (bool, error) {
+	var before bson.M
+	err := s.Collection.FindOneAndDelete(ctx, bson.M{"_id": id, models.FieldDeletedAt: bson.M{"$exists": true}}).Decode(&before)
+	if err == mongo.ErrNoDocuments {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	s.recordRevision(ctx, models.RevisionPurge, id, nil, before)
+	s.changed(ctx, realtime.OperationDelete, id, before, nil)
+	return true, nil
+}
+
+func removeString(values []string, value string) []string {
+	kept := []string{}
+	for _, v := range values {
+		if v != value {
+			kept = append(kept, v)
+		}
+	}
+	return kept
+}
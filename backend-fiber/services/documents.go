@@ -0,0 +1,128 @@
+// file: services/documents.go
+package services
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/fiber-mongo/starter-kit/apierror"   // Sesuaikan dengan path modulmu
+	"github.com/fiber-mongo/starter-kit/models"     // Sesuaikan dengan path modulmu
+	"github.com/fiber-mongo/starter-kit/ratelimit"  // Sesuaikan dengan path modulmu
+	"github.com/fiber-mongo/starter-kit/realtime"   // Sesuaikan dengan path modulmu
+	"github.com/fiber-mongo/starter-kit/repository" // Sesuaikan dengan path modulmu
+	"github.com/fiber-mongo/starter-kit/webhooks"   // Sesuaikan dengan path modulmu
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// EventSink menerima perubahan dokumen yang sudah tersimpan (untuk realtime dan webhook)
+type EventSink interface {
+	DocumentChanged(ctx context.Context, project models.Project, collection, operationType string, documentID interface{}, before, after bson.M)
+}
+
+// Operasi realtime -> nama event webhook
+var webhookEvents = map[string]string{
+	realtime.OperationInsert: models.DocumentEventCreate,
+	realtime.OperationUpdate: models.DocumentEventUpdate,
+	realtime.OperationDelete: models.DocumentEventDelete,
+}
+
+// PlatformEvents mengirim event ke event bus realtime dan memasukkan pengiriman webhook ke antrian.
+// Kegagalan di sini tidak membatalkan penulisan, hanya dicatat di log.
+type PlatformEvents struct{}
+
+func (PlatformEvents) DocumentChanged(ctx context.Context, project models.Project, collection, operationType string, documentID interface{}, before, after bson.M) {
+	realtime.Publish(realtime.Event{
+		ProjectID:     project.ID.Hex(),
+		Collection:    collection,
+		OperationType: operationType,
+		DocumentID:    documentID,
+		FullDocument:  after,
+		Timestamp:     time.Now(),
+	})
+
+	if err := webhooks.Enqueue(ctx, project.ID, collection, webhookEvents[operationType], documentID, before, after); err != nil {
+		slog.ErrorContext(ctx, "webhooks: failed to enqueue event", "event", operationType, "projectId", project.ID.Hex(), "collection", collection, "error", err)
+	}
+}
+
+// DocumentService membuka koleksi user sebuah proyek beserta settings-nya untuk operasi dokumen
+type DocumentService struct {
+	projects  repository.ProjectRepository
+	documents repository.DocumentRepository
+	events    EventSink
+}
+
+func NewDocumentService(projects repository.ProjectRepository, documents repository.DocumentRepository, events EventSink) *DocumentService {
+	return &DocumentService{projects: projects, documents: documents, events: events}
+}
+
+// Open menyiapkan DocumentScope lengkap (settings koleksi dan actor) untuk satu koleksi
+func (s *DocumentService) Open(ctx context.Context, project models.Project, collectionName string, actor models.Actor) (*DocumentScope, error) {
+	scopes, err := s.OpenAll(ctx, project, []string{collectionName}, actor)
+	if err != nil {
+		return nil, err
+	}
+	return scopes[0], nil
+}
+
+// OpenAll seperti Open untuk beberapa koleksi sekaligus (settings dibaca dalam satu query)
+func (s *DocumentService) OpenAll(ctx context.Context, project models.Project, collectionNames []string, actor models.Actor) ([]*DocumentScope, error) {
+	settings, err := s.Settings(ctx, project.ID, collectionNames)
+	if err != nil {
+		return nil, err
+	}
+
+	scopes := make([]*DocumentScope, 0, len(collectionNames))
+	for _, name := range collectionNames {
+		collection, err := s.documents.Collection(ctx, project, name)
+		if err != nil {
+			return nil, apierror.Internal(err, "Could not connect to user database")
+		}
+		revisions, err := s.documents.Collection(ctx, project, name+models.RevisionCollectionSuffix)
+		if err != nil {
+			return nil, apierror.Internal(err, "Could not connect to user database")
+		}
+		scopes = append(scopes, &DocumentScope{
+			Project:    project,
+			Collection: collection,
+			Settings:   settings[name],
+			Actor:      actor,
+			revisions:  revisions,
+			service:    s,
+		})
+	}
+	return scopes, nil
+}
+
+// Settings membaca settings beberapa koleksi. Koleksi tanpa settings mendapat nilai default.
+func (s *DocumentService) Settings(ctx context.Context, projectID primitive.ObjectID, collectionNames []string) (map[string]models.CollectionSettings, error) {
+	settings, err := s.projects.CollectionSettings(ctx, projectID, collectionNames)
+	if err != nil {
+		return nil, apierror.Internal(err, "Failed to read collection settings")
+	}
+	return settings, nil
+}
+
+// DataSize mengembalikan ukuran data database user sebuah proyek (untuk kuota storage)
+func (s *DocumentService) DataSize(ctx context.Context, project models.Project) (int64, error) {
+	return s.documents.DataSize(ctx, project)
+}
+
+// StorageQuotaExceeded memeriksa apakah ukuran data proyek sudah mencapai limit (byte, 0 = tanpa batas).
+// Ukuran diambil dari cache ratelimit; jika gagal diukur, penulisan tetap diizinkan.
+func (s *DocumentService) StorageQuotaExceeded(ctx context.Context, project models.Project, limit int64) bool {
+	if limit <= 0 {
+		return false
+	}
+	used, err := ratelimit.StorageUsage(ctx, project.ID, func(ctx context.Context) (int64, error) {
+		return s.DataSize(ctx, project)
+	})
+	if err != nil {
+		slog.WarnContext(ctx, "ratelimit: failed to read storage size", "projectId", project.ID.Hex(), "error", err)
+		return false
+	}
+	return used >= limit
+}
@@ -0,0 +1,54 @@
+// file: services/etag.go
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// ErrPreconditionFailed dikembalikan saat header If-Match tidak cocok dengan versi dokumen saat ini (HTTP 412)
+var ErrPreconditionFailed = errors.New("document has been modified")
+
+// DocumentETag menghitung ETag (strong) dari isi BSON dokumen seperti yang tersimpan di database
+func DocumentETag(raw bson.Raw) string {
+	sum := sha256.Sum256(raw)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// ListETag menghitung ETag sebuah daftar dokumen (berubah jika ada dokumen yang berubah, bertambah atau berkurang)
+func ListETag(docs []bson.Raw) string {
+	hash := sha256.New()
+	for _, doc := range docs {
+		hash.Write(doc)
+	}
+	return `W/"` + hex.EncodeToString(hash.Sum(nil)[:16]) + `"`
+}
+
+// ETagMatches mencocokkan header If-Match / If-None-Match (bisa berisi beberapa ETag atau "*")
+func ETagMatches(header, etag string) bool {
+	normalize := func(tag string) string {
+		return strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+	}
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || normalize(candidate) == normalize(etag) {
+			return true
+		}
+	}
+	return false
+}
+
+// Fungsi helper untuk menambahkan syarat "dokumen masih persis sama dengan snapshot" ke sebuah filter,
+// supaya pengecekan If-Match dan penulisan terjadi secara atomik
+func unchangedFilter(filter bson.M, snapshot bson.Raw) bson.M {
+	guarded := bson.M{}
+	for k, v := range filter {
+		guarded[k] = v
+	}
+	guarded["$expr"] = bson.M{"$eq": bson.A{"$$ROOT", bson.M{"$literal": snapshot}}}
+	return guarded
+}
@@ -0,0 +1,395 @@
+// file: services/projects.go
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/fiber-mongo/starter-kit/apierror"   // Sesuaikan dengan path modulmu
+	"github.com/fiber-mongo/starter-kit/database"   // Sesuaikan dengan path modulmu
+	"github.com/fiber-mongo/starter-kit/models"     // Sesuaikan dengan path modulmu
+	"github.com/fiber-mongo/starter-kit/ratelimit"  // Sesuaikan dengan path modulmu
+	"github.com/fiber-mongo/starter-kit/repository" // Sesuaikan dengan path modulmu
+	"github.com/fiber-mongo/starter-kit/secrets"    // Sesuaikan dengan path modulmu
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// ConnectionChecker memastikan database user bisa dihubungi dengan detail koneksi tersebut.
+// Error yang dikembalikan sebaiknya *apierror.Error supaya client mendapat pesan yang jelas.
+type ConnectionChecker func(ctx context.Context, conn database.ProjectConnection) error
+
+// PingMongo adalah ConnectionChecker yang membuka koneksi sementara ke MongoDB dan menjalankan ping
+func PingMongo(ctx context.Context, conn database.ProjectConnection) error {
+	client, err := mongo.Connect(ctx, conn.ClientOptions())
+	if err != nil {
+		return apierror.Validation("", "Failed to build valid MongoDB URI from provided details.").Wrap(err)
+	}
+	defer client.Disconnect(ctx)
+
+	if err := client.Ping(ctx, nil); err != nil {
+		return apierror.New(fiber.StatusServiceUnavailable, apierror.CodeConnectionFailed, "Failed to connect. Check your connection details and firewall.").Wrap(err)
+	}
+	return nil
+}
+
+// ProjectService berisi aturan pengelolaan proyek (koneksi database user dan API Key)
+type ProjectService struct {
+	repo            repository.ProjectRepository
+	checkConnection ConnectionChecker
+}
+
+func NewProjectService(repo repository.ProjectRepository, checkConnection ConnectionChecker) *ProjectService {
+	return &ProjectService{repo: repo, checkConnection: checkConnection}
+}
+
+func (s *ProjectService) List(ctx context.Context) ([]models.Project, error) {
+	projects, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, apierror.Internal(err, "Failed to fetch projects")
+	}
+	if projects == nil {
+		projects = []models.Project{}
+	}
+	return projects, nil
+}
+
+func (s *ProjectService) Get(ctx context.Context, id primitive.ObjectID) (models.Project, error) {
+	project, err := s.repo.Get(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return project, apierror.ProjectNotFound()
+	}
+	if err != nil {
+		return project, apierror.Internal(err, "Failed to read project")
+	}
+	return project, nil
+}
+
+// GetByAPIKey mencari proyek pemilik API Key (untuk otentikasi Data API)
+func (s *ProjectService) GetByAPIKey(ctx context.Context, apiKey string) (models.Project, error) {
+	project, err := s.repo.GetByAPIKey(ctx, apiKey)
+	if errors.Is(err, repository.ErrNotFound) {
+		return project, apierror.New(fiber.StatusUnauthorized, apierror.CodeInvalidAPIKey, "Unauthorized: Invalid API Key")
+	}
+	if err != nil {
+		return project, apierror.Internal(err, "Failed to verify API Key")
+	}
+	return project, nil
+}
+
+// Create memeriksa koneksi ke database user, membuat API Key, lalu menyimpan proyek baru
+func (s *ProjectService) Create(ctx context.Context, input models.ProjectInput) (models.Project, error) {
+	project := models.Project{
+		Name:      input.Name,
+		CreatedAt: time.Now(),
+	}
+	plain, err := applyConnection(&project, input)
+	if err != nil {
+		return project, err
+	}
+	if err := s.check(ctx, project, plain); err != nil {
+		return project, err
+	}
+	if err := sealSecrets(&project, plain); err != nil {
+		return project, err
+	}
+
+	// Generate API Key unik untuk proyek ini
+	apiKey, err := generateAPIKey()
+	if err != nil {
+		return project, apierror.Internal(err, "Failed to generate API Key")
+	}
+	project.ApiKey = apiKey
+
+	if err := s.repo.Create(ctx, &project); err != nil {
+		return project, apierror.Internal(err, "Failed to save the project")
+	}
+	return project, nil
+}
+
+// TestConnection memeriksa detail koneksi tanpa menyimpan apa pun
+func (s *ProjectService) TestConnection(ctx context.Context, input models.ProjectInput) error {
+	var project models.Project
+	plain, err := applyConnection(&project, input)
+	if err != nil {
+		return err
+	}
+	return s.check(ctx, project, plain)
+}
+
+// TestProjectConnection memeriksa detail koneksi baru untuk proyek yang sudah ada tanpa menyimpannya.
+// Field yang dikosongkan memakai nilai yang tersimpan (lihat UpdateConnection).
+func (s *ProjectService) TestProjectConnection(ctx context.Context, id primitive.ObjectID, input models.ProjectInput) error {
+	existing, err := s.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	input, err = mergeConnectionInput(existing, input)
+	if err != nil {
+		return err
+	}
+	project := existing
+	plain, err := applyConnection(&project, input)
+	if err != nil {
+		return err
+	}
+	return s.check(ctx, project, plain)
+}
+
+// UpdateConnection mengganti detail koneksi database user sebuah proyek setelah koneksinya berhasil diperiksa.
+// Password, connection string dan sertifikat yang dikosongkan tetap memakai nilai lama.
+func (s *ProjectService) UpdateConnection(ctx context.Context, id primitive.ObjectID, input models.ProjectInput) (models.Project, error) {
+	existing, err := s.Get(ctx, id)
+	if err != nil {
+		return existing, err
+	}
+	input, err = mergeConnectionInput(existing, input)
+	if err != nil {
+		return existing, err
+	}
+
+	project := existing
+	plain, err := applyConnection(&project, input)
+	if err != nil {
+		return existing, err
+	}
+	if err := s.check(ctx, project, plain); err != nil {
+		return existing, err
+	}
+	if err := sealSecrets(&project, plain); err != nil {
+		return existing, err
+	}
+
+	err = s.repo.UpdateConnection(ctx, project)
+	if errors.Is(err, repository.ErrNotFound) {
+		return existing, apierror.ProjectNotFound()
+	}
+	if err != nil {
+		return existing, apierror.Internal(err, "Failed to update the connection settings")
+	}
+
+	// Client lama masih terhubung ke database sebelumnya
+	database.InvalidateUserClient(id.Hex())
+	return project, nil
+}
+
+func (s *ProjectService) SetActiveCollections(ctx context.Context, id primitive.ObjectID, collections []string) error {
+	err := s.repo.SetActiveCollections(ctx, id, collections)
+	if errors.Is(err, repository.ErrNotFound) {
+		return apierror.ProjectNotFound()
+	}
+	if err != nil {
+		return apierror.Internal(err, "Failed to update project settings")
+	}
+	return nil
+}
+
+// Delete menghapus proyek beserta data platform miliknya dan menutup client database user-nya
+func (s *ProjectService) Delete(ctx context.Context, id primitive.ObjectID) error {
+	err := s.repo.Delete(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return apierror.ProjectNotFound()
+	}
+	if err != nil {
+		return apierror.Internal(err, "Failed to delete project")
+	}
+
+	ratelimit.Invalidate(id)
+	database.InvalidateUserClient(id.Hex())
+	return nil
+}
+
+// Fungsi helper untuk memvalidasi input koneksi lalu mengisinya ke proyek.
+// Password, connection string dan sertifikat dikembalikan apa adanya; enkripsi dilakukan oleh sealSecrets sebelum disimpan.
+func applyConnection(project *models.Project, input models.ProjectInput) (database.ConnectionSecrets, error) {
+	var plain database.ConnectionSecrets
+
+	if input.DBPort == "" {
+		input.DBPort = "27017"
+	}
+	if input.AuthSource == "" {
+		input.AuthSource = "admin"
+	}
+
+	if input.AuthMechanism != "" && !slices.Contains(database.AuthMechanisms, input.AuthMechanism) {
+		return plain, apierror.Validation("authMechanism", "authMechanism must be one of "+strings.Join(database.AuthMechanisms, ", "))
+	}
+	if input.ReadPreference != "" && !slices.Contains(database.ReadPreferences, input.ReadPreference) {
+		return plain, apierror.Validation("readPreference", "readPreference must be one of "+strings.Join(database.ReadPreferences, ", "))
+	}
+	hosts := []string{}
+	for _, host := range input.DBHosts {
+		host = strings.TrimSpace(host)
+		if host == "" {
+			continue
+		}
+		if strings.ContainsAny(host, "/@,?# ") {
+			return plain, apierror.Validation("dbHosts", "dbHosts entries must be host or host:port")
+		}
+		hosts = append(hosts, host)
+	}
+
+	project.DBHost = input.DBHost
+	project.DBPort = input.DBPort
+	project.DBUser = input.DBUser
+	project.DBPassword = "" // Diisi sealSecrets dalam bentuk terenkripsi
+	plain.DBPassword = input.DBPassword
+	project.DBName = input.DBName
+	project.AuthSource = input.AuthSource
+	project.DBHosts = hosts
+	project.ReplicaSet = input.ReplicaSet
+	project.ReadPreference = input.ReadPreference
+	project.AuthMechanism = input.AuthMechanism
+	project.TLS = input.TLS
+	project.ConnectionString, project.TLSCACert, project.TLSClientCert = "", "", ""
+
+	if uri := strings.TrimSpace(input.ConnectionString); uri != "" {
+		parsed, err := url.Parse(uri)
+		if err != nil || (parsed.Scheme != "mongodb" && parsed.Scheme != "mongodb+srv") || parsed.Host == "" {
+			return plain, apierror.Validation("connectionString", "connectionString must start with mongodb:// or mongodb+srv://")
+		}
+		// Nama database boleh diambil dari path connection string
+		if project.DBName == "" {
+			project.DBName = strings.TrimPrefix(parsed.Path, "/")
+		}
+		// Host disimpan terpisah (tanpa kredensial) supaya tetap bisa ditampilkan di dashboard
+		project.DBHost, project.DBPort, project.DBUser, plain.DBPassword = parsed.Host, "", "", ""
+		plain.ConnectionString = uri
+	}
+	if project.DBName == "" {
+		return plain, apierror.Validation("dbName", "dbName is required")
+	}
+
+	if pem := strings.TrimSpace(input.TLSCACert); pem != "" {
+		if !x509.NewCertPool().AppendCertsFromPEM([]byte(pem)) {
+			return plain, apierror.Validation("tlsCACert", "tlsCACert must be a PEM encoded certificate")
+		}
+		plain.TLSCACert, project.TLS = pem, true
+	}
+	if pem := strings.TrimSpace(input.TLSClientCert); pem != "" {
+		if _, err := tls.X509KeyPair([]byte(pem), []byte(pem)); err != nil {
+			return plain, apierror.Validation("tlsClientCert", "tlsClientCert must contain a PEM certificate and its private key")
+		}
+		plain.TLSClientCert, project.TLS = pem, true
+	}
+	return plain, nil
+}
+
+// Fungsi helper untuk melengkapi input pembaruan koneksi dengan nilai yang tersimpan di proyek.
+// Password kosong berarti tidak diubah; connection string lama dipakai jika host juga tidak diisi;
+// sertifikat lama tetap dipakai kecuali removeTlsCertificates diisi.
+func mergeConnectionInput(existing models.Project, input models.ProjectInput) (models.ProjectInput, error) {
+	stored, err := database.DecryptSecrets(existing)
+	if err != nil {
+		return input, apierror.Internal(err, "Failed to decrypt the stored connection details")
+	}
+
+	if input.Name == "" {
+		input.Name = existing.Name
+	}
+	if input.DBName == "" {
+		input.DBName = existing.DBName
+	}
+	if input.ConnectionString == "" && input.DBHost == "" && len(input.DBHosts) == 0 {
+		input.ConnectionString = stored.ConnectionString
+	}
+	// Password hanya dipertahankan untuk user yang sama di server yang sama, supaya password lama
+	// tidak bisa dikirim ke host lain (atau lewat mekanisme auth lain) hanya dengan mengganti detail koneksi
+	if input.DBPassword == "" && input.ConnectionString == "" && input.DBUser == existing.DBUser && sameServer(existing, input) {
+		input.DBPassword = stored.DBPassword
+	}
+	if !input.RemoveTLSCertificates {
+		if input.TLSCACert == "" {
+			input.TLSCACert = stored.TLSCACert
+		}
+		if input.TLSClientCert == "" {
+			input.TLSClientCert = stored.TLSClientCert
+		}
+	}
+	return input, nil
+}
+
+// Fungsi helper untuk membandingkan host, port, authSource dan authMechanism input dengan yang tersimpan.
+// Nilai default diterapkan sama seperti di applyConnection.
+func sameServer(existing models.Project, input models.ProjectInput) bool {
+	port, authSource := input.DBPort, input.AuthSource
+	if port == "" {
+		port = "27017"
+	}
+	if authSource == "" {
+		authSource = "admin"
+	}
+	hosts := []string{}
+	for _, host := range input.DBHosts {
+		if host = strings.TrimSpace(host); host != "" {
+			hosts = append(hosts, host)
+		}
+	}
+	return input.DBHost == existing.DBHost &&
+		port == existing.DBPort &&
+		slices.Equal(hosts, existing.DBHosts) &&
+		authSource == existing.AuthSource &&
+		input.AuthMechanism == existing.AuthMechanism
+}
+
+// Fungsi helper untuk memeriksa koneksi ke database user sebelum detailnya dipakai atau disimpan
+func (s *ProjectService) check(ctx context.Context, project models.Project, plain database.ConnectionSecrets) error {
+	conn, err := database.BuildConnection(project, plain)
+	if err != nil {
+		return apierror.Internal(err, "Failed to prepare the database connection")
+	}
+	return s.checkConnection(ctx, conn)
+}
+
+// Fungsi helper untuk mengenkripsi password, connection string dan sertifikat sebelum proyek disimpan
+func sealSecrets(project *models.Project, plain database.ConnectionSecrets) error {
+	for _, field := range []struct {
+		value  string
+		target *string
+	}{
+		{plain.DBPassword, &project.DBPassword},
+		{plain.ConnectionString, &project.ConnectionString},
+		{plain.TLSCACert, &project.TLSCACert},
+		{plain.TLSClientCert, &project.TLSClientCert},
+	} {
+		if field.value == "" {
+			continue
+		}
+		encrypted, err := encryptSecret(field.value)
+		if err != nil {
+			return err
+		}
+		*field.target = encrypted
+	}
+	return nil
+}
+
+// Fungsi helper untuk mengenkripsi rahasia proyek dengan ENCRYPTION_KEY server
+func encryptSecret(value string) (string, error) {
+	encrypted, err := secrets.Encrypt([]byte(value))
+	if errors.Is(err, secrets.ErrNoKey) {
+		return "", apierror.Internal(err, "ENCRYPTION_KEY must be configured on the server to store database passwords, connection strings and certificates")
+	}
+	if err != nil {
+		return "", apierror.Internal(err, "Failed to encrypt connection details")
+	}
+	return encrypted, nil
+}
+
+func generateAPIKey() (string, error) {
+	bytes := make([]byte, 16)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(bytes), nil
+}
@@ -0,0 +1,142 @@
+// file: services/revisions.go
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/fiber-mongo/starter-kit/models"     // Sesuaikan dengan path modulmu
+	"github.com/fiber-mongo/starter-kit/realtime"   // Sesuaikan dengan path modulmu
+	"github.com/fiber-mongo/starter-kit/repository" // Sesuaikan dengan path modulmu
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Revisions mengembalikan koleksi bayangan tempat revisi dokumen koleksi ini disimpan
+func (s *DocumentScope) Revisions() repository.Collection {
+	return s.revisions
+}
+
+// Fungsi helper untuk mencatat revisi baru setelah penulisan (jika revisi aktif untuk koleksi ini).
+// snapshot adalah isi dokumen setelah perubahan; untuk delete berisi dokumen terakhir sebelum dihapus.
+// Jika dokumen belum punya riwayat, isi sebelumnya (before) dicatat dulu sebagai revisi "baseline".
+func (s *DocumentScope) recordRevision(ctx context.Context, operation string, documentID interface{}, before, snapshot bson.M) {
+	if !s.Settings.Revisions || snapshot == nil {
+		return
+	}
+
+	next, err := s.nextRevision(ctx, documentID)
+	if err != nil {
+		slog.ErrorContext(ctx, "revisions: failed to allocate revision number", "documentId", documentID, "error", err)
+		return
+	}
+
+	now := time.Now()
+	docs := []interface{}{}
+	if next == 1 && before != nil {
+		docs = append(docs, models.DocumentRevision{
+			DocumentID: documentID, Revision: next, Operation: models.RevisionBaseline, Snapshot: before, CreatedAt: now,
+		})
+		if next, err = s.nextRevision(ctx, documentID); err != nil {
+			slog.ErrorContext(ctx, "revisions: failed to allocate revision number", "documentId", documentID, "error", err)
+			return
+		}
+	}
+	docs = append(docs, models.DocumentRevision{
+		DocumentID: documentID, Revision: next, Operation: operation, Snapshot: snapshot, Actor: s.Actor, CreatedAt: now,
+	})
+
+	if _, err := s.revisions.InsertMany(ctx, docs); err != nil {
+		slog.ErrorContext(ctx, "revisions: failed to record revision", "documentId", documentID, "error", err)
+	}
+}
+
+// Fungsi helper untuk mengambil nomor revisi berikutnya sebuah dokumen secara atomik ($inc pada dokumen counter),
+// supaya update yang bersamaan tidak mendapat nomor yang sama. Counter disimpan di koleksi revisi yang sama
+// dengan _id "seq:<documentId>" (tanpa field documentId, jadi tidak ikut terbaca sebagai revisi).
+func (s *DocumentScope) nextRevision(ctx context.Context, documentID interface{}) (int, error) {
+	counterID := "seq:" + revisionDocumentKey(documentID)
+	for attempt := 0; attempt < 2; attempt++ {
+		var counter struct {
+			Seq int `bson:"seq"`
+		}
+		err := s.revisions.FindOneAndUpdate(ctx, bson.M{"_id": counterID}, bson.M{"$inc": bson.M{"seq": 1}},
+			options.FindOneAndUpdate().SetReturnDocument(options.After),
+		).Decode(&counter)
+		if err == nil {
+			return counter.Seq, nil
+		}
+		if err != mongo.ErrNoDocuments {
+			return 0, err
+		}
+
+		// Counter belum ada: mulai dari revisi terakhir yang sudah tersimpan (riwayat dari sebelum counter dipakai).
+		// Jika request lain membuat counter lebih dulu, insert ini gagal dengan duplicate key dan $inc diulang.
+		var latest models.DocumentRevision
+		err = s.revisions.FindOne(ctx, bson.M{"documentId": documentID},
+			options.FindOne().SetSort(bson.M{"revision": -1}).SetProjection(bson.M{"revision": 1}),
+		).Decode(&latest)
+		if err != nil && err != mongo.ErrNoDocuments {
+			return 0, err
+		}
+		if _, err := s.revisions.InsertOne(ctx, bson.M{"_id": counterID, "seq": latest.Revision}); err != nil && !mongo.IsDuplicateKeyError(err) {
+			return 0, err
+		}
+	}
+	return 0, errors.New("revision counter could not be created")
+}
+
+// Fungsi helper untuk membuat key counter dari _id dokumen
+func revisionDocumentKey(documentID interface{}) string {
+	if oid, ok := documentID.(primitive.ObjectID); ok {
+		return oid.Hex()
+	}
+	return fmt.Sprint(documentID)
+}
+
+// RestoreRevision mengembalikan dokumen ke isi sebuah revisi (dokumen yang sudah dihapus dibuat ulang).
+// Mengembalikan false jika revisi tidak ditemukan.
+func (s *DocumentScope) RestoreRevision(ctx context.Context, id primitive.ObjectID, revision int) (bool, error) {
+	var target models.DocumentRevision
+	err := s.revisions.FindOne(ctx, bson.M{"documentId": id, "revision": revision}).Decode(&target)
+	if err == mongo.ErrNoDocuments {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	restored := bson.M{}
+	for k, v := range target.Snapshot {
+		restored[k] = v
+	}
+	restored["_id"] = id
+	delete(restored, models.FieldDeletedAt)
+	delete(restored, models.FieldDeletedBy)
+	if s.Settings.Timestamps {
+		restored[models.FieldUpdatedAt] = time.Now()
+	}
+
+	var before bson.M
+	if err := s.Collection.FindOne(ctx, bson.M{"_id": id}).Decode(&before); err != nil && err != mongo.ErrNoDocuments {
+		return false, err
+	}
+	if _, err := s.Collection.ReplaceOne(ctx, bson.M{"_id": id}, restored, options.Replace().SetUpsert(true)); err != nil {
+		return false, err
+	}
+
+	s.recordRevision(ctx, models.RevisionRestore, id, before, restored)
+	// Bagi subscriber, dokumen yang sebelumnya tidak terlihat (dihapus) muncul kembali sebagai insert
+	operation := realtime.OperationUpdate
+	if _, deleted := before[models.FieldDeletedAt]; before == nil || deleted {
+		operation = realtime.OperationInsert
+	}
+	s.changed(ctx, operation, id, before, restored)
+	return true, nil
+}
@@ -0,0 +1,50 @@
+// file: services/services.go
+package services
+
+import (
+	"sync"
+
+	"github.com/fiber-mongo/starter-kit/repository" // Sesuaikan dengan path modulmu
+)
+
+// Services berisi service yang dipakai handler. Handler tidak mengakses database secara langsung,
+// sehingga test bisa memasang repository memori lewat Set.
+type Services struct {
+	Projects  *ProjectService
+	Documents *DocumentService
+}
+
+// NewMongo menyusun service dengan repository MongoDB (database platform dan database user proyek)
+func NewMongo() Services {
+	projects := repository.NewMongoProjects()
+	return Services{
+		Projects:  NewProjectService(projects, PingMongo),
+		Documents: NewDocumentService(projects, repository.NewMongoDocuments(), PlatformEvents{}),
+	}
+}
+
+var (
+	servicesMu sync.RWMutex
+	current    = NewMongo()
+)
+
+// Set mengganti service yang dipakai oleh handler
+func Set(s Services) {
+	servicesMu.Lock()
+	defer servicesMu.Unlock()
+	current = s
+}
+
+// Projects mengembalikan service proyek yang sedang dipakai
+func Projects() *ProjectService {
+	servicesMu.RLock()
+	defer servicesMu.RUnlock()
+	return current.Projects
+}
+
+// Documents mengembalikan service dokumen yang sedang dipakai
+func Documents() *DocumentService {
+	servicesMu.RLock()
+	defer servicesMu.RUnlock()
+	return current.Documents
+}
@@ -0,0 +1,211 @@
+// file: tracing/tracing.go
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/fiber-mongo/starter-kit/apierror" // Sesuaikan dengan path modulmu
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/event"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	serviceName = "backend-fiber"
+	tracerName  = "github.com/fiber-mongo/starter-kit"
+)
+
+// Nama atribut yang dipakai di span aplikasi
+const (
+	AttrProjectID  = attribute.Key("project.id")
+	AttrCollection = attribute.Key("db.mongodb.collection")
+)
+
+var (
+	providerMu sync.Mutex
+	provider   *sdktrace.TracerProvider
+)
+
+// Setup memasang TracerProvider global. exporter: "otlp" (endpoint dari OTEL_EXPORTER_OTLP_ENDPOINT,
+// default http://localhost:4318), "stdout" untuk testing lokal, atau kosong/"none" untuk mematikan ekspor.
+// Propagasi W3C traceparent/baggage selalu aktif.
+func Setup(ctx context.Context, exporter string) error {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	var spanExporter sdktrace.SpanExporter
+	var err error
+	switch strings.ToLower(exporter) {
+	case "", "none":
+		return nil
+	case "otlp":
+		spanExporter, err = otlptracehttp.New(ctx)
+	case "stdout":
+		spanExporter, err = stdouttrace.New(stdouttrace.WithPrettyPrint())
+	default:
+		return fmt.Errorf("unknown trace exporter %q (use otlp, stdout or none)", exporter)
+	}
+	if err != nil {
+		return err
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(attribute.String("service.name", serviceName)))
+	if err != nil {
+		return err
+	}
+
+	tp := sdktrace.NewTracerProvider(sdktrace.WithBatcher(spanExporter), sdktrace.WithResource(res))
+	otel.SetTracerProvider(tp)
+
+	providerMu.Lock()
+	provider = tp
+	providerMu.Unlock()
+	return nil
+}
+
+// Shutdown mengirim span yang masih tertahan lalu menutup exporter
+func Shutdown(ctx context.Context) error {
+	providerMu.Lock()
+	tp := provider
+	provider = nil
+	providerMu.Unlock()
+	if tp == nil {
+		return nil
+	}
+	return tp.Shutdown(ctx)
+}
+
+// Tracer mengembalikan tracer aplikasi (no-op jika tracing tidak diaktifkan)
+func Tracer() trace.Tracer {
+	return otel.Tracer(tracerName)
+}
+
+// Adapter header fasthttp untuk propagator OpenTelemetry
+type headerCarrier struct {
+	c *fiber.Ctx
+}
+
+func (h headerCarrier) Get(key string) string {
+	return h.c.Get(key)
+}
+
+func (h headerCarrier) Set(key, value string) {
+	h.c.Set(key, value)
+}
+
+func (h headerCarrier) Keys() []string {
+	var keys []string
+	h.c.Request().Header.VisitAll(func(key, _ []byte) {
+		keys = append(keys, string(key))
+	})
+	return keys
+}
+
+// Middleware membuat span server untuk setiap request. Context trace dari header traceparent dipakai
+// sebagai parent, dan span disimpan di UserContext supaya span Mongo di handler menjadi anaknya.
+// Dipasang setelah logging.Middleware supaya request ID tetap ada di context.
+func Middleware(c *fiber.Ctx) error {
+	ctx := otel.GetTextMapPropagator().Extract(c.UserContext(), headerCarrier{c})
+	ctx, span := Tracer().Start(ctx, c.Method()+" "+c.Path(),
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			attribute.String("http.request.method", c.Method()),
+			attribute.String("url.path", c.Path()),
+			attribute.String("client.address", c.IP()),
+		),
+	)
+	defer span.End()
+	c.SetUserContext(ctx)
+
+	err := c.Next()
+
+	status := c.Response().StatusCode()
+	if err != nil {
+		status = apierror.Status(err)
+	}
+	if r := c.Route(); r != nil && r.Path != "" {
+		span.SetName(c.Method() + " " + r.Path)
+		span.SetAttributes(attribute.String("http.route", r.Path))
+	}
+	span.SetAttributes(attribute.Int("http.response.status_code", status))
+	if projectID := c.Params("projectId"); projectID != "" {
+		span.SetAttributes(AttrProjectID.String(projectID))
+	}
+	if collection := c.Params("collectionName"); collection != "" {
+		span.SetAttributes(AttrCollection.String(collection))
+	}
+	if status >= fiber.StatusInternalServerError {
+		span.SetStatus(codes.Error, fiber.ErrInternalServerError.Message)
+	}
+	return err
+}
+
+type commandKey struct {
+	connectionID string
+	requestID    int64
+}
+
+// CommandMonitor membuat monitor perintah driver MongoDB yang mencatat setiap perintah sebagai span
+// anak dari span di context operasi. projectID diisi untuk client database user (kosong untuk platform).
+func CommandMonitor(projectID string) *event.CommandMonitor {
+	var mu sync.Mutex
+	spans := map[commandKey]trace.Span{}
+
+	finish := func(connectionID string, requestID int64, err error) {
+		key := commandKey{connectionID, requestID}
+		mu.Lock()
+		span, ok := spans[key]
+		delete(spans, key)
+		mu.Unlock()
+		if !ok {
+			return
+		}
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}
+
+	return &event.CommandMonitor{
+		Started: func(ctx context.Context, e *event.CommandStartedEvent) {
+			attrs := []attribute.KeyValue{
+				attribute.String("db.system", "mongodb"),
+				attribute.String("db.namespace", e.DatabaseName),
+				attribute.String("db.operation.name", e.CommandName),
+			}
+			// Nilai elemen pertama perintah (misalnya {"find": "users"}) adalah nama koleksi
+			if elements, err := e.Command.Elements(); err == nil && len(elements) > 0 {
+				if collection, ok := elements[0].Value().StringValueOK(); ok {
+					attrs = append(attrs, AttrCollection.String(collection))
+				}
+			}
+			if projectID != "" {
+				attrs = append(attrs, AttrProjectID.String(projectID))
+			}
+			_, span := Tracer().Start(ctx, "mongo."+e.CommandName,
+				trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+
+			mu.Lock()
+			spans[commandKey{e.ConnectionID, e.RequestID}] = span
+			mu.Unlock()
+		},
+		Succeeded: func(ctx context.Context, e *event.CommandSucceededEvent) {
+			finish(e.ConnectionID, e.RequestID, nil)
+		},
+		Failed: func(ctx context.Context, e *event.CommandFailedEvent) {
+			finish(e.ConnectionID, e.RequestID, fmt.Errorf("%s", e.Failure))
+		},
+	}
+}
@@ -0,0 +1,297 @@
+// file: usage/usage.go
+package usage
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/fiber-mongo/starter-kit/database" // Sesuaikan dengan nama modul Anda
+	"github.com/fiber-mongo/starter-kit/models"   // Sesuaikan dengan nama modul Anda
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	statsCollectionName = "usage_stats"
+
+	// Statistik dikumpulkan di memori lalu ditulis ke database secara berkala dengan $inc
+	flushInterval   = 10 * time.Second
+	storageInterval = time.Hour
+	dbTimeout       = 30 * time.Second
+)
+
+// Satu request Data API yang sudah selesai
+type Sample struct {
+	ProjectID  primitive.ObjectID
+	Collection string
+	APIKeyID   string
+	Status     int
+	Latency    time.Duration
+	BytesIn    int64
+	BytesOut   int64
+	Time       time.Time
+}
+
+// Fungsi yang membaca ukuran storage semua proyek lalu memanggil RecordStorage (dijalankan tiap jam)
+type StorageSampler func(ctx context.Context)
+
+type statsKey struct {
+	projectID   primitive.ObjectID
+	collection  string
+	apiKeyID    string
+	granularity string
+	bucket      time.Time
+}
+
+type counters struct {
+	requests     int64
+	clientErrors int64
+	serverErrors int64
+	bytesIn      int64
+	bytesOut     int64
+	latencyMsSum float64
+	latency      map[string]int64
+}
+
+var (
+	mu      sync.Mutex
+	pending = map[statsKey]*counters{}
+
+	wg                 sync.WaitGroup
+	stopCh             = make(chan struct{})
+	stopOnce           sync.Once
+	baseCtx, cancelAll = context.WithCancel(context.Background()) // Membatalkan sampler storage saat Stop
+)
+
+// BucketStart mengembalikan awal bucket (UTC) untuk sebuah waktu
+func BucketStart(t time.Time, granularity string) time.Time {
+	t = t.UTC()
+	if granularity == models.UsageDaily {
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	}
+	return t.Truncate(time.Hour)
+}
+
+// LatencyBucket mengembalikan nama bucket histogram untuk sebuah latency ("le_<ms>" atau "inf")
+func LatencyBucket(latency time.Duration) string {
+	ms := latency.Milliseconds()
+	for _, bound := range models.UsageLatencyBoundsMs {
+		if ms <= bound {
+			return "le_" + strconv.FormatInt(bound, 10)
+		}
+	}
+	return "inf"
+}
+
+// Record menambahkan satu request ke statistik bucket jam dan hari. Data baru tersimpan di database
+// pada flush berikutnya.
+func Record(s Sample) {
+	bucket := LatencyBucket(s.Latency)
+
+	mu.Lock()
+	defer mu.Unlock()
+	for _, granularity := range []string{models.UsageHourly, models.UsageDaily} {
+		key := statsKey{
+			projectID:   s.ProjectID,
+			collection:  s.Collection,
+			apiKeyID:    s.APIKeyID,
+			granularity: granularity,
+			bucket:      BucketStart(s.Time, granularity),
+		}
+		c, ok := pending[key]
+		if !ok {
+			c = &counters{latency: map[string]int64{}}
+			pending[key] = c
+		}
+		c.requests++
+		switch {
+		case s.Status >= 500:
+			c.serverErrors++
+		case s.Status >= 400:
+			c.clientErrors++
+		}
+		c.bytesIn += s.BytesIn
+		c.bytesOut += s.BytesOut
+		c.latencyMsSum += float64(s.Latency) / float64(time.Millisecond)
+		c.latency[bucket]++
+	}
+}
+
+// RecordStorage menyimpan ukuran data sebuah koleksi ke bucket jam dan hari saat ini
+func RecordStorage(ctx context.Context, projectID primitive.ObjectID, collection string, bytes int64, now time.Time) error {
+	var writes []mongo.WriteModel
+	for _, granularity := range []string{models.UsageHourly, models.UsageDaily} {
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{
+				"projectId":      projectID,
+				"collectionName": collection,
+				"apiKeyId":       "",
+				"granularity":    granularity,
+				"bucket":         BucketStart(now, granularity),
+			}).
+			SetUpdate(bson.M{"$set": bson.M{"storageBytes": bytes, "updatedAt": now}}).
+			SetUpsert(true))
+	}
+	_, err := database.GetCollection(statsCollectionName).BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false))
+	return err
+}
+
+// Flush menulis semua statistik yang masih di memori ke database. Jika gagal, statistik dikembalikan
+// ke memori supaya dicoba lagi pada flush berikutnya.
+func Flush(ctx context.Context) error {
+	mu.Lock()
+	batch := pending
+	pending = map[statsKey]*counters{}
+	mu.Unlock()
+	if len(batch) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	writes := make([]mongo.WriteModel, 0, len(batch))
+	keys := make([]statsKey, 0, len(batch)) // keys[i] adalah key untuk writes[i]
+	for key, c := range batch {
+		inc := bson.M{
+			"requests":     c.requests,
+			"clientErrors": c.clientErrors,
+			"serverErrors": c.serverErrors,
+			"bytesIn":      c.bytesIn,
+			"bytesOut":     c.bytesOut,
+			"latencyMsSum": c.latencyMsSum,
+		}
+		for bucket, n := range c.latency {
+			inc["latency."+bucket] = n
+		}
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{
+				"projectId":      key.projectID,
+				"collectionName": key.collection,
+				"apiKeyId":       key.apiKeyID,
+				"granularity":    key.granularity,
+				"bucket":         key.bucket,
+			}).
+			SetUpdate(bson.M{"$inc": inc, "$set": bson.M{"updatedAt": now}}).
+			SetUpsert(true))
+		keys = append(keys, key)
+	}
+
+	if _, err := database.GetCollection(statsCollectionName).BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false)); err != nil {
+		mu.Lock()
+		for _, i := range failedWrites(err, len(writes)) {
+			merge(keys[i], batch[keys[i]])
+		}
+		mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// Fungsi helper untuk menentukan index write yang perlu dicoba lagi setelah BulkWrite gagal.
+// Jika server melaporkan write mana saja yang gagal, hanya write tersebut yang dikembalikan supaya
+// write yang sudah berhasil tidak dihitung dua kali. Untuk error lain (misalnya koneksi terputus atau
+// write concern error) tidak diketahui mana yang sudah tersimpan; semuanya dicoba lagi, karena menghitung
+// dua kali lebih baik daripada kehilangan statistik.
+func failedWrites(err error, n int) []int {
+	var bulkErr mongo.BulkWriteException
+	if errors.As(err, &bulkErr) && bulkErr.WriteConcernError == nil && len(bulkErr.WriteErrors) > 0 {
+		indexes := make([]int, 0, len(bulkErr.WriteErrors))
+		for _, writeErr := range bulkErr.WriteErrors {
+			if writeErr.Index >= 0 && writeErr.Index < n {
+				indexes = append(indexes, writeErr.Index)
+			}
+		}
+		return indexes
+	}
+	indexes := make([]int, n)
+	for i := range indexes {
+		indexes[i] = i
+	}
+	return indexes
+}
+
+// Fungsi helper untuk menggabungkan counter kembali ke pending. Dipanggil dengan mu terkunci.
+func merge(key statsKey, c *counters) {
+	existing, ok := pending[key]
+	if !ok {
+		pending[key] = c
+		return
+	}
+	existing.requests += c.requests
+	existing.clientErrors += c.clientErrors
+	existing.serverErrors += c.serverErrors
+	existing.bytesIn += c.bytesIn
+	existing.bytesOut += c.bytesOut
+	existing.latencyMsSum += c.latencyMsSum
+	for bucket, n := range c.latency {
+		existing.latency[bucket] += n
+	}
+}
+
+// Start menjalankan goroutine yang menulis statistik secara berkala dan (jika sampler diisi)
+// mencatat ukuran storage setiap jam
+func Start(sampler StorageSampler) {
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(flushInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stopCh:
+				return
+			case <-ticker.C:
+				ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+				if err := Flush(ctx); err != nil {
+					slog.Error("usage: flush failed", "error", err)
+				}
+				cancel()
+			}
+		}
+	}()
+
+	if sampler == nil {
+		return
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			ctx, cancel := context.WithTimeout(baseCtx, storageInterval/2)
+			sampler(ctx)
+			cancel()
+
+			select {
+			case <-stopCh:
+				return
+			case <-time.After(storageInterval):
+			}
+		}
+	}()
+}
+
+// Stop menghentikan goroutine statistik lalu menulis statistik yang tersisa di memori
+func Stop(ctx context.Context) error {
+	stopOnce.Do(func() {
+		close(stopCh)
+		cancelAll()
+	})
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return Flush(ctx)
+}
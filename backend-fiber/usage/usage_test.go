@@ -0,0 +1,38 @@
+// file: usage/usage_test.go
+package usage
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestFailedWrites(t *testing.T) {
+	writeErrors := func(indexes ...int) []mongo.BulkWriteError {
+		out := []mongo.BulkWriteError{}
+		for _, i := range indexes {
+			out = append(out, mongo.BulkWriteError{WriteError: mongo.WriteError{Index: i, Code: 11000}})
+		}
+		return out
+	}
+
+	tests := []struct {
+		name string
+		err  error
+		want []int
+	}{
+		{"write errors", mongo.BulkWriteException{WriteErrors: writeErrors(1, 3)}, []int{1, 3}},
+		{"out of range index", mongo.BulkWriteException{WriteErrors: writeErrors(2, 9)}, []int{2}},
+		{"write concern error", mongo.BulkWriteException{WriteErrors: writeErrors(1), WriteConcernError: &mongo.WriteConcernError{Code: 64}}, []int{0, 1, 2, 3}},
+		{"network error", errors.New("connection reset"), []int{0, 1, 2, 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := failedWrites(tt.err, 4); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("failedWrites = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+// file: webhooks/destination.go
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+// ErrBlockedDestination dikembalikan jika URL webhook mengarah ke alamat internal
+var ErrBlockedDestination = errors.New("webhook url must not point to a loopback, private, link-local or metadata address")
+
+// Rentang alamat tambahan yang tidak tercakup oleh method netip.Addr di bawah
+var blockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),     // "this network"
+	netip.MustParsePrefix("100.64.0.0/10"), // Carrier-grade NAT
+	netip.MustParsePrefix("192.0.0.0/24"),  // IETF protocol assignments
+	netip.MustParsePrefix("198.18.0.0/15"), // Benchmarking
+}
+
+// Fungsi helper untuk menentukan apakah alamat tujuan webhook harus ditolak
+func blockedAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if addr.IsLoopback() || addr.IsPrivate() || addr.IsUnspecified() ||
+		addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() || addr.IsInterfaceLocalMulticast() || addr.IsMulticast() {
+		return true // Termasuk 169.254.169.254 (metadata cloud) dan fd00:ec2::254
+	}
+	for _, prefix := range blockedPrefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateURL memastikan URL webhook absolut (http/https) dan host-nya tidak mengarah ke alamat internal.
+// Alamat diperiksa lagi saat koneksi dibuat (lihat safeDialer), karena DNS bisa berubah setelah webhook disimpan.
+func ValidateURL(ctx context.Context, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return errors.New("url must be an absolute http or https URL")
+	}
+
+	host := u.Hostname()
+	if addr, err := netip.ParseAddr(host); err == nil {
+		if blockedAddr(addr) {
+			return ErrBlockedDestination
+		}
+		return nil
+	}
+
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return fmt.Errorf("url host '%s' could not be resolved", host)
+	}
+	for _, addr := range addrs {
+		if blockedAddr(addr) {
+			return ErrBlockedDestination
+		}
+	}
+	return nil
+}
+
+// Dialer yang menolak koneksi ke alamat internal setelah DNS di-resolve (mencegah DNS rebinding)
+var safeDialer = &net.Dialer{
+	Timeout:   requestTimeout,
+	KeepAlive: 30 * time.Second,
+	Control: func(network, address string, _ syscall.RawConn) error {
+		addrPort, err := netip.ParseAddrPort(address)
+		if err != nil {
+			return err
+		}
+		if blockedAddr(addrPort.Addr()) {
+			return ErrBlockedDestination
+		}
+		return nil
+	},
+}
+
+// Fungsi helper untuk membuat HTTP client pengiriman webhook. Proxy dari environment tidak dipakai
+// supaya alamat yang diperiksa safeDialer adalah alamat tujuan sebenarnya.
+func newHTTPClient() *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = safeDialer.DialContext
+	return &http.Client{
+		Timeout:   requestTimeout,
+		Transport: transport,
+		// Redirect ke alamat internal tetap ditolak oleh safeDialer
+	}
+}
@@ -0,0 +1,53 @@
+// file: webhooks/destination_test.go
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestValidateURL(t *testing.T) {
+	blocked := []string{
+		"http://127.0.0.1/hook",
+		"http://[::1]:8080/hook",
+		"http://10.1.2.3/hook",
+		"http://172.16.0.1/hook",
+		"http://192.168.1.10/hook",
+		"http://169.254.169.254/latest/meta-data/",
+		"http://[fd00:ec2::254]/",
+		"http://[::ffff:127.0.0.1]/hook",
+		"http://0.0.0.0/hook",
+		"http://100.64.0.1/hook",
+	}
+	for _, u := range blocked {
+		if err := ValidateURL(context.Background(), u); !errors.Is(err, ErrBlockedDestination) {
+			t.Errorf("ValidateURL(%q) = %v, want ErrBlockedDestination", u, err)
+		}
+	}
+
+	for _, u := range []string{"https://203.0.113.10/hook", "http://[2001:db8::1]/hook"} {
+		if err := ValidateURL(context.Background(), u); err != nil {
+			t.Errorf("ValidateURL(%q) = %v, want nil", u, err)
+		}
+	}
+	for _, u := range []string{"ftp://example.com", "/relative", "http://"} {
+		if err := ValidateURL(context.Background(), u); err == nil || errors.Is(err, ErrBlockedDestination) {
+			t.Errorf("ValidateURL(%q) = %v, want invalid url error", u, err)
+		}
+	}
+}
+
+func TestHTTPClientRefusesInternalAddress(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request reached the loopback server")
+	}))
+	defer server.Close()
+
+	_, err := newHTTPClient().Get(server.URL)
+	if !errors.Is(err, ErrBlockedDestination) {
+		t.Fatalf("err = %v, want ErrBlockedDestination", err)
+	}
+}
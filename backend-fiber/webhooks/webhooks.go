@@ -0,0 +1,336 @@
+// file: webhooks/webhooks.go
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/fiber-mongo/starter-kit/database" // Sesuaikan dengan nama modul Anda
+	"github.com/fiber-mongo/starter-kit/models"   // Sesuaikan dengan nama modul Anda
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	webhooksCollectionName   = "webhooks"
+	deliveriesCollectionName = "webhook_deliveries"
+
+	// Percobaan ke-n yang gagal dijadwalkan ulang setelah baseBackoff * 2^(n-1), maksimal maxBackoff
+	maxAttempts = 8
+	baseBackoff = 10 * time.Second
+	maxBackoff  = time.Hour
+
+	requestTimeout    = 10 * time.Second
+	lockDuration      = 2 * time.Minute // Pengiriman yang macet (misalnya server mati) diambil ulang setelah ini
+	pollInterval      = 5 * time.Second
+	workerCount       = 4
+	maxLoggedAttempts = 20
+	dbTimeout         = 10 * time.Second
+)
+
+var (
+	httpClient = newHTTPClient()
+
+	wg       sync.WaitGroup
+	wake     = make(chan struct{}, 1)
+	stopCh   = make(chan struct{})
+	stopOnce sync.Once
+)
+
+// Isi body yang dikirim ke URL webhook
+type payload struct {
+	DeliveryID string      `json:"deliveryId"`
+	Event      string      `json:"event"`
+	ProjectID  string      `json:"projectId"`
+	Collection string      `json:"collection"`
+	DocumentID interface{} `json:"documentId"`
+	Before     bson.M      `json:"before"` // null untuk create
+	After      bson.M      `json:"after"`  // null untuk delete
+	Timestamp  time.Time   `json:"timestamp"`
+}
+
+// Sign menghitung signature yang dikirim di header X-Webhook-Signature:
+// "sha256=" + hex(HMAC-SHA256(secret, "<timestamp>.<body>")). Penerima sebaiknya juga menolak timestamp yang terlalu lama.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Enqueue membuat satu antrian pengiriman untuk setiap webhook aktif proyek yang melanggan event dan koleksi ini
+func Enqueue(ctx context.Context, projectID primitive.ObjectID, collection, event string, documentID interface{}, before, after bson.M) error {
+	cursor, err := database.GetCollection(webhooksCollectionName).Find(ctx, bson.M{
+		"projectId": projectID,
+		"active":    true,
+		"events":    event,
+		"$or": bson.A{
+			bson.M{"collections": bson.M{"$size": 0}},
+			bson.M{"collections": nil},
+			bson.M{"collections": collection},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	var hooks []models.Webhook
+	if err := cursor.All(ctx, &hooks); err != nil {
+		return err
+	}
+	if len(hooks) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	deliveries := make([]interface{}, 0, len(hooks))
+	for _, hook := range hooks {
+		deliveryID := primitive.NewObjectID()
+		body, err := json.Marshal(payload{
+			DeliveryID: deliveryID.Hex(),
+			Event:      event,
+			ProjectID:  projectID.Hex(),
+			Collection: collection,
+			DocumentID: documentID,
+			Before:     before,
+			After:      after,
+			Timestamp:  now,
+		})
+		if err != nil {
+			return err
+		}
+		deliveries = append(deliveries, models.WebhookDelivery{
+			ID:            deliveryID,
+			WebhookID:     hook.ID,
+			ProjectID:     projectID,
+			Event:         event,
+			Collection:    collection,
+			Payload:       string(body),
+			Status:        models.DeliveryPending,
+			NextAttemptAt: now,
+			Logs:          []models.DeliveryAttempt{},
+			CreatedAt:     now,
+		})
+	}
+
+	if _, err := database.GetCollection(deliveriesCollectionName).InsertMany(ctx, deliveries); err != nil {
+		return err
+	}
+	notify()
+	return nil
+}
+
+// ListDeliveries mengambil log pengiriman terbaru sebuah webhook
+func ListDeliveries(ctx context.Context, projectID, webhookID primitive.ObjectID, limit int64) ([]models.WebhookDelivery, error) {
+	cursor, err := database.GetCollection(deliveriesCollectionName).Find(ctx,
+		bson.M{"projectId": projectID, "webhookId": webhookID},
+		options.Find().SetSort(bson.M{"createdAt": -1}).SetLimit(limit),
+	)
+	if err != nil {
+		return nil, err
+	}
+	deliveries := []models.WebhookDelivery{}
+	err = cursor.All(ctx, &deliveries)
+	return deliveries, err
+}
+
+// Redeliver menjadwalkan ulang sebuah pengiriman (termasuk yang sudah sukses/gagal) untuk dikirim segera.
+// Mengembalikan mongo.ErrNoDocuments jika pengiriman tidak ditemukan atau sedang dikirim.
+func Redeliver(ctx context.Context, projectID, webhookID, deliveryID primitive.ObjectID) error {
+	result, err := database.GetCollection(deliveriesCollectionName).UpdateOne(ctx,
+		bson.M{"_id": deliveryID, "projectId": projectID, "webhookId": webhookID, "status": bson.M{"$ne": models.DeliveryDelivering}},
+		bson.M{
+			"$set":   bson.M{"status": models.DeliveryPending, "attempts": 0, "nextAttemptAt": time.Now()},
+			"$unset": bson.M{"lockedUntil": "", "deliveredAt": ""},
+		},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	notify()
+	return nil
+}
+
+// Start menjalankan worker yang mengirim antrian webhook. Dipanggil sekali saat startup.
+func Start() {
+	for i := 0; i < workerCount; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			worker()
+		}()
+	}
+}
+
+// Stop menghentikan worker dan menunggu pengiriman yang sedang berjalan selesai (atau ctx habis).
+// Antrian yang belum terkirim tetap tersimpan dan dilanjutkan saat server berjalan lagi.
+func Stop(ctx context.Context) error {
+	stopOnce.Do(func() { close(stopCh) })
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func notify() {
+	select {
+	case wake <- struct{}{}:
+	default:
+	}
+}
+
+func worker() {
+	for {
+		select {
+		case <-stopCh:
+			return
+		default:
+		}
+
+		if processNext() {
+			continue
+		}
+
+		select {
+		case <-stopCh:
+			return
+		case <-wake:
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+// Ambil satu pengiriman yang sudah jatuh tempo lalu kirim. Mengembalikan false jika antrian kosong.
+func processNext() bool {
+	deliveries := database.GetCollection(deliveriesCollectionName)
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	now := time.Now()
+	var delivery models.WebhookDelivery
+	err := deliveries.FindOneAndUpdate(ctx,
+		bson.M{"$or": bson.A{
+			bson.M{"status": models.DeliveryPending, "nextAttemptAt": bson.M{"$lte": now}},
+			bson.M{"status": models.DeliveryDelivering, "lockedUntil": bson.M{"$lt": now}},
+		}},
+		bson.M{"$set": bson.M{"status": models.DeliveryDelivering, "lockedUntil": now.Add(lockDuration)}},
+		options.FindOneAndUpdate().SetSort(bson.M{"nextAttemptAt": 1}).SetReturnDocument(options.After),
+	).Decode(&delivery)
+	if err != nil {
+		if err != mongo.ErrNoDocuments {
+			slog.Error("webhooks: failed to claim delivery", "error", err)
+		}
+		return false
+	}
+
+	var hook models.Webhook
+	err = database.GetCollection(webhooksCollectionName).FindOne(ctx, bson.M{"_id": delivery.WebhookID, "active": true}).Decode(&hook)
+	if err != nil {
+		finish(delivery, models.DeliveryAttempt{At: now, Error: "webhook deleted or disabled"}, false, true)
+		return true
+	}
+
+	attempt := send(hook, delivery)
+	success := attempt.Error == "" && attempt.StatusCode >= 200 && attempt.StatusCode < 300
+	finish(delivery, attempt, success, delivery.Attempts+1 >= maxAttempts)
+	return true
+}
+
+func send(hook models.Webhook, delivery models.WebhookDelivery) models.DeliveryAttempt {
+	start := time.Now()
+	attempt := models.DeliveryAttempt{At: start}
+
+	body := []byte(delivery.Payload)
+	req, err := http.NewRequest(http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt
+	}
+	timestamp := start.Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "fiber-mongo-starter-kit-webhooks/1.0")
+	req.Header.Set("X-Webhook-ID", delivery.ID.Hex())
+	req.Header.Set("X-Webhook-Event", delivery.Event)
+	req.Header.Set("X-Webhook-Timestamp", strconv.FormatInt(timestamp, 10))
+	req.Header.Set("X-Webhook-Signature", Sign(hook.Secret, timestamp, body))
+
+	resp, err := httpClient.Do(req)
+	attempt.DurationMs = time.Since(start).Milliseconds()
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	attempt.StatusCode = resp.StatusCode
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		attempt.Error = fmt.Sprintf("unexpected status %d", resp.StatusCode)
+	}
+	return attempt
+}
+
+// Simpan hasil percobaan: sukses, gagal permanen, atau dijadwalkan ulang dengan exponential backoff
+func finish(delivery models.WebhookDelivery, attempt models.DeliveryAttempt, success, final bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	attempts := delivery.Attempts + 1
+	set := bson.M{"attempts": attempts}
+	switch {
+	case success:
+		set["status"] = models.DeliverySucceeded
+		set["deliveredAt"] = attempt.At
+	case final:
+		set["status"] = models.DeliveryFailed
+	default:
+		set["status"] = models.DeliveryPending
+		set["nextAttemptAt"] = time.Now().Add(backoff(attempts))
+	}
+
+	_, err := database.GetCollection(deliveriesCollectionName).UpdateOne(ctx, bson.M{"_id": delivery.ID}, bson.M{
+		"$set":   set,
+		"$unset": bson.M{"lockedUntil": ""},
+		"$push":  bson.M{"logs": bson.M{"$each": bson.A{attempt}, "$slice": -maxLoggedAttempts}},
+	})
+	if err != nil {
+		slog.Error("webhooks: failed to update delivery", "deliveryId", delivery.ID.Hex(), "error", err)
+	}
+}
+
+func backoff(attempts int) time.Duration {
+	delay := baseBackoff
+	for i := 1; i < attempts && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > maxBackoff {
+		delay = maxBackoff
+	}
+	return delay
+}
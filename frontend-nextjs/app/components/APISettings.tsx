@@ -24,6 +24,12 @@ export default function APISettings({ project, onSettingsSave }: { project: any,
   const [newCollectionName, setNewCollectionName] = useState('');
   const [schemaFields, setSchemaFields] = useState<SchemaField[]>([{ id: Date.now(), name: '', type: 'string', required: false }]);
   const [isCreating, setIsCreating] = useState(false);
+  // Nama koleksi yang sedang diedit (null berarti modal dalam mode "create")
+  const [editingCollection, setEditingCollection] = useState<string | null>(null);
+  const [validationLevel, setValidationLevel] = useState('strict');
+  const [validationAction, setValidationAction] = useState('error');
+  // Schema mentah (JSON) untuk validator yang tidak bisa diedit lewat schema builder (null berarti pakai builder)
+  const [rawSchema, setRawSchema] = useState<string | null>(null);
 
   // State untuk Modal "How to use"
   const [isUsageModalOpen, setIsUsageModalOpen] = useState(false);
@@ -38,7 +44,7 @@ export default function APISettings({ project, onSettingsSave }: { project: any,
       const response = await fetch(`http://localhost:8080/api/v1/projects/${project.id}/collections`);
       const data = await response.json();
       if (!response.ok) throw new Error(data.error || 'Failed to fetch collections');
-      setAllCollections(data);
+      setAllCollections(data.map((collection: { name: string }) => collection.name));
     } catch (err: any) {
       setError(err.message);
     } finally {
@@ -109,14 +115,85 @@ export default function APISettings({ project, onSettingsSave }: { project: any,
     setSchemaFields(fields => fields.filter(f => f.id !== id));
   };
 
+  const openCreateModal = () => {
+    setEditingCollection(null);
+    setNewCollectionName('');
+    setSchemaFields([{ id: Date.now(), name: '', type: 'string', required: false }]);
+    setValidationLevel('strict');
+    setValidationAction('error');
+    setRawSchema(null);
+    setIsCreateModalOpen(true);
+  };
+
+  const openEditModal = async (collectionName: string) => {
+    try {
+      const response = await fetch(`http://localhost:8080/api/v1/projects/${project.id}/collections/${collectionName}/schema`);
+      const data = await response.json();
+      if (!response.ok) throw new Error(data.error || 'Failed to fetch collection schema');
+      // Validator selain $jsonSchema tidak bisa disimpan ulang dari sini sama sekali
+      if (data.editable === false && !data.schema) {
+        throw new Error(`The validator of '${collectionName}' is not a $jsonSchema and cannot be edited here.`);
+      }
+
+      const fields: SchemaField[] = (data.fields || []).map((f: Omit<SchemaField, 'id'>, i: number) => ({ id: Date.now() + i, ...f }));
+      setEditingCollection(collectionName);
+      setNewCollectionName(collectionName);
+      setSchemaFields(fields.length > 0 ? fields : [{ id: Date.now(), name: '', type: 'string', required: false }]);
+      setValidationLevel(data.validationLevel || 'strict');
+      setValidationAction(data.validationAction || 'error');
+      // Schema dengan properti bersarang, enum, batas nilai, dll. diedit sebagai JSON supaya tidak ada yang hilang
+      setRawSchema(data.editable === false ? JSON.stringify(data.schema, null, 2) : null);
+      setIsCreateModalOpen(true);
+    } catch (err: any) {
+      alert(`Error: ${err.message}`);
+    }
+  };
+
+  // Tebak schema dari sampel dokumen lalu terapkan dengan satu klik
+  const handleInferSchema = async (collectionName: string) => {
+    try {
+      const inferResponse = await fetch(`http://localhost:8080/api/v1/projects/${project.id}/collections/${collectionName}/schema/infer`);
+      const inferred = await inferResponse.json();
+      if (!inferResponse.ok) throw new Error(inferred.error || 'Failed to infer schema');
+
+      const summary = inferred.fields
+        .filter((f: any) => !f.path.includes('.') && !f.path.includes('[]'))
+        .map((f: any) => `${f.path}: ${Object.keys(f.types).join(' | ')} (${f.frequency}%)`)
+        .join('\n');
+      if (!window.confirm(`Inferred from ${inferred.sampledDocuments} sampled documents:\n\n${summary}\n\nApply this schema to '${collectionName}'?`)) return;
+
+      const response = await fetch(`http://localhost:8080/api/v1/projects/${project.id}/collections/${collectionName}`, {
+        method: 'PUT',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify({ schema: inferred.schema }),
+      });
+      const data = await response.json();
+      if (!response.ok) throw new Error(data.error || 'Failed to apply schema');
+      alert(data.message);
+      fetchAllCollections();
+    } catch (err: any) {
+      alert(`Error: ${err.message}`);
+    }
+  };
+
   const handleCreateCollection = async () => {
     if (!newCollectionName) {
       alert('Collection name is required.');
       return;
     }
+
+    let parsedRawSchema: any = null;
+    if (rawSchema !== null) {
+      try {
+        parsedRawSchema = JSON.parse(rawSchema);
+      } catch {
+        alert('Schema must be valid JSON.');
+        return;
+      }
+    }
     setIsCreating(true);
 
-    const schema = {
+    const builtSchema = {
       bsonType: "object",
       properties: schemaFields.reduce((acc, field) => {
         if (field.name) {
@@ -127,22 +204,35 @@ export default function APISettings({ project, onSettingsSave }: { project: any,
       required: schemaFields.filter(f => f.required && f.name).map(f => f.name)
     };
 
+    const hasSchema = parsedRawSchema !== null || builtSchema.required.length > 0 || Object.keys(builtSchema.properties).length > 0;
+    const schema = parsedRawSchema ?? builtSchema;
+
     try {
-      const response = await fetch(`http://localhost:8080/api/v1/projects/${project.id}/collections`, {
-        method: 'POST',
-        headers: { 'Content-Type': 'application/json' },
-        body: JSON.stringify({
-          collectionName: newCollectionName,
-          schema: (schema.required.length > 0 || Object.keys(schema.properties).length > 0) ? schema : {}
-        }),
-      });
+      const response = editingCollection
+        ? await fetch(`http://localhost:8080/api/v1/projects/${project.id}/collections/${editingCollection}`, {
+            method: 'PUT',
+            headers: { 'Content-Type': 'application/json' },
+            body: JSON.stringify({ schema: hasSchema ? schema : {}, validationLevel, validationAction }),
+          })
+        : await fetch(`http://localhost:8080/api/v1/projects/${project.id}/collections`, {
+            method: 'POST',
+            headers: { 'Content-Type': 'application/json' },
+            body: JSON.stringify({
+              collectionName: newCollectionName,
+              schema: hasSchema ? schema : {},
+              validationLevel,
+              validationAction,
+            }),
+          });
       const data = await response.json();
-      if (!response.ok) throw new Error(data.error || 'Failed to create collection');
+      if (!response.ok) throw new Error(data.error || 'Failed to save collection');
 
       alert(data.message);
+      setEditingCollection(null);
       setIsCreateModalOpen(false);
       setNewCollectionName('');
       setSchemaFields([{ id: Date.now(), name: '', type: 'string', required: false }]);
+      setRawSchema(null);
       fetchAllCollections(); // Refresh daftar koleksi setelah berhasil
     } catch (err: any) {
       alert(`Error: ${err.message}`);
@@ -169,9 +259,14 @@ export default function APISettings({ project, onSettingsSave }: { project: any,
             <h2 className="text-2xl font-bold">API & Collections</h2>
             <p className="mt-2 text-gray-600">Manage collections and control which are exposed as API endpoints.</p>
         </div>
-        <button onClick={() => setIsCreateModalOpen(true)} className="rounded-md bg-indigo-600 px-4 py-2 font-semibold text-white hover:bg-indigo-700">
-            + New Collection
-        </button>
+        <div className="flex items-center space-x-3">
+            <a href={`http://localhost:8080/api/v1/projects/${project.id}/docs`} target="_blank" rel="noopener noreferrer" className="rounded-md border px-4 py-2 font-semibold text-gray-700 hover:bg-gray-50">
+                API Docs
+            </a>
+            <button onClick={openCreateModal} className="rounded-md bg-indigo-600 px-4 py-2 font-semibold text-white hover:bg-indigo-700">
+                + New Collection
+            </button>
+        </div>
       </div>
 
       {error && <p className="mt-4 font-bold text-red-500">{error}</p>}
@@ -184,7 +279,8 @@ export default function APISettings({ project, onSettingsSave }: { project: any,
               <div className="flex items-center justify-between">
                 <span className="font-mono text-lg">{name}</span>
                 <div className="flex items-center space-x-4">
-                    <button onClick={() => alert('Edit schema feature is coming soon!')} className="text-sm font-semibold text-gray-600 hover:text-blue-600">Edit</button>
+                    <button onClick={() => openEditModal(name)} className="text-sm font-semibold text-gray-600 hover:text-blue-600">Edit</button>
+                    <button onClick={() => handleInferSchema(name)} className="text-sm font-semibold text-gray-600 hover:text-blue-600">Infer</button>
                     <button onClick={() => handleDeleteCollection(name)} className="text-sm font-semibold text-gray-600 hover:text-red-600">Delete</button>
                     <label htmlFor={`toggle-${name}`} className="flex cursor-pointer items-center">
                         <div className="relative">
@@ -230,15 +326,22 @@ export default function APISettings({ project, onSettingsSave }: { project: any,
               <Transition.Child as={Fragment} enter="ease-out duration-300" enterFrom="opacity-0 scale-95" enterTo="opacity-100 scale-100" leave="ease-in duration-200" leaveFrom="opacity-100 scale-100" leaveTo="opacity-0 scale-95">
                 <Dialog.Panel className="w-full max-w-2xl transform overflow-hidden rounded-2xl bg-white p-6 text-left align-middle shadow-xl transition-all">
                   <Dialog.Title as="h3" className="text-lg font-medium leading-6 text-gray-900">
-                    Create New Collection
+                    {editingCollection ? `Edit Schema: ${editingCollection}` : 'Create New Collection'}
                   </Dialog.Title>
                   <div className="mt-4 space-y-4">
                     <div>
                       <label htmlFor="newCollectionName" className="block text-sm font-medium text-gray-700">Collection Name</label>
-                      <input type="text" id="newCollectionName" value={newCollectionName} onChange={(e) => setNewCollectionName(e.target.value)} className="mt-1 block w-full rounded-md border-gray-300 px-3 py-2 shadow-sm" required />
+                      <input type="text" id="newCollectionName" value={newCollectionName} onChange={(e) => setNewCollectionName(e.target.value)} disabled={editingCollection !== null} className="mt-1 block w-full rounded-md border-gray-300 px-3 py-2 shadow-sm disabled:bg-gray-100" required />
                     </div>
                     <hr/>
                     <p className="text-sm font-medium text-gray-700">Define Schema (Optional)</p>
+                    {rawSchema !== null ? (
+                      <div>
+                        <p className="text-xs text-gray-500">This schema uses features the field builder cannot represent (nested properties, enums, constraints, nullable types...). Edit it as JSON.</p>
+                        <textarea value={rawSchema} onChange={(e) => setRawSchema(e.target.value)} rows={14} className="mt-1 block w-full rounded-md border-gray-300 font-mono text-xs" />
+                      </div>
+                    ) : (
+                    <>
                     <div className="space-y-3">
                       {schemaFields.map((field) => (
                         <div key={field.id} className="grid grid-cols-12 items-center gap-2">
@@ -248,10 +351,13 @@ export default function APISettings({ project, onSettingsSave }: { project: any,
                             <option value="string">Image URL (String)</option>
                             <option value="double">Number</option>
                             <option value="int">Integer</option>
+                            <option value="long">Long Integer</option>
+                            <option value="decimal">Decimal</option>
                             <option value="bool">Boolean</option>
                             <option value="objectId">ObjectID (Relation)</option>
                             <option value="date">Date</option>
                             <option value="array">Array</option>
+                            <option value="object">Object</option>
                           </select>
                           <label className="col-span-4 flex items-center justify-center space-x-2">
                             <input type="checkbox" checked={field.required} onChange={(e) => handleFieldChange(field.id, 'required', e.target.checked)} className="rounded" />
@@ -262,11 +368,30 @@ export default function APISettings({ project, onSettingsSave }: { project: any,
                       ))}
                     </div>
                     <button onClick={addField} className="text-sm text-blue-600 hover:underline">+ Add Field</button>
+                    </>
+                    )}
+                    <div className="grid grid-cols-2 gap-4">
+                      <label className="text-sm text-gray-700">
+                        Validation Level
+                        <select value={validationLevel} onChange={(e) => setValidationLevel(e.target.value)} className="mt-1 block w-full rounded-md border-gray-300">
+                          <option value="strict">Strict</option>
+                          <option value="moderate">Moderate</option>
+                          <option value="off">Off</option>
+                        </select>
+                      </label>
+                      <label className="text-sm text-gray-700">
+                        Validation Action
+                        <select value={validationAction} onChange={(e) => setValidationAction(e.target.value)} className="mt-1 block w-full rounded-md border-gray-300">
+                          <option value="error">Error (reject)</option>
+                          <option value="warn">Warn (log only)</option>
+                        </select>
+                      </label>
+                    </div>
                   </div>
                   <div className="mt-6 flex justify-end space-x-4">
                     <button type="button" className="rounded-md bg-gray-100 px-4 py-2 text-gray-700 hover:bg-gray-200" onClick={() => setIsCreateModalOpen(false)}>Cancel</button>
                     <button type="button" className="rounded-md bg-indigo-600 px-4 py-2 text-white hover:bg-indigo-700" onClick={handleCreateCollection} disabled={isCreating}>
-                      {isCreating ? 'Creating...' : 'Create Collection'}
+                      {isCreating ? 'Saving...' : editingCollection ? 'Save Schema' : 'Create Collection'}
                     </button>
                   </div>
                 </Dialog.Panel>
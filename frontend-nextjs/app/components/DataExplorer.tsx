@@ -22,6 +22,7 @@ export default function DataExplorer({ projectId, apiKey, collectionNameFromUrl
   const [isEditing, setIsEditing] = useState(false);
   const [currentDoc, setCurrentDoc] = useState<any>(null);
   const [docContent, setDocContent] = useState('');
+  const [docETag, setDocETag] = useState<string | null>(null); // ETag dokumen yang sedang diedit (untuk If-Match)
 
   // Fungsi untuk mengambil data dari API
   const fetchData = async () => {
@@ -54,6 +55,17 @@ export default function DataExplorer({ projectId, apiKey, collectionNameFromUrl
     fetchData();
   }, [collectionNameFromUrl, projectId]); // Muat ulang data jika URL koleksi berubah
 
+  // Langganan realtime (SSE): muat ulang data setiap ada perubahan dari client mana pun
+  useEffect(() => {
+    if (!projectId || !collectionNameFromUrl || !apiKey) return;
+    const source = new EventSource(
+      `http://localhost:8080/api/v1/realtime/${projectId}/${collectionNameFromUrl}/sse?apiKey=${encodeURIComponent(apiKey)}`
+    );
+    const handleChange = () => fetchData();
+    ['insert', 'update', 'delete'].forEach((type) => source.addEventListener(type, handleChange));
+    return () => source.close();
+  }, [collectionNameFromUrl, projectId, apiKey]);
+
   const handleDelete = async (docId: string) => {
     if (!window.confirm('Are you sure you want to delete this document?')) return;
     try {
@@ -77,12 +89,27 @@ export default function DataExplorer({ projectId, apiKey, collectionNameFromUrl
     setIsModalOpen(true);
   };
   
-  const openEditModal = (doc: any) => {
+  const openEditModal = async (doc: any) => {
     setIsEditing(true);
     setCurrentDoc(doc);
+    setDocETag(null);
     const { _id, ...editableDoc } = doc;
     setDocContent(JSON.stringify(editableDoc, null, 2));
     setIsModalOpen(true);
+
+    // Ambil versi terbaru dokumen beserta ETag-nya agar update tidak menimpa perubahan orang lain
+    try {
+      const response = await fetch(`http://localhost:8080/api/v1/data/${projectId}/${collectionNameFromUrl}/${doc._id}`, {
+        headers: { 'X-API-Key': apiKey },
+      });
+      if (response.ok) {
+        const { _id: latestId, ...latestDoc } = await response.json();
+        setDocContent(JSON.stringify(latestDoc, null, 2));
+        setDocETag(response.headers.get('ETag'));
+      }
+    } catch {
+      // Tetap bisa mengedit tanpa ETag
+    }
   };
   
   const handleSave = async () => {
@@ -97,13 +124,24 @@ export default function DataExplorer({ projectId, apiKey, collectionNameFromUrl
       method = 'POST';
     }
     
+    const requestHeaders: Record<string, string> = { 'Content-Type': 'application/json', 'X-API-Key': apiKey };
+    if (isEditing && docETag) {
+      requestHeaders['If-Match'] = docETag;
+    }
+
     try {
       JSON.parse(docContent); // Validasi JSON sebelum mengirim
       const response = await fetch(url, {
         method: method,
-        headers: { 'Content-Type': 'application/json', 'X-API-Key': apiKey },
+        headers: requestHeaders,
         body: docContent,
       });
+      if (response.status === 412) {
+        alert('This document was changed by someone else. Reload it and try again.');
+        setIsModalOpen(false);
+        fetchData();
+        return;
+      }
       const data = await response.json();
       if (!response.ok) throw new Error(data.error || 'Save failed');
       
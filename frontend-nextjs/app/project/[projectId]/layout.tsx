@@ -20,7 +20,7 @@ export default function ProjectLayout({ children }: { children: React.ReactNode
         const response = await fetch(`http://localhost:8080/api/v1/projects/${projectId}/collections`);
         const data = await response.json();
         if (!response.ok) throw new Error(data.error || 'Failed to fetch collections');
-        setCollections(data);
+        setCollections(data.map((collection: { name: string }) => collection.name));
       } catch (error) {
         console.error(error);
       } finally {